@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
@@ -35,6 +36,14 @@ func (src *ClusterProfile) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.ClusterSelector.LabelSelector = metav1.LabelSelector{}
 	}
 
+	// Restore fields v1alpha1 cannot represent, saved by ConvertFrom
+	restored := &configv1beta1.ClusterProfile{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	restoreSpec(&restored.Spec, &dst.Spec)
+	restoreStatus(&restored.Status, &dst.Status)
+
 	return nil
 }
 
@@ -50,5 +59,7 @@ func (dst *ClusterProfile) ConvertFrom(srcRaw conversion.Hub) error {
 		dst.Spec.ClusterSelector = ""
 	}
 
-	return nil
+	// Preserve the v1beta1 object, so fields v1alpha1 cannot represent are not lost
+	// on a v1beta1 -> v1alpha1 -> v1beta1 round trip
+	return utilconversion.MarshalData(src, dst)
 }
@@ -19,13 +19,16 @@ package v1alpha1_test
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/util"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 
 	configv1alpha1 "github.com/projectsveltos/addon-controller/api/v1alpha1"
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
@@ -103,7 +106,9 @@ var _ = Describe("Conversion", func() {
 			final := &configv1alpha1.ClusterProfile{}
 			Expect(final.ConvertFrom(dst)).To(Succeed())
 
-			Expect(reflect.DeepEqual(final.ObjectMeta, clusterProfile.ObjectMeta)).To(BeTrue())
+			// ConvertFrom saves the v1beta1 object in the conversion data annotation
+			delete(final.Annotations, utilconversion.DataAnnotation)
+			Expect(apiequality.Semantic.DeepEqual(final.ObjectMeta, clusterProfile.ObjectMeta)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.PolicyRefs, clusterProfile.Spec.PolicyRefs)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.KustomizationRefs, clusterProfile.Spec.KustomizationRefs)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.ClusterRefs, clusterProfile.Spec.ClusterRefs)).To(BeTrue())
@@ -204,13 +209,205 @@ var _ = Describe("Conversion", func() {
 			final := &configv1alpha1.Profile{}
 			Expect(final.ConvertFrom(dst)).To(Succeed())
 
-			Expect(reflect.DeepEqual(final.ObjectMeta, profile.ObjectMeta)).To(BeTrue())
+			// ConvertFrom saves the v1beta1 object in the conversion data annotation
+			delete(final.Annotations, utilconversion.DataAnnotation)
+			Expect(apiequality.Semantic.DeepEqual(final.ObjectMeta, profile.ObjectMeta)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.PolicyRefs, profile.Spec.PolicyRefs)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.KustomizationRefs, profile.Spec.KustomizationRefs)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Spec.ClusterRefs, profile.Spec.ClusterRefs)).To(BeTrue())
 			Expect(reflect.DeepEqual(final.Status, profile.Status)).To(BeTrue())
 		})
 	})
+
+	Context("Convert from v1beta1 to v1alpha1", func() {
+		It("ClusterSummary FeatureSummaries conversion", func() {
+			failureMessage := randomString()
+			clusterSummary := &configv1beta1.ClusterSummary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      randomString(),
+					Namespace: randomString(),
+				},
+				Status: configv1beta1.ClusterSummaryStatus{
+					FeatureSummaries: []configv1beta1.FeatureSummary{
+						{
+							FeatureID:      configv1beta1.FeatureResources,
+							Hash:           []byte(randomString()),
+							Status:         configv1beta1.FeatureStatusFailed,
+							FailureMessage: &failureMessage,
+							FailedResources: []configv1beta1.ResourceFailure{
+								{APIVersion: "v1", Kind: "ConfigMap", Namespace: randomString(), Name: randomString()},
+							},
+							DeployedGroupVersionKind: []string{"ConfigMap.v1."},
+						},
+						{
+							FeatureID: configv1beta1.FeatureHelm,
+							Hash:      []byte(randomString()),
+							Status:    configv1beta1.FeatureStatusProvisioned,
+						},
+					},
+				},
+			}
+
+			dst := &configv1alpha1.ClusterSummary{}
+			Expect(dst.ConvertFrom(clusterSummary)).To(Succeed())
+
+			Expect(len(dst.Status.FeatureSummaries)).To(Equal(len(clusterSummary.Status.FeatureSummaries)))
+			for i := range clusterSummary.Status.FeatureSummaries {
+				src := &clusterSummary.Status.FeatureSummaries[i]
+				converted := &dst.Status.FeatureSummaries[i]
+				Expect(string(converted.FeatureID)).To(Equal(string(src.FeatureID)))
+				Expect(converted.Hash).To(Equal(src.Hash))
+				Expect(string(converted.Status)).To(Equal(string(src.Status)))
+				Expect(converted.FailureMessage).To(Equal(src.FailureMessage))
+				Expect(converted.DeployedGroupVersionKind).To(Equal(src.DeployedGroupVersionKind))
+			}
+
+			final := &configv1beta1.ClusterSummary{}
+			Expect(dst.ConvertTo(final)).To(Succeed())
+			Expect(len(final.Status.FeatureSummaries)).To(Equal(len(clusterSummary.Status.FeatureSummaries)))
+			Expect(final.Status.FeatureSummaries[1]).To(Equal(clusterSummary.Status.FeatureSummaries[1]))
+		})
+
+		It("ClusterProfile PolicyRefs conversion", func() {
+			clusterProfile := &configv1beta1.ClusterProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: randomString(),
+				},
+				Spec: configv1beta1.Spec{
+					PolicyRefs: []configv1beta1.PolicyRef{
+						{
+							Namespace:      randomString(),
+							Name:           randomString(),
+							Kind:           string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+							Version:        "v2",
+							DeploymentType: configv1beta1.DeploymentTypeLocal,
+						},
+						{
+							Namespace: randomString(),
+							Name:      randomString(),
+							Kind:      "GitRepository",
+							Path:      randomString(),
+						},
+					},
+				},
+			}
+
+			dst := &configv1alpha1.ClusterProfile{}
+			Expect(dst.ConvertFrom(clusterProfile)).To(Succeed())
+
+			Expect(len(dst.Spec.PolicyRefs)).To(Equal(len(clusterProfile.Spec.PolicyRefs)))
+			for i := range clusterProfile.Spec.PolicyRefs {
+				src := &clusterProfile.Spec.PolicyRefs[i]
+				converted := &dst.Spec.PolicyRefs[i]
+				Expect(converted.Namespace).To(Equal(src.Namespace))
+				Expect(converted.Name).To(Equal(src.Name))
+				Expect(converted.Kind).To(Equal(src.Kind))
+				Expect(converted.Path).To(Equal(src.Path))
+				Expect(string(converted.DeploymentType)).To(Equal(string(src.DeploymentType)))
+			}
+
+			final := &configv1beta1.ClusterProfile{}
+			Expect(dst.ConvertTo(final)).To(Succeed())
+			Expect(final.Spec.PolicyRefs).To(Equal(clusterProfile.Spec.PolicyRefs))
+		})
+
+		It("ClusterProfile round trip preserves fields v1alpha1 cannot represent", func() {
+			propagationPolicy := metav1.DeletePropagationForeground
+			clusterProfile := &configv1beta1.ClusterProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: randomString(),
+				},
+				Spec: configv1beta1.Spec{
+					AdoptionPolicy:            configv1beta1.AdoptionPolicyFail,
+					RecreateOnImmutableChange: []configv1beta1.FeatureID{configv1beta1.FeatureResources},
+					DeletionPolicies: []configv1beta1.DeletionPolicy{
+						{FeatureID: configv1beta1.FeatureKustomize, PropagationPolicy: &propagationPolicy, WaitForDeletion: true},
+					},
+					NamespaceLabels:   map[string]string{randomString(): randomString()},
+					ReloadConsumers:   true,
+					ResyncPeriod:      &metav1.Duration{Duration: time.Hour},
+					DeploymentTimeout: &metav1.Duration{Duration: time.Minute},
+					HelmCharts: []configv1beta1.HelmChart{
+						{
+							RepositoryURL:    randomString(),
+							RepositoryName:   randomString(),
+							ChartName:        randomString(),
+							ChartVersion:     randomString(),
+							ReleaseName:      randomString(),
+							ReleaseNamespace: randomString(),
+							Options: &configv1beta1.HelmOptions{
+								UninstallOptions: configv1beta1.HelmUninstallOptions{DeleteCRDs: true},
+							},
+						},
+					},
+				},
+				Status: configv1beta1.Status{
+					ObservedGeneration: 3,
+				},
+			}
+
+			spoke := &configv1alpha1.ClusterProfile{}
+			Expect(spoke.ConvertFrom(clusterProfile.DeepCopy())).To(Succeed())
+
+			final := &configv1beta1.ClusterProfile{}
+			Expect(spoke.ConvertTo(final)).To(Succeed())
+			Expect(final.Annotations).ToNot(HaveKey(utilconversion.DataAnnotation))
+
+			Expect(final.Spec.AdoptionPolicy).To(Equal(clusterProfile.Spec.AdoptionPolicy))
+			Expect(final.Spec.RecreateOnImmutableChange).To(Equal(clusterProfile.Spec.RecreateOnImmutableChange))
+			Expect(final.Spec.DeletionPolicies).To(Equal(clusterProfile.Spec.DeletionPolicies))
+			Expect(final.Spec.NamespaceLabels).To(Equal(clusterProfile.Spec.NamespaceLabels))
+			Expect(final.Spec.ReloadConsumers).To(BeTrue())
+			Expect(final.Spec.ResyncPeriod).To(Equal(clusterProfile.Spec.ResyncPeriod))
+			Expect(final.Spec.DeploymentTimeout).To(Equal(clusterProfile.Spec.DeploymentTimeout))
+			Expect(final.Spec.HelmCharts).To(HaveLen(1))
+			Expect(final.Spec.HelmCharts[0].Options).ToNot(BeNil())
+			Expect(final.Spec.HelmCharts[0].Options.UninstallOptions.DeleteCRDs).To(BeTrue())
+			Expect(final.Status.ObservedGeneration).To(Equal(clusterProfile.Status.ObservedGeneration))
+		})
+
+		It("ClusterSummary round trip preserves fields v1alpha1 cannot represent", func() {
+			clusterSummary := &configv1beta1.ClusterSummary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      randomString(),
+					Namespace: randomString(),
+				},
+				Spec: configv1beta1.ClusterSummarySpec{
+					ClusterProfileSpec: configv1beta1.Spec{
+						ResyncPeriod: &metav1.Duration{Duration: time.Hour},
+					},
+				},
+				Status: configv1beta1.ClusterSummaryStatus{
+					FeatureSummaries: []configv1beta1.FeatureSummary{
+						{
+							FeatureID: configv1beta1.FeatureResources,
+							Status:    configv1beta1.FeatureStatusFailed,
+							FailedResources: []configv1beta1.ResourceFailure{
+								{APIVersion: "v1", Kind: "ConfigMap", Namespace: randomString(), Name: randomString()},
+							},
+						},
+					},
+					InvalidReferences: []configv1beta1.InvalidReference{
+						{Kind: "Secret", Namespace: randomString(), Name: randomString(), Reason: configv1beta1.ReferenceNotFound},
+					},
+					Conditions: []metav1.Condition{
+						{Type: randomString(), Status: metav1.ConditionTrue, Reason: randomString()},
+					},
+				},
+			}
+
+			spoke := &configv1alpha1.ClusterSummary{}
+			Expect(spoke.ConvertFrom(clusterSummary.DeepCopy())).To(Succeed())
+
+			final := &configv1beta1.ClusterSummary{}
+			Expect(spoke.ConvertTo(final)).To(Succeed())
+
+			Expect(final.Spec.ClusterProfileSpec.ResyncPeriod).To(Equal(clusterSummary.Spec.ClusterProfileSpec.ResyncPeriod))
+			Expect(final.Status.FeatureSummaries).To(Equal(clusterSummary.Status.FeatureSummaries))
+			Expect(final.Status.InvalidReferences).To(Equal(clusterSummary.Status.InvalidReferences))
+			Expect(final.Status.Conditions).To(Equal(clusterSummary.Status.Conditions))
+		})
+	})
 })
 
 func randomString() string {
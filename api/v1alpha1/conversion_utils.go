@@ -99,3 +99,111 @@ func Convert_v1beta1_HelmUpgradeOptions_To_v1alpha1_HelmUpgradeOptions(
 
 	return nil
 }
+
+func Convert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(
+	src *configv1beta1.ClusterSummaryStatus, dst *ClusterSummaryStatus, s conversion.Scope) error {
+
+	if err := autoConvert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(src, dst, s); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(
+	src *configv1beta1.FeatureSummary, dst *FeatureSummary, s conversion.Scope) error {
+
+	if err := autoConvert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(src, dst, s); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(
+	src *configv1beta1.PolicyRef, dst *PolicyRef, s conversion.Scope) error {
+
+	if err := autoConvert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(src, dst, s); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Convert_v1beta1_Status_To_v1alpha1_Status(
+	src *configv1beta1.Status, dst *Status, s conversion.Scope) error {
+
+	if err := autoConvert_v1beta1_Status_To_v1alpha1_Status(src, dst, s); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// restoreSpec sets on dst the fields v1alpha1.Spec cannot represent, taking them from
+// restored, the v1beta1.Spec saved in the conversion data annotation.
+func restoreSpec(restored, dst *configv1beta1.Spec) {
+	dst.AdoptionPolicy = restored.AdoptionPolicy
+	dst.RecreateOnImmutableChange = restored.RecreateOnImmutableChange
+	dst.DeletionPolicies = restored.DeletionPolicies
+	dst.NamespaceLabels = restored.NamespaceLabels
+	dst.ReloadConsumers = restored.ReloadConsumers
+	dst.ResyncPeriod = restored.ResyncPeriod
+	dst.DeploymentTimeout = restored.DeploymentTimeout
+
+	for i := range dst.PolicyRefs {
+		policyRef := &dst.PolicyRefs[i]
+		for j := range restored.PolicyRefs {
+			if restored.PolicyRefs[j].Kind == policyRef.Kind &&
+				restored.PolicyRefs[j].Namespace == policyRef.Namespace &&
+				restored.PolicyRefs[j].Name == policyRef.Name {
+
+				policyRef.Version = restored.PolicyRefs[j].Version
+				break
+			}
+		}
+	}
+
+	for i := range dst.HelmCharts {
+		helmChart := &dst.HelmCharts[i]
+		for j := range restored.HelmCharts {
+			if restored.HelmCharts[j].RepositoryURL == helmChart.RepositoryURL &&
+				restored.HelmCharts[j].ReleaseNamespace == helmChart.ReleaseNamespace &&
+				restored.HelmCharts[j].ReleaseName == helmChart.ReleaseName {
+
+				if restored.HelmCharts[j].Options != nil &&
+					restored.HelmCharts[j].Options.UninstallOptions.DeleteCRDs {
+
+					if helmChart.Options == nil {
+						helmChart.Options = &configv1beta1.HelmOptions{}
+					}
+					helmChart.Options.UninstallOptions.DeleteCRDs = true
+				}
+				break
+			}
+		}
+	}
+}
+
+// restoreStatus sets on dst the fields v1alpha1.Status cannot represent, taking them from
+// restored, the v1beta1.Status saved in the conversion data annotation.
+func restoreStatus(restored, dst *configv1beta1.Status) {
+	dst.ObservedGeneration = restored.ObservedGeneration
+}
+
+// restoreClusterSummaryStatus sets on dst the fields v1alpha1.ClusterSummaryStatus cannot
+// represent, taking them from restored, the v1beta1.ClusterSummaryStatus saved in the
+// conversion data annotation.
+func restoreClusterSummaryStatus(restored, dst *configv1beta1.ClusterSummaryStatus) {
+	dst.InvalidReferences = restored.InvalidReferences
+	dst.Conditions = restored.Conditions
+
+	for i := range dst.FeatureSummaries {
+		for j := range restored.FeatureSummaries {
+			if restored.FeatureSummaries[j].FeatureID == dst.FeatureSummaries[i].FeatureID {
+				dst.FeatureSummaries[i].FailedResources = restored.FeatureSummaries[j].FailedResources
+				break
+			}
+		}
+	}
+}
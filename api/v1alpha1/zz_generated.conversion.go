@@ -186,11 +186,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.ClusterSummaryStatus)(nil), (*ClusterSummaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(a.(*v1beta1.ClusterSummaryStatus), b.(*ClusterSummaryStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Clusters)(nil), (*v1beta1.Clusters)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Clusters_To_v1beta1_Clusters(a.(*Clusters), b.(*v1beta1.Clusters), scope)
 	}); err != nil {
@@ -236,11 +231,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.FeatureSummary)(nil), (*FeatureSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(a.(*v1beta1.FeatureSummary), b.(*FeatureSummary), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*HelmChart)(nil), (*v1beta1.HelmChart)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_HelmChart_To_v1beta1_HelmChart(a.(*HelmChart), b.(*v1beta1.HelmChart), scope)
 	}); err != nil {
@@ -296,11 +286,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.PolicyRef)(nil), (*PolicyRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(a.(*v1beta1.PolicyRef), b.(*PolicyRef), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Profile)(nil), (*v1beta1.Profile)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Profile_To_v1beta1_Profile(a.(*Profile), b.(*v1beta1.Profile), scope)
 	}); err != nil {
@@ -366,11 +351,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Status)(nil), (*Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_Status_To_v1alpha1_Status(a.(*v1beta1.Status), b.(*Status), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*TemplateResourceRef)(nil), (*v1beta1.TemplateResourceRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TemplateResourceRef_To_v1beta1_TemplateResourceRef(a.(*TemplateResourceRef), b.(*v1beta1.TemplateResourceRef), scope)
 	}); err != nil {
@@ -406,6 +386,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.ClusterSummaryStatus)(nil), (*ClusterSummaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(a.(*v1beta1.ClusterSummaryStatus), b.(*ClusterSummaryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.FeatureSummary)(nil), (*FeatureSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(a.(*v1beta1.FeatureSummary), b.(*FeatureSummary), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.HelmChart)(nil), (*HelmChart)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_HelmChart_To_v1alpha1_HelmChart(a.(*v1beta1.HelmChart), b.(*HelmChart), scope)
 	}); err != nil {
@@ -426,11 +416,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.PolicyRef)(nil), (*PolicyRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(a.(*v1beta1.PolicyRef), b.(*PolicyRef), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.Spec)(nil), (*Spec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Spec_To_v1alpha1_Spec(a.(*v1beta1.Spec), b.(*Spec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.Status)(nil), (*Status)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Status_To_v1alpha1_Status(a.(*v1beta1.Status), b.(*Status), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -838,7 +838,17 @@ func Convert_v1beta1_ClusterSummarySpec_To_v1alpha1_ClusterSummarySpec(in *v1bet
 
 func autoConvert_v1alpha1_ClusterSummaryStatus_To_v1beta1_ClusterSummaryStatus(in *ClusterSummaryStatus, out *v1beta1.ClusterSummaryStatus, s conversion.Scope) error {
 	out.Dependencies = (*string)(unsafe.Pointer(in.Dependencies))
-	out.FeatureSummaries = *(*[]v1beta1.FeatureSummary)(unsafe.Pointer(&in.FeatureSummaries))
+	if in.FeatureSummaries != nil {
+		in, out := &in.FeatureSummaries, &out.FeatureSummaries
+		*out = make([]v1beta1.FeatureSummary, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_FeatureSummary_To_v1beta1_FeatureSummary(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.FeatureSummaries = nil
+	}
 	out.DeployedGVKs = *(*[]v1beta1.FeatureDeploymentInfo)(unsafe.Pointer(&in.DeployedGVKs))
 	out.HelmReleaseSummaries = *(*[]v1beta1.HelmChartSummary)(unsafe.Pointer(&in.HelmReleaseSummaries))
 	return nil
@@ -851,17 +861,24 @@ func Convert_v1alpha1_ClusterSummaryStatus_To_v1beta1_ClusterSummaryStatus(in *C
 
 func autoConvert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(in *v1beta1.ClusterSummaryStatus, out *ClusterSummaryStatus, s conversion.Scope) error {
 	out.Dependencies = (*string)(unsafe.Pointer(in.Dependencies))
-	out.FeatureSummaries = *(*[]FeatureSummary)(unsafe.Pointer(&in.FeatureSummaries))
+	if in.FeatureSummaries != nil {
+		in, out := &in.FeatureSummaries, &out.FeatureSummaries
+		*out = make([]FeatureSummary, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.FeatureSummaries = nil
+	}
 	out.DeployedGVKs = *(*[]FeatureDeploymentInfo)(unsafe.Pointer(&in.DeployedGVKs))
 	out.HelmReleaseSummaries = *(*[]HelmChartSummary)(unsafe.Pointer(&in.HelmReleaseSummaries))
+	// WARNING: in.InvalidReferences requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus is an autogenerated conversion function.
-func Convert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(in *v1beta1.ClusterSummaryStatus, out *ClusterSummaryStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_ClusterSummaryStatus_To_v1alpha1_ClusterSummaryStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_Clusters_To_v1beta1_Clusters(in *Clusters, out *v1beta1.Clusters, s conversion.Scope) error {
 	out.Hash = *(*[]byte)(unsafe.Pointer(&in.Hash))
 	out.Clusters = *(*[]corev1.ObjectReference)(unsafe.Pointer(&in.Clusters))
@@ -970,16 +987,12 @@ func autoConvert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(in *v1beta1.F
 	out.Status = FeatureStatus(in.Status)
 	out.FailureReason = (*string)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.FailedResources requires manual conversion: does not exist in peer-type
 	out.DeployedGroupVersionKind = *(*[]string)(unsafe.Pointer(&in.DeployedGroupVersionKind))
 	out.LastAppliedTime = (*v1.Time)(unsafe.Pointer(in.LastAppliedTime))
 	return nil
 }
 
-// Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary is an autogenerated conversion function.
-func Convert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(in *v1beta1.FeatureSummary, out *FeatureSummary, s conversion.Scope) error {
-	return autoConvert_v1beta1_FeatureSummary_To_v1alpha1_FeatureSummary(in, out, s)
-}
-
 func autoConvert_v1alpha1_HelmChart_To_v1beta1_HelmChart(in *HelmChart, out *v1beta1.HelmChart, s conversion.Scope) error {
 	out.RepositoryURL = in.RepositoryURL
 	out.RepositoryName = in.RepositoryName
@@ -1151,6 +1164,7 @@ func autoConvert_v1beta1_HelmUninstallOptions_To_v1alpha1_HelmUninstallOptions(i
 	out.KeepHistory = in.KeepHistory
 	out.DeletionPropagation = in.DeletionPropagation
 	// WARNING: in.DisableHooks requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeleteCRDs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1238,16 +1252,12 @@ func autoConvert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(in *v1beta1.PolicyRef,
 	out.Namespace = in.Namespace
 	out.Name = in.Name
 	out.Kind = in.Kind
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
 	out.Path = in.Path
 	out.DeploymentType = DeploymentType(in.DeploymentType)
 	return nil
 }
 
-// Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef is an autogenerated conversion function.
-func Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(in *v1beta1.PolicyRef, out *PolicyRef, s conversion.Scope) error {
-	return autoConvert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(in, out, s)
-}
-
 func autoConvert_v1alpha1_Profile_To_v1beta1_Profile(in *Profile, out *v1beta1.Profile, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_Spec_To_v1beta1_Spec(&in.Spec, &out.Spec, s); err != nil {
@@ -1446,7 +1456,17 @@ func autoConvert_v1alpha1_Spec_To_v1beta1_Spec(in *Spec, out *v1beta1.Spec, s co
 	out.Reloader = in.Reloader
 	out.TemplateResourceRefs = *(*[]v1beta1.TemplateResourceRef)(unsafe.Pointer(&in.TemplateResourceRefs))
 	out.DependsOn = *(*[]string)(unsafe.Pointer(&in.DependsOn))
-	out.PolicyRefs = *(*[]v1beta1.PolicyRef)(unsafe.Pointer(&in.PolicyRefs))
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]v1beta1.PolicyRef, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_PolicyRef_To_v1beta1_PolicyRef(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PolicyRefs = nil
+	}
 	if in.HelmCharts != nil {
 		in, out := &in.HelmCharts, &out.HelmCharts
 		*out = make([]v1beta1.HelmChart, len(*in))
@@ -1472,12 +1492,29 @@ func autoConvert_v1beta1_Spec_To_v1alpha1_Spec(in *v1beta1.Spec, out *Spec, s co
 	out.SyncMode = SyncMode(in.SyncMode)
 	out.Tier = in.Tier
 	out.ContinueOnConflict = in.ContinueOnConflict
+	// WARNING: in.AdoptionPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.RecreateOnImmutableChange requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletionPolicies requires manual conversion: does not exist in peer-type
+	// WARNING: in.NamespaceLabels requires manual conversion: does not exist in peer-type
 	out.MaxUpdate = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUpdate))
 	out.StopMatchingBehavior = StopMatchingBehavior(in.StopMatchingBehavior)
 	out.Reloader = in.Reloader
+	// WARNING: in.ReloadConsumers requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResyncPeriod requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeploymentTimeout requires manual conversion: does not exist in peer-type
 	out.TemplateResourceRefs = *(*[]TemplateResourceRef)(unsafe.Pointer(&in.TemplateResourceRefs))
 	out.DependsOn = *(*[]string)(unsafe.Pointer(&in.DependsOn))
-	out.PolicyRefs = *(*[]PolicyRef)(unsafe.Pointer(&in.PolicyRefs))
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]PolicyRef, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_PolicyRef_To_v1alpha1_PolicyRef(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PolicyRefs = nil
+	}
 	if in.HelmCharts != nil {
 		in, out := &in.HelmCharts, &out.HelmCharts
 		*out = make([]HelmChart, len(*in))
@@ -1522,14 +1559,10 @@ func autoConvert_v1beta1_Status_To_v1alpha1_Status(in *v1beta1.Status, out *Stat
 	if err := Convert_v1beta1_Clusters_To_v1alpha1_Clusters(&in.UpdatedClusters, &out.UpdatedClusters, s); err != nil {
 		return err
 	}
+	// WARNING: in.ObservedGeneration requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_Status_To_v1alpha1_Status is an autogenerated conversion function.
-func Convert_v1beta1_Status_To_v1alpha1_Status(in *v1beta1.Status, out *Status, s conversion.Scope) error {
-	return autoConvert_v1beta1_Status_To_v1alpha1_Status(in, out, s)
-}
-
 func autoConvert_v1alpha1_TemplateResourceRef_To_v1beta1_TemplateResourceRef(in *TemplateResourceRef, out *v1beta1.TemplateResourceRef, s conversion.Scope) error {
 	out.Resource = in.Resource
 	out.Identifier = in.Identifier
@@ -30,6 +30,8 @@ const (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=clusterprofiles,scope=Cluster
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io="
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io/move-hierarchy="
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 
@@ -42,6 +42,7 @@ const (
 	UpdateResourceAction   ResourceAction = "Update"
 	DeleteResourceAction   ResourceAction = "Delete"
 	ConflictResourceAction ResourceAction = "Conflict"
+	RecreateResourceAction ResourceAction = "Recreate"
 )
 
 type ReleaseReport struct {
@@ -73,7 +74,7 @@ type ResourceReport struct {
 	Resource Resource `json:"resource"`
 
 	// Action represent the type of operation on the Kubernetes resource.
-	// +kubebuilder:validation:Enum=No Action;Create;Update;Delete;Conflict
+	// +kubebuilder:validation:Enum=No Action;Create;Update;Delete;Conflict;Recreate
 	Action string `json:"action,omitempty"`
 
 	// Message is for any message that needs to added to better
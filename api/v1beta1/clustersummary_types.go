@@ -35,6 +35,14 @@ const (
 	// ClusterSummary before removing it from the apiserver.
 	ClusterSummaryFinalizer = "clustersummaryfinalizer.projectsveltos.io"
 
+	// ReferencedResourceFinalizer, when enabled, is added to ConfigMaps/Secrets referenced by
+	// at least one ClusterSummary. It prevents those from being deleted while still in use.
+	ReferencedResourceFinalizer = "referencedresourcefinalizer.projectsveltos.io"
+
+	// ReferencedResourceLabel is added, together with ReferencedResourceFinalizer, to protected
+	// ConfigMaps/Secrets so those can be listed with a label selector.
+	ReferencedResourceLabel = "projectsveltos.io/referenced-resource"
+
 	ClusterSummaryKind = "ClusterSummary"
 )
 
@@ -80,6 +88,100 @@ const (
 	FeatureStatusRemoved = FeatureStatus("Removed")
 )
 
+const (
+	// DegradedCondition is set to True when at least one feature has not been
+	// provisioned for longer than the configured threshold.
+	DegradedCondition = "Degraded"
+
+	// FeaturesNotConvergedReason indicates one or more features are stuck
+	// in Provisioning or Failed state.
+	FeaturesNotConvergedReason = "FeaturesNotConverged"
+
+	// FeaturesConvergedReason indicates no feature is stuck.
+	FeaturesConvergedReason = "FeaturesConverged"
+
+	// FeatureConvergedConditionSuffix, appended to a FeatureID (for instance ResourcesConverged),
+	// is the type of the condition set to False while that feature is in Provisioning or Failed
+	// state. Its LastTransitionTime is the time the feature stopped being converged. The condition
+	// is removed once the feature converges.
+	FeatureConvergedConditionSuffix = "Converged"
+
+	// PausedCondition is set to True when the managed cluster or the ClusterSummary
+	// is paused. While paused, features are neither deployed nor withdrawn.
+	PausedCondition = "Paused"
+
+	// ClusterPausedReason indicates cluster (or ClusterSummary) is paused.
+	ClusterPausedReason = "ClusterPaused"
+
+	// ReferencesValidCondition is set to True when all resources referenced by the
+	// ClusterSummary exist and can be parsed. It is set to False otherwise, with
+	// Status.InvalidReferences listing the offending references.
+	ReferencesValidCondition = "ReferencesValid"
+
+	// ReferencesResolvedReason indicates all referenced resources are valid.
+	ReferencesResolvedReason = "ReferencesResolved"
+
+	// InvalidReferencesReason indicates one or more referenced resources are missing
+	// or cannot be parsed.
+	InvalidReferencesReason = "InvalidReferences"
+)
+
+// ReferenceFailureReason describes why a referenced resource is not valid
+type ReferenceFailureReason string
+
+const (
+	// ReferenceNotFound indicates the referenced resource does not exist
+	ReferenceNotFound = ReferenceFailureReason("NotFound")
+
+	// ReferenceUnparsable indicates the content of the referenced resource
+	// cannot be parsed
+	ReferenceUnparsable = ReferenceFailureReason("Unparsable")
+
+	// ReferenceNotSupported indicates the referenced resource is not supported
+	// (for instance a Secret of the wrong type)
+	ReferenceNotSupported = ReferenceFailureReason("NotSupported")
+)
+
+// InvalidReference reports a resource, referenced by the ClusterSummary,
+// which is either missing or invalid
+type InvalidReference struct {
+	// Kind of the referenced resource
+	Kind string `json:"kind"`
+
+	// Namespace of the referenced resource
+	Namespace string `json:"namespace"`
+
+	// Name of the referenced resource
+	Name string `json:"name"`
+
+	// Reason indicates why the referenced resource is not valid
+	// +kubebuilder:validation:Enum:=NotFound;Unparsable;NotSupported
+	Reason ReferenceFailureReason `json:"reason"`
+
+	// Message provides more details
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceFailure reports a resource which could not be deployed
+type ResourceFailure struct {
+	// APIVersion of the resource
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Namespace of the resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Message indicates why the resource could not be deployed
+	Message string `json:"message"`
+}
+
 // FeatureSummary contains a summary of the state of a workload
 // cluster feature.
 type FeatureSummary struct {
@@ -103,6 +205,12 @@ type FeatureSummary struct {
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
 
+	// FailedResources lists, when deployment failed, each resource which could not
+	// be deployed and why.
+	// +listType=atomic
+	// +optional
+	FailedResources []ResourceFailure `json:"failedResources,omitempty"`
+
 	// DeployedGroupVersionKind contains all GroupVersionKinds deployed in either
 	// the workload cluster or the management cluster because of this feature.
 	// Each element has format kind.version.group
@@ -205,11 +313,25 @@ type ClusterSummaryStatus struct {
 	// +listType=atomic
 	// +optional
 	HelmReleaseSummaries []HelmChartSummary `json:"helmReleaseSummaries,omitempty"`
+
+	// InvalidReferences lists the referenced resources which are either
+	// missing or cannot be parsed.
+	// +listType=atomic
+	// +optional
+	InvalidReferences []InvalidReference `json:"invalidReferences,omitempty"`
+
+	// Conditions reports the ClusterSummary conditions.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //nolint: lll // marker
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=clustersummaries,scope=Namespaced
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io="
+// +kubebuilder:metadata:labels="clusterctl.cluster.x-k8s.io/move="
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterSummary"
@@ -145,6 +145,8 @@ type RegistryCredentialsConfig struct {
 	// CredentialsSecretRef references a secret containing credentials
 	// For ClusterProfile namespace can be left empty. In such a case, namespace will
 	// be implicit set to cluster's namespace.
+	// For classic (non OCI) helm repositories, the secret must either contain username
+	// and password keys or be of type kubernetes.io/dockerconfigjson.
 	// +optional
 	CredentialsSecretRef *corev1.SecretReference `json:"credentials,omitempty"`
 
@@ -364,6 +366,17 @@ type HelmUninstallOptions struct {
 	// +kubebuilder:default:=false
 	// +optional
 	DisableHooks bool `json:"disableHooks,omitempty"`
+
+	// DeleteCRDs, when set, removes the CRDs contained in the chart crds/ directory
+	// once the release is uninstalled. Helm never removes those CRDs. Leftover CRDs,
+	// and the webhooks relying on them, might keep affecting the managed cluster.
+	// All instances of those CRDs are removed as well.
+	// The value in use at the last install or upgrade is recorded on the helm release,
+	// so it is honored also when the chart is removed from the profile.
+	// Default to false
+	// +kubebuilder:default:=false
+	// +optional
+	DeleteCRDs bool `json:"deleteCRDs,omitempty"`
 }
 
 type HelmChart struct {
@@ -403,6 +416,8 @@ type HelmChart struct {
 	// These values can be static or leverage Go templates for dynamic customization.
 	// When expressed as templates, the values are filled in using information from
 	// resources within the management cluster before deployment (Cluster and TemplateResourceRefs)
+	// Values are merged in order: Values first, then each ValuesFrom entry in the order listed.
+	// A later entry overrides values set by an earlier one.
 	// +optional
 	ValuesFrom []ValueFrom `json:"valuesFrom,omitempty"`
 
@@ -514,6 +529,43 @@ const (
 	LeavePolicies    StopMatchingBehavior = "LeavePolicies"
 )
 
+// AdoptionPolicy specifies what Sveltos does when a resource it needs to deploy already
+// exists in the managed cluster but was not deployed by Sveltos.
+// +kubebuilder:validation:Enum:=Adopt;Fail;Skip
+type AdoptionPolicy string
+
+// Define the AdoptionPolicy constants.
+const (
+	// AdoptionPolicyAdopt indicates existing resources are updated and managed by Sveltos from then on
+	AdoptionPolicyAdopt AdoptionPolicy = "Adopt"
+
+	// AdoptionPolicyFail indicates existing resources are left untouched and reported as conflicts
+	AdoptionPolicyFail AdoptionPolicy = "Fail"
+
+	// AdoptionPolicySkip indicates existing resources are left untouched and deployment continues
+	AdoptionPolicySkip AdoptionPolicy = "Skip"
+)
+
+// DeletionPolicy customizes how Sveltos removes Kubernetes resources deployed because of a feature
+type DeletionPolicy struct {
+	// FeatureID is the feature this policy applies to. Only Resources and Kustomize
+	// are supported. Helm charts are configured via HelmChart Options instead.
+	FeatureID FeatureID `json:"featureID"`
+
+	// PropagationPolicy is the deletion propagation policy used when removing resources.
+	// Defaults to the policy of the resource kind (Background for most resources).
+	// +kubebuilder:validation:Enum:=Foreground;Background;Orphan
+	// +optional
+	PropagationPolicy *metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+
+	// WaitForDeletion, when set to true, makes Sveltos confirm removed resources are gone
+	// (for instance after their finalizers ran) before considering the undeploy complete.
+	// Until then, the ClusterSummary finalizer is not removed.
+	// +kubebuilder:default:=false
+	// +optional
+	WaitForDeletion bool `json:"waitForDeletion,omitempty"`
+}
+
 type TemplateResourceRef struct {
 	// Resource references a Kubernetes instance in the management
 	// cluster to fetch and use during template instantiation.
@@ -552,6 +604,16 @@ type PolicyRef struct {
 	// +kubebuilder:validation:Enum=GitRepository;OCIRepository;Bucket;ConfigMap;Secret
 	Kind string `json:"kind"`
 
+	// Version, when set, pins this PolicyRef to a specific version of a ConfigMap/Secret:
+	// the resource named <Name>-<Version> is deployed instead of Name. Published versions are
+	// meant to be immutable (immutable: true), so what is deployed does not change until
+	// Version is explicitly bumped.
+	// When not set, PolicyRef tracks the latest content of Name and every edit is deployed.
+	// Ignored for GitRepository;OCIRepository;Bucket (pin those via their own ref).
+	// +kubebuilder:validation:Pattern=`^$|^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
 	// Path to the directory containing the YAML files.
 	// Defaults to 'None', which translates to the root path of the SourceRef.
 	// Used only for GitRepository;OCIRepository;Bucket
@@ -638,6 +700,40 @@ type Spec struct {
 	// +optional
 	ContinueOnConflict bool `json:"continueOnConflict,omitempty"`
 
+	// AdoptionPolicy indicates what Sveltos does when a resource it needs to deploy already exists
+	// in the managed cluster but was not deployed by Sveltos (for instance in brownfield clusters).
+	// - Adopt means the resource is updated and managed by Sveltos from then on;
+	// - Fail means the resource is left untouched and reported as a conflict. ContinueOnConflict
+	// indicates whether remaining resources are still deployed;
+	// - Skip means the resource is left untouched and remaining resources are deployed.
+	// Resources left untouched are neither tracked nor removed by Sveltos.
+	// +kubebuilder:default:=Adopt
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// RecreateOnImmutableChange lists the features (Resources and Kustomize) for which Sveltos deletes
+	// and recreates a resource when its update is rejected because an immutable field changed (for
+	// instance a Deployment selector or a Service clusterIP). Recreated resources are reported with
+	// action Recreate. By default such updates keep failing.
+	// +listType=set
+	// +optional
+	RecreateOnImmutableChange []FeatureID `json:"recreateOnImmutableChange,omitempty"`
+
+	// DeletionPolicies customizes, per feature, how resources deployed by Sveltos are removed
+	// from the managed cluster.
+	// +listType=map
+	// +listMapKey=featureID
+	// +optional
+	DeletionPolicies []DeletionPolicy `json:"deletionPolicies,omitempty"`
+
+	// NamespaceLabels are added to the namespaces Sveltos creates in a managed cluster while
+	// deploying resources. They can be used to exempt those namespaces from admission webhooks
+	// (for instance Kyverno or Gatekeeper) which are deployed by this same ClusterProfile/Profile
+	// and could otherwise block the resources applied right after them.
+	// Namespaces already existing in the managed cluster are not modified.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
 	// The maximum number of clusters that can be updated concurrently.
 	// Value can be an absolute number (ex: 5) or a percentage of desired cluster (ex: 10%).
 	// Defaults to 100%.
@@ -666,6 +762,34 @@ type Spec struct {
 	// +optional
 	Reloader bool `json:"reloader,omitempty"`
 
+	// ReloadConsumers indicates whether Deployment/StatefulSet/DaemonSet instances consuming a
+	// ConfigMap/Secret distributed by this ClusterProfile need to be restarted via rolling upgrade
+	// when Sveltos updates the content of such ConfigMap/Secret.
+	// Contrary to Reloader, consuming workloads do not need to be deployed by Sveltos. Any workload
+	// in the same namespace referencing the ConfigMap/Secret (volumes, env, envFrom, imagePullSecrets)
+	// is restarted by annotating its pod template.
+	// +kubebuilder:default:=false
+	// +optional
+	ReloadConsumers bool `json:"reloadConsumers,omitempty"`
+
+	// ResyncPeriod, when set, forces features deployed in Continuous mode to be re-applied
+	// once this period has elapsed since last deployment, even if their configuration has
+	// not changed. This bounds in time any drift in clusters where the drift-detection-manager
+	// cannot be installed.
+	// It overrides, for this ClusterProfile/Profile, the controller full-resync-period setting.
+	// Setting it to 0s disables full resync for this ClusterProfile/Profile.
+	// A resync is evaluated every time the ClusterSummary is reconciled, so the effective
+	// resolution is bounded by the controller sync period.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// DeploymentTimeout, when set, bounds the time spent deploying each feature (resources,
+	// helm charts, kustomize) in a managed cluster. If a deployment exceeds it, the feature
+	// is marked Failed with FailureReason Timeout and it is deployed again only after a backoff
+	// period longer than the one used for other failures.
+	// +optional
+	DeploymentTimeout *metav1.Duration `json:"deploymentTimeout,omitempty"`
+
 	// TemplateResourceRefs is a list of resource to collect from the management cluster.
 	// Those resources' values will be used to instantiate templates
 	// +patchMergeKey=identifier
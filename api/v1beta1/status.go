@@ -37,4 +37,9 @@ type Status struct {
 	// Spec
 	// +optional
 	UpdatedClusters Clusters `json:"updatedClusters,omitempty"`
+
+	// ObservedGeneration is the most recent generation of ClusterProfile/Profile
+	// Spec fully processed. ClusterSummaries for all matching clusters reflect it.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TemplateLibraryKind is the kind for TemplateLibrary resource
+	TemplateLibraryKind = "TemplateLibrary"
+)
+
+// LibraryTemplate is a named and versioned template fragment
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="a published template version is immutable, publish a new version instead"
+type LibraryTemplate struct {
+	// Name of the template
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Version of the template. Templates referencing this one, do so
+	// by name and version. A given name and version should never be modified,
+	// rather a new version should be published.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Template is the template fragment. It is parsed as a Go template and
+	// has access to the same functions and data as the template including it.
+	Template string `json:"template"`
+}
+
+// TemplateLibrarySpec defines the desired state of TemplateLibrary
+type TemplateLibrarySpec struct {
+	// Templates is the list of templates part of this library.
+	// Any template (PolicyRefs, Helm values, Kustomize substitute values, patches)
+	// can include them with includeTemplate "<name>@<version>" <data>
+	// A template, once published, cannot be modified.
+	// +listType=map
+	// +listMapKey=name
+	// +listMapKey=version
+	// +optional
+	Templates []LibraryTemplate `json:"templates,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=templatelibraries,scope=Cluster
+// +kubebuilder:storageversion
+
+// TemplateLibrary is the Schema for the templatelibraries API.
+// It holds named and versioned template fragments that can be reused
+// across ClusterProfiles/Profiles.
+type TemplateLibrary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TemplateLibrarySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TemplateLibraryList contains a list of TemplateLibrary
+type TemplateLibraryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TemplateLibrary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TemplateLibrary{}, &TemplateLibraryList{})
+}
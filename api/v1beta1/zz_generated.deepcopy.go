@@ -428,6 +428,18 @@ func (in *ClusterSummaryStatus) DeepCopyInto(out *ClusterSummaryStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InvalidReferences != nil {
+		in, out := &in.InvalidReferences, &out.InvalidReferences
+		*out = make([]InvalidReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSummaryStatus.
@@ -465,6 +477,26 @@ func (in *Clusters) DeepCopy() *Clusters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicy) DeepCopyInto(out *DeletionPolicy) {
+	*out = *in
+	if in.PropagationPolicy != nil {
+		in, out := &in.PropagationPolicy, &out.PropagationPolicy
+		*out = new(metav1.DeletionPropagation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicy.
+func (in *DeletionPolicy) DeepCopy() *DeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriftExclusion) DeepCopyInto(out *DriftExclusion) {
 	*out = *in
@@ -572,6 +604,11 @@ func (in *FeatureSummary) DeepCopyInto(out *FeatureSummary) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FailedResources != nil {
+		in, out := &in.FailedResources, &out.FailedResources
+		*out = make([]ResourceFailure, len(*in))
+		copy(*out, *in)
+	}
 	if in.DeployedGroupVersionKind != nil {
 		in, out := &in.DeployedGroupVersionKind, &out.DeployedGroupVersionKind
 		*out = make([]string, len(*in))
@@ -718,6 +755,21 @@ func (in *HelmUpgradeOptions) DeepCopy() *HelmUpgradeOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidReference) DeepCopyInto(out *InvalidReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidReference.
+func (in *InvalidReference) DeepCopy() *InvalidReference {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KustomizationRef) DeepCopyInto(out *KustomizationRef) {
 	*out = *in
@@ -745,6 +797,21 @@ func (in *KustomizationRef) DeepCopy() *KustomizationRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LibraryTemplate) DeepCopyInto(out *LibraryTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibraryTemplate.
+func (in *LibraryTemplate) DeepCopy() *LibraryTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(LibraryTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRef) DeepCopyInto(out *PolicyRef) {
 	*out = *in
@@ -901,6 +968,21 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFailure) DeepCopyInto(out *ResourceFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFailure.
+func (in *ResourceFailure) DeepCopy() *ResourceFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceReport) DeepCopyInto(out *ResourceReport) {
 	*out = *in
@@ -931,11 +1013,40 @@ func (in *Spec) DeepCopyInto(out *Spec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RecreateOnImmutableChange != nil {
+		in, out := &in.RecreateOnImmutableChange, &out.RecreateOnImmutableChange
+		*out = make([]FeatureID, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletionPolicies != nil {
+		in, out := &in.DeletionPolicies, &out.DeletionPolicies
+		*out = make([]DeletionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.MaxUpdate != nil {
 		in, out := &in.MaxUpdate, &out.MaxUpdate
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DeploymentTimeout != nil {
+		in, out := &in.DeploymentTimeout, &out.DeploymentTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.TemplateResourceRefs != nil {
 		in, out := &in.TemplateResourceRefs, &out.TemplateResourceRefs
 		*out = make([]TemplateResourceRef, len(*in))
@@ -1034,6 +1145,84 @@ func (in *Status) DeepCopy() *Status {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLibrary) DeepCopyInto(out *TemplateLibrary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibrary.
+func (in *TemplateLibrary) DeepCopy() *TemplateLibrary {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLibrary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TemplateLibrary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLibraryList) DeepCopyInto(out *TemplateLibraryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TemplateLibrary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibraryList.
+func (in *TemplateLibraryList) DeepCopy() *TemplateLibraryList {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLibraryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TemplateLibraryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLibrarySpec) DeepCopyInto(out *TemplateLibrarySpec) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]LibraryTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibrarySpec.
+func (in *TemplateLibrarySpec) DeepCopy() *TemplateLibrarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLibrarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateResourceRef) DeepCopyInto(out *TemplateResourceRef) {
 	*out = *in
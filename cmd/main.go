@@ -18,13 +18,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -34,13 +37,27 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -57,6 +74,8 @@ import (
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/api/v1beta1/index"
 	"github.com/projectsveltos/addon-controller/controllers"
+	"github.com/projectsveltos/addon-controller/controllers/clustercache"
+	"github.com/projectsveltos/addon-controller/internal/features"
 	"github.com/projectsveltos/addon-controller/internal/telemetry"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/crd"
@@ -70,21 +89,48 @@ var (
 	setupLog                = ctrl.Log.WithName("setup")
 	diagnosticsAddress      string
 	insecureDiagnostics     bool
+	diagnosticsCertDir      string
+	diagnosticsClientCAFile string
+	enablePprof             bool
+	enableTracing           bool
+	featureLogVerbosity     map[string]int
 	shardKey                string
 	workers                 int
 	concurrentReconciles    int
+	profileReconciles       int
+	summaryReconciles       int
 	agentInMgmtCluster      bool
 	reportMode              controllers.ReportMode
 	tmpReportMode           int
 	restConfigQPS           float32
 	restConfigBurst         int
+	remoteRestConfigQPS     float32
+	remoteRestConfigBurst   int
+	leaderElect             bool
+	leaderElectLease        time.Duration
+	leaderElectRenew        time.Duration
+	leaderElectRetry        time.Duration
 	webhookPort             int
 	syncPeriod              time.Duration
 	conflictRetryTime       time.Duration
+	fullResyncPeriod        time.Duration
+	stuckFeatureThreshold   time.Duration
+	remoteCleanupOnDelete   bool
+	protectReferences       bool
+	enablePolicyWebhook     bool
+	webhookSelfSignedCerts  bool
+	webhookCertDir          string
+	webhookServiceName      string
+	webhookServiceNs        string
+	webhookConfigName       string
+	maxDeployDuration       time.Duration
+	deployerShutdownGrace   time.Duration
 	version                 string
 	healthAddr              string
 	profilerAddress         string
 	driftDetectionConfigMap string
+	imageRegistryOverride   string
+	deploymentPolicies      string
 	disableCaching          bool
 	disableTelemetry        bool
 )
@@ -102,6 +148,9 @@ const (
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
+// Add RBAC for leader election.
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+
 func main() {
 	scheme, err := controllers.InitScheme()
 	if err != nil {
@@ -141,7 +190,8 @@ func main() {
 		HealthProbeBindAddress: healthAddr,
 		WebhookServer: webhook.NewServer(
 			webhook.Options{
-				Port: webhookPort,
+				Port:    webhookPort,
+				CertDir: webhookCertDir,
 			}),
 		Cache: cache.Options{
 			SyncPeriod: &syncPeriod,
@@ -153,11 +203,17 @@ func main() {
 			},
 		},
 		PprofBindAddress: profilerAddress,
+		LeaderElection:   leaderElect,
+		LeaderElectionID: getLeaderElectionID(),
+		LeaseDuration:    &leaderElectLease,
+		RenewDeadline:    &leaderElectRenew,
+		RetryPeriod:      &leaderElectRetry,
 	}
 
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.QPS = restConfigQPS
 	restConfig.Burst = restConfigBurst
+	clustercache.SetRemoteClientRateLimits(remoteRestConfigQPS, remoteRestConfigBurst)
 
 	mgr, err := ctrl.NewManager(restConfig, ctrlOptions)
 	if err != nil {
@@ -169,6 +225,12 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 	controllers.SetManagementClusterAccess(mgr.GetClient(), mgr.GetConfig())
 	controllers.SetDriftdetectionConfigMap(driftDetectionConfigMap)
+	controllers.SetImageRegistryOverride(imageRegistryOverride)
+	controllers.SetDeploymentPoliciesConfigMap(deploymentPolicies)
+	if err := controllers.SetFeatureLogVerbosity(featureLogVerbosity); err != nil {
+		setupLog.Error(err, "invalid feature-log-verbosity")
+		os.Exit(1)
+	}
 
 	logsettings.RegisterForLogSettings(ctx,
 		libsveltosv1beta1.ComponentAddonManager, ctrl.Log.WithName("log-setter"),
@@ -184,7 +246,47 @@ func main() {
 		}
 	}
 
-	startControllersAndWatchers(ctx, mgr)
+	if enableTracing {
+		if err := setupTracing(ctx, mgr); err != nil {
+			setupLog.Error(err, "failed to setup tracing")
+			os.Exit(1)
+		}
+	}
+
+	// Deployer workers are stopped only once in-flight requests are drained, so they
+	// do not use the manager context which is canceled as soon as a signal is received
+	deployerCtx, cancelDeployer := context.WithCancel(context.Background())
+	startControllersAndWatchers(ctx, deployerCtx, mgr)
+
+	if enablePolicyWebhook {
+		validator := &controllers.PolicyConfigMapValidator{Logger: ctrl.Log.WithName("policy-validator")}
+		if err := validator.SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ConfigMap")
+			os.Exit(1)
+		}
+
+		if webhookSelfSignedCerts {
+			setupWebhookCertificates(ctx, mgr)
+		}
+	}
+
+	// Compliance report and inventory are served by the diagnostics server, so they are protected the same
+	// way metrics are. They expose cluster content, so they are not served when diagnostics are insecure
+	if insecureDiagnostics {
+		setupLog.Info(fmt.Sprintf("%s and %s are not served with --insecure-diagnostics",
+			controllers.ComplianceReportPath, controllers.InventoryPath))
+	} else {
+		if err := mgr.AddMetricsServerExtraHandler(controllers.ComplianceReportPath,
+			controllers.NewComplianceReportHandler(mgr.GetClient(), ctrl.Log.WithName("compliance-report"))); err != nil {
+			setupLog.Error(err, "unable to set up compliance report endpoint")
+			os.Exit(1)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(controllers.InventoryPath,
+			controllers.NewInventoryHandler(mgr.GetClient(), ctrl.Log.WithName("inventory"))); err != nil {
+			setupLog.Error(err, "unable to set up inventory endpoint")
+			os.Exit(1)
+		}
+	}
 
 	setupChecks(mgr)
 	controllers.SetVersion(version)
@@ -192,7 +294,17 @@ func main() {
 	setupIndexes(ctx, mgr)
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctx); err != nil {
+	err = mgr.Start(ctx)
+
+	// Let in-flight deployer requests complete before exiting. Unfinished requests are not persisted,
+	// they are queued again by ClusterSummary reconciliation once the next leader starts.
+	if inFlight := controllers.DrainDeployer(deployerShutdownGrace); len(inFlight) != 0 {
+		setupLog.Info(fmt.Sprintf("exiting with %d deployer requests still in progress. Those will be "+
+			"redeployed by next leader", len(inFlight)), "requests", inFlight)
+	}
+	cancelDeployer()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -219,6 +331,27 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&insecureDiagnostics, "insecure-diagnostics", false,
 		"Enable insecure diagnostics serving. For more details see the description of --diagnostics-address.")
 
+	fs.StringVar(&diagnosticsCertDir, "diagnostics-cert-dir", "",
+		"Directory containing tls.crt and tls.key used by the secure diagnostics endpoint. Certificates are reloaded "+
+			"when they change. When not set, a self-signed certificate is generated")
+
+	fs.StringVar(&diagnosticsClientCAFile, "diagnostics-client-ca-file", "",
+		"When set, clients of the secure diagnostics endpoint must present a certificate signed by a CA in this "+
+			"PEM file. Client certificate replaces token authentication: certificate CommonName is the user, "+
+			"authorized via SubjectAccessReviews")
+
+	fs.BoolVar(&enablePprof, "enable-pprof", true,
+		"When set, the secure diagnostics endpoint also serves pprof (cpu, heap, goroutine, ...) endpoints. "+
+			"Ignored if --insecure-diagnostics is set")
+
+	fs.BoolVar(&enableTracing, "enable-tracing", false,
+		"When set, OpenTelemetry traces are exported via OTLP/gRPC. Exporter is configured using the standard "+
+			"OTEL_EXPORTER_OTLP_* environment variables (e.g. OTEL_EXPORTER_OTLP_ENDPOINT)")
+
+	fs.StringToIntVar(&featureLogVerbosity, "feature-log-verbosity", map[string]int{},
+		"Per feature log verbosity overriding the global one (e.g. Helm=5,Resources=1). "+
+			"Valid features are Resources, Helm and Kustomize")
+
 	fs.StringVar(&shardKey, "shard-key", "",
 		"If set, only clusters will annotation matching this shard key will be reconciled by this deployment")
 
@@ -228,8 +361,17 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&concurrentReconciles, "concurrent-reconciles", defaultReconcilers,
 		"concurrent reconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 10")
 
+	fs.IntVar(&profileReconciles, "profile-concurrent-reconciles", 0,
+		"Maximum number of concurrent Reconciles for ClusterProfile, Profile, ClusterSet and Set controllers. "+
+			"Defaults to concurrent-reconciles")
+
+	fs.IntVar(&summaryReconciles, "clustersummary-concurrent-reconciles", 0,
+		"Maximum number of concurrent Reconciles for ClusterSummary controller. Defaults to concurrent-reconciles")
+
 	fs.StringVar(&version, "version", "", "current sveltos version")
 
+	features.MutableGates.AddFlag(fs)
+
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
@@ -239,6 +381,15 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&driftDetectionConfigMap, "drift-detection-config", "",
 		"The name of the ConfigMap in the projectsveltos namespace containing the drift-detection-manager configuration")
 
+	fs.StringVar(&imageRegistryOverride, "image-registry-override", "",
+		"When set (e.g. registry.example.com), the registry of every image in the manifests embedded in "+
+			"addon-controller (drift-detection-manager) is replaced with this one. Meant for air-gapped clusters")
+
+	fs.StringVar(&deploymentPolicies, "deployment-policies-config", "",
+		"The name of the ConfigMap in the projectsveltos namespace containing deployment policies. Each key is a "+
+			"CEL expression evaluated against every resource (variable object) before it is deployed. "+
+			"Resources are deployed only if all expressions evaluate to true")
+
 	const defautlRestConfigQPS = 20
 	fs.Float32Var(&restConfigQPS, "kube-api-qps", defautlRestConfigQPS,
 		fmt.Sprintf("Maximum queries per second from the controller client to the Kubernetes API server. Defaults to %d",
@@ -249,6 +400,32 @@ func initFlags(fs *pflag.FlagSet) {
 		fmt.Sprintf("Maximum number of queries that should be allowed in one burst from the controller client to the Kubernetes API server. Default %d",
 			defaultRestConfigBurst))
 
+	fs.Float32Var(&remoteRestConfigQPS, "remote-kube-api-qps", 0,
+		"Maximum queries per second from the controller clients to managed clusters Kubernetes API servers. "+
+			"Defaults to the value set in each cluster kubeconfig")
+
+	fs.IntVar(&remoteRestConfigBurst, "remote-kube-api-burst", 0,
+		"Maximum number of queries that should be allowed in one burst from the controller clients to managed "+
+			"clusters Kubernetes API servers. Defaults to the value set in each cluster kubeconfig")
+
+	fs.BoolVar(&leaderElect, "leader-elect", false,
+		"Enable leader election. Ensures only one replica (per shard) is active at any time")
+
+	const defaultLeaderElectLease = 15
+	fs.DurationVar(&leaderElectLease, "leader-elect-lease-duration", defaultLeaderElectLease*time.Second,
+		fmt.Sprintf("Duration non-leader candidates will wait before trying to acquire leadership. Default: %d seconds",
+			defaultLeaderElectLease))
+
+	const defaultLeaderElectRenew = 10
+	fs.DurationVar(&leaderElectRenew, "leader-elect-renew-deadline", defaultLeaderElectRenew*time.Second,
+		fmt.Sprintf("Duration the leader will retry refreshing leadership before giving it up. Default: %d seconds",
+			defaultLeaderElectRenew))
+
+	const defaultLeaderElectRetry = 2
+	fs.DurationVar(&leaderElectRetry, "leader-elect-retry-period", defaultLeaderElectRetry*time.Second,
+		fmt.Sprintf("Duration leader election clients wait between tries of actions. Default: %d seconds",
+			defaultLeaderElectRetry))
+
 	const defaultWebhookPort = 9443
 	fs.IntVar(&webhookPort, "webhook-port", defaultWebhookPort,
 		"Webhook Server port")
@@ -262,6 +439,81 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&conflictRetryTime, "conflict-retry-time", defaultConflictRetryTime*time.Second,
 		fmt.Sprintf("The minimum interval at which watched ClusterProfile with conflicts are retried. Defaul: %d seconds",
 			defaultConflictRetryTime))
+
+	fs.DurationVar(&fullResyncPeriod, "full-resync-period", 0,
+		"When set (e.g. 6h), features deployed in Continuous mode are re-applied once this period has elapsed "+
+			"since last deployment, even if their configuration has not changed. Disabled by default")
+
+	const defaultMaxDeployDuration = time.Hour
+	fs.DurationVar(&maxDeployDuration, "max-deploy-duration", defaultMaxDeployDuration,
+		"Maximum time a deployer worker can spend processing a single request before the manager reports "+
+			"itself as not ready. Set to 0 to disable this check")
+
+	const defaultDeployerShutdownGrace = 8
+	fs.DurationVar(&deployerShutdownGrace, "deployer-shutdown-grace-period", defaultDeployerShutdownGrace*time.Second,
+		fmt.Sprintf("On shutdown, no new deployer request is started and in-flight ones are given up to this period "+
+			"to complete. Requests not completed are redeployed once the next leader starts. "+
+			"Must be shorter than the pod terminationGracePeriodSeconds. Default: %d seconds",
+			defaultDeployerShutdownGrace))
+
+	fs.DurationVar(&stuckFeatureThreshold, "stuck-feature-threshold", 0,
+		"When set (e.g. 30m), ClusterSummary Degraded condition is set if a feature stays in Provisioning or Failed "+
+			"state for longer than this threshold. Disabled by default")
+
+	fs.BoolVar(&remoteCleanupOnDelete, "remote-cleanup-on-cluster-deletion", false,
+		"When set, features are removed from a managed cluster also when the CAPI Cluster itself is being deleted. "+
+			"By default, only management cluster state is cleaned as the cluster API server is going away")
+
+	fs.BoolVar(&protectReferences, "protect-referenced-resources", false,
+		"When set, a finalizer is added to ConfigMaps/Secrets referenced by ClusterProfiles/Profiles. Those cannot be "+
+			"deleted till no ClusterSummary is referencing them anymore")
+
+	fs.BoolVar(&enablePolicyWebhook, "enable-policy-validation-webhook", false,
+		fmt.Sprintf("When set, a validating webhook rejects ConfigMaps labeled with %s=true whose content is not "+
+			"made of valid Kubernetes resources", controllers.PolicyValidationLabelName))
+
+	fs.BoolVar(&webhookSelfSignedCerts, "webhook-self-signed-certs", false,
+		"When set, the webhook serving certificate is generated and rotated by addon-controller, and its CA is "+
+			"injected in the ValidatingWebhookConfiguration. Use cert-manager instead when running more than one replica")
+
+	fs.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"Directory containing webhook server tls.crt and tls.key. Default: <temp-dir>/k8s-webhook-server/serving-certs")
+
+	fs.StringVar(&webhookServiceName, "webhook-service-name", "addon-webhook-service",
+		"Name of the Service in front of the webhook server. Used with --webhook-self-signed-certs")
+
+	fs.StringVar(&webhookServiceNs, "webhook-service-namespace", "projectsveltos",
+		"Namespace of the Service in front of the webhook server. Used with --webhook-self-signed-certs")
+
+	fs.StringVar(&webhookConfigName, "webhook-configuration-name", "addon-validating-webhook-configuration",
+		"Name of the ValidatingWebhookConfiguration whose caBundle is managed. Used with --webhook-self-signed-certs")
+}
+
+func setupWebhookCertificates(ctx context.Context, mgr ctrl.Manager) {
+	certDir := webhookCertDir
+	if certDir == "" {
+		// Same default used by controller-runtime webhook server
+		certDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+
+	// Manager cache is not started yet, so use a direct client
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for webhook certificates")
+		os.Exit(1)
+	}
+
+	options := controllers.WebhookCertificateOptions{
+		CertDir:                  certDir,
+		ServiceName:              webhookServiceName,
+		ServiceNamespace:         webhookServiceNs,
+		WebhookConfigurationName: webhookConfigName,
+	}
+	if err := controllers.SetupWebhookCertificates(ctx, c, options,
+		ctrl.Log.WithName("webhook-certificates")); err != nil {
+		setupLog.Error(err, "unable to set up webhook certificates")
+		os.Exit(1)
+	}
 }
 
 func setupIndexes(ctx context.Context, mgr ctrl.Manager) {
@@ -280,6 +532,37 @@ func setupChecks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+
+	// Informer caches must be synced for the manager to be ready
+	if err := mgr.AddReadyzCheck("informers", cacheSyncCheck(mgr)); err != nil {
+		setupLog.Error(err, "unable to set up informers ready check")
+		os.Exit(1)
+	}
+
+	// Wedged deployer workers cause the readiness check to fail. Liveness is not affected, as a long
+	// running deployment (for instance a slow helm install) must not cause the pod to be restarted.
+	// Loss of leader election is not checked here: in that case manager stops and process exits.
+	if maxDeployDuration != 0 {
+		deployerCheck := controllers.DeployerHealthCheck(maxDeployDuration)
+		if err := mgr.AddReadyzCheck("deployer", deployerCheck); err != nil {
+			setupLog.Error(err, "unable to set up deployer ready check")
+			os.Exit(1)
+		}
+	}
+}
+
+// cacheSyncCheck returns a healthz.Checker reporting an error if manager informer caches are not synced.
+func cacheSyncCheck(mgr ctrl.Manager) healthz.Checker {
+	const cacheSyncTimeout = time.Second
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return fmt.Errorf("informer caches are not synced")
+		}
+		return nil
+	}
 }
 
 // capiCRDHandler restarts process if a CAPI CRD is updated
@@ -420,7 +703,7 @@ func getProfileReconciler(mgr manager.Manager) *controllers.ProfileReconciler {
 		Profiles:             make(map[corev1.ObjectReference]libsveltosv1beta1.Selector),
 		ClusterLabels:        make(map[corev1.ObjectReference]map[string]string),
 		Mux:                  sync.Mutex{},
-		ConcurrentReconciles: concurrentReconciles,
+		ConcurrentReconciles: getConcurrentReconciles(profileReconciles),
 		Logger:               ctrl.Log.WithName("profilereconciler"),
 	}
 }
@@ -434,30 +717,35 @@ func getClusterProfileReconciler(mgr manager.Manager) *controllers.ClusterProfil
 		ClusterProfiles:      make(map[corev1.ObjectReference]libsveltosv1beta1.Selector),
 		ClusterLabels:        make(map[corev1.ObjectReference]map[string]string),
 		Mux:                  sync.Mutex{},
-		ConcurrentReconciles: concurrentReconciles,
+		ConcurrentReconciles: getConcurrentReconciles(profileReconciles),
 		Logger:               ctrl.Log.WithName("clusterprofilereconciler"),
 	}
 }
 
-func getClusterSummaryReconciler(ctx context.Context, mgr manager.Manager) *controllers.ClusterSummaryReconciler {
-	d := deployer.GetClient(ctx, ctrl.Log.WithName("deployer"), mgr.GetClient(), workers)
+func getClusterSummaryReconciler(deployerCtx context.Context, mgr manager.Manager) *controllers.ClusterSummaryReconciler {
+	d := deployer.GetClient(deployerCtx, ctrl.Log.WithName("deployer"), mgr.GetClient(), workers)
 	controllers.RegisterFeatures(d, setupLog)
 
 	return &controllers.ClusterSummaryReconciler{
-		Config:               mgr.GetConfig(),
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		ShardKey:             shardKey,
-		Version:              version,
-		ReportMode:           reportMode,
-		AgentInMgmtCluster:   agentInMgmtCluster,
-		Deployer:             d,
-		ClusterMap:           make(map[corev1.ObjectReference]*libsveltosset.Set),
-		ReferenceMap:         make(map[corev1.ObjectReference]*libsveltosset.Set),
-		PolicyMux:            sync.Mutex{},
-		ConcurrentReconciles: concurrentReconciles,
-		ConflictRetryTime:    conflictRetryTime,
-		Logger:               ctrl.Log.WithName("clustersummaryreconciler"),
+		Config:                         mgr.GetConfig(),
+		Client:                         mgr.GetClient(),
+		Scheme:                         mgr.GetScheme(),
+		ShardKey:                       shardKey,
+		Version:                        version,
+		ReportMode:                     reportMode,
+		AgentInMgmtCluster:             agentInMgmtCluster,
+		Deployer:                       d,
+		ClusterMap:                     make(map[corev1.ObjectReference]*libsveltosset.Set),
+		ReferenceMap:                   make(map[corev1.ObjectReference]*libsveltosset.Set),
+		PolicyMux:                      sync.Mutex{},
+		ConcurrentReconciles:           getConcurrentReconciles(summaryReconciles),
+		ConflictRetryTime:              conflictRetryTime,
+		FullResyncPeriod:               fullResyncPeriod,
+		StuckFeatureThreshold:          stuckFeatureThreshold,
+		RemoteCleanupOnClusterDeletion: remoteCleanupOnDelete,
+		ProtectReferencedResources:     protectReferences,
+		EventRecorder:                  mgr.GetEventRecorderFor("addon-controller"),
+		Logger:                         ctrl.Log.WithName("clustersummaryreconciler"),
 	}
 }
 
@@ -465,7 +753,7 @@ func getSetReconciler(mgr manager.Manager) *controllers.SetReconciler {
 	return &controllers.SetReconciler{
 		Client:               mgr.GetClient(),
 		Scheme:               mgr.GetScheme(),
-		ConcurrentReconciles: concurrentReconciles,
+		ConcurrentReconciles: getConcurrentReconciles(profileReconciles),
 		Mux:                  sync.Mutex{},
 		ClusterMap:           make(map[corev1.ObjectReference]*libsveltosset.Set),
 		SetMap:               make(map[corev1.ObjectReference]*libsveltosset.Set),
@@ -479,7 +767,7 @@ func getClusterSetReconciler(mgr manager.Manager) *controllers.ClusterSetReconci
 	return &controllers.ClusterSetReconciler{
 		Client:               mgr.GetClient(),
 		Scheme:               mgr.GetScheme(),
-		ConcurrentReconciles: concurrentReconciles,
+		ConcurrentReconciles: getConcurrentReconciles(profileReconciles),
 		Mux:                  sync.Mutex{},
 		ClusterMap:           make(map[corev1.ObjectReference]*libsveltosset.Set),
 		ClusterSetMap:        make(map[corev1.ObjectReference]*libsveltosset.Set),
@@ -489,6 +777,24 @@ func getClusterSetReconciler(mgr manager.Manager) *controllers.ClusterSetReconci
 	}
 }
 
+// getConcurrentReconciles returns the per controller concurrent reconciles, if set.
+// Otherwise the value set via concurrent-reconciles.
+func getConcurrentReconciles(perController int) int {
+	if perController > 0 {
+		return perController
+	}
+	return concurrentReconciles
+}
+
+// getLeaderElectionID returns the leader election ID. Each shard has its own leader.
+func getLeaderElectionID() string {
+	const leaderElectionID = "addon-controller.projectsveltos.io"
+	if shardKey != "" {
+		return fmt.Sprintf("%s-%s", shardKey, leaderElectionID)
+	}
+	return leaderElectionID
+}
+
 // getDiagnosticsOptions returns metrics options which can be used to configure a Manager.
 func getDiagnosticsOptions() metricsserver.Options {
 	// If "--insecure-diagnostics" is set, serve metrics via http
@@ -502,21 +808,165 @@ func getDiagnosticsOptions() metricsserver.Options {
 
 	// If "--insecure-diagnostics" is not set, serve metrics via https
 	// and with authentication/authorization. As the endpoint is protected,
-	// we also serve pprof endpoints and an endpoint to change the log level.
-	return metricsserver.Options{
+	// we also serve pprof endpoints (unless disabled) and an endpoint to change the log level.
+	options := metricsserver.Options{
 		BindAddress:    diagnosticsAddress,
 		SecureServing:  true,
 		FilterProvider: filters.WithAuthenticationAndAuthorization,
-		ExtraHandlers: map[string]http.Handler{
+		// When not set, a self-signed certificate is generated
+		CertDir: diagnosticsCertDir,
+	}
+
+	// If "--diagnostics-client-ca-file" is set, clients are authenticated via certificates
+	// signed by that CA instead of via tokens. Certificate CommonName is the user (Organizations are
+	// the groups) and, as for tokens, the user is authorized via SubjectAccessReviews
+	if diagnosticsClientCAFile != "" {
+		clientCA, err := getClientCA(diagnosticsClientCAFile)
+		if err != nil {
+			setupLog.Error(err, "failed to load diagnostics client CA")
+			os.Exit(1)
+		}
+		verifyOptions, _ := clientCA.VerifyOptions()
+		options.FilterProvider = withClientCertificateAuthenticationAndAuthorization(clientCA)
+		options.TLSOpts = append(options.TLSOpts, func(c *tls.Config) {
+			c.ClientCAs = verifyOptions.Roots
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		})
+	}
+
+	if enablePprof {
+		options.ExtraHandlers = map[string]http.Handler{
 			// Add pprof handler.
-			"/debug/pprof/":        http.HandlerFunc(pprof.Index),
-			"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
-			"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
-			"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
-			"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
-			"/debug/pprof/heap":    pprof.Handler("heap"),
-		},
+			"/debug/pprof/":             http.HandlerFunc(pprof.Index),
+			"/debug/pprof/cmdline":      http.HandlerFunc(pprof.Cmdline),
+			"/debug/pprof/profile":      http.HandlerFunc(pprof.Profile),
+			"/debug/pprof/symbol":       http.HandlerFunc(pprof.Symbol),
+			"/debug/pprof/trace":        http.HandlerFunc(pprof.Trace),
+			"/debug/pprof/heap":         pprof.Handler("heap"),
+			"/debug/pprof/goroutine":    pprof.Handler("goroutine"),
+			"/debug/pprof/allocs":       pprof.Handler("allocs"),
+			"/debug/pprof/block":        pprof.Handler("block"),
+			"/debug/pprof/mutex":        pprof.Handler("mutex"),
+			"/debug/pprof/threadcreate": pprof.Handler("threadcreate"),
+		}
+	}
+
+	return options
+}
+
+// getClientCA returns the CAs contained in the PEM file
+func getClientCA(caFile string) (dynamiccertificates.CAContentProvider, error) {
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
 	}
+
+	return dynamiccertificates.NewStaticCAContent("diagnostics-client-ca", caCert)
+}
+
+// withClientCertificateAuthenticationAndAuthorization returns a metrics FilterProvider which
+// authenticates clients via their certificate and authorizes them via SubjectAccessReviews.
+// It mirrors filters.WithAuthenticationAndAuthorization, which only supports tokens.
+func withClientCertificateAuthenticationAndAuthorization(clientCA dynamiccertificates.CAContentProvider,
+) func(config *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+
+	return func(config *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+		authorizationV1Client, err := authorizationv1.NewForConfigAndClient(config, httpClient)
+		if err != nil {
+			return nil, err
+		}
+
+		authenticatorConfig := authenticatorfactory.DelegatingAuthenticatorConfig{
+			Anonymous:                          &apiserver.AnonymousAuthConfig{Enabled: false}, // Require authentication.
+			ClientCertificateCAContentProvider: clientCA,
+		}
+		certificateAuthenticator, _, err := authenticatorConfig.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
+
+		authorizerConfig := authorizerfactory.DelegatingAuthorizerConfig{
+			SubjectAccessReviewClient: authorizationV1Client,
+			AllowCacheTTL:             5 * time.Minute,
+			DenyCacheTTL:              30 * time.Second,
+			WebhookRetryBackoff: &wait.Backoff{
+				Duration: 500 * time.Millisecond,
+				Factor:   1.5,
+				Jitter:   0.2,
+				Steps:    5,
+			},
+		}
+		delegatingAuthorizer, err := authorizerConfig.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authorizer: %w", err)
+		}
+
+		return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				res, ok, err := certificateAuthenticator.AuthenticateRequest(req)
+				if err != nil {
+					log.V(logsettings.LogInfo).Info(fmt.Sprintf("client certificate authentication failed: %v", err))
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				if !ok {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+
+				attributes := authorizer.AttributesRecord{
+					User: res.User,
+					Verb: strings.ToLower(req.Method),
+					Path: req.URL.Path,
+				}
+
+				authorized, reason, err := delegatingAuthorizer.Authorize(req.Context(), attributes)
+				if err != nil {
+					msg := fmt.Sprintf("Authorization for user %s failed", attributes.User.GetName())
+					log.Error(err, msg)
+					http.Error(w, msg, http.StatusInternalServerError)
+					return
+				}
+				if authorized != authorizer.DecisionAllow {
+					msg := fmt.Sprintf("Authorization denied for user %s", attributes.User.GetName())
+					log.V(logsettings.LogDebug).Info(fmt.Sprintf("%s: %s", msg, reason))
+					http.Error(w, msg, http.StatusForbidden)
+					return
+				}
+
+				handler.ServeHTTP(w, req)
+			}), nil
+		}, nil
+	}
+}
+
+// setupTracing registers a global TracerProvider exporting spans via OTLP/gRPC.
+// TracerProvider is flushed and shut down when manager stops.
+func setupTracing(ctx context.Context, mgr manager.Manager) error {
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName("addon-controller"),
+			semconv.ServiceVersion(version)))
+	if err != nil {
+		return err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return tp.Shutdown(context.Background())
+	}))
 }
 
 // startControllers starts all reconcilers:
@@ -527,7 +977,7 @@ func getDiagnosticsOptions() metricsserver.Options {
 // It also starts needed watchers:
 // - cluster API watchers for ClusterProfile/Profile, ClusterSet/Set
 // - Flux watcher for ClusterSummary
-func startControllersAndWatchers(ctx context.Context, mgr manager.Manager) {
+func startControllersAndWatchers(ctx, deployerCtx context.Context, mgr manager.Manager) {
 	var clusterProfileReconciler *controllers.ClusterProfileReconciler
 	var profileReconciler *controllers.ProfileReconciler
 	var clusterSetReconciler *controllers.ClusterSetReconciler
@@ -577,7 +1027,7 @@ func startControllersAndWatchers(ctx context.Context, mgr manager.Manager) {
 		watchersForCAPI = append(watchersForCAPI, setReconciler)
 	}
 
-	clusterSummaryReconciler := getClusterSummaryReconciler(ctx, mgr)
+	clusterSummaryReconciler := getClusterSummaryReconciler(deployerCtx, mgr)
 	err = clusterSummaryReconciler.SetupWithManager(ctx, mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", configv1beta1.ClusterSummaryKind)
@@ -25,8 +25,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/set"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
-	libsveltosset "github.com/projectsveltos/libsveltos/lib/set"
 )
 
 // Multiple ClusterProfiles can:
@@ -65,7 +65,7 @@ type instance struct {
 	perClusterChartMap map[string]map[string][]string
 
 	// list of tracked clusters
-	clusters *libsveltosset.Set
+	clusters *set.ObjectSet
 }
 
 type HelmReleaseInfo struct {
@@ -92,7 +92,7 @@ func GetChartManagerInstance(ctx context.Context, c client.Client) (*instance, e
 				perClusterChartMap: make(map[string]map[string][]string),
 				chartMux:           sync.Mutex{},
 			}
-			managerInstance.clusters = &libsveltosset.Set{}
+			managerInstance.clusters = &set.ObjectSet{}
 			if err := managerInstance.rebuildRegistrations(ctx, c); err != nil {
 				managerInstance = nil
 				return nil, err
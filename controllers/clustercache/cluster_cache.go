@@ -19,9 +19,11 @@ package clustercache
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2/textlogger"
@@ -29,17 +31,29 @@ import (
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/projectsveltos/addon-controller/pkg/set"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
-	libsveltosset "github.com/projectsveltos/libsveltos/lib/set"
 )
 
 var (
 	managerInstance *clusterCache
 	lock            = &sync.Mutex{}
+
+	// remoteQPS and remoteBurst, when set, override the rate limits of clients
+	// used to reach managed clusters
+	remoteQPS   float32
+	remoteBurst int
 )
 
+// SetRemoteClientRateLimits sets QPS and Burst for clients used to reach managed clusters.
+// Zero values keep the ones set in the cluster kubeconfig.
+func SetRemoteClientRateLimits(qps float32, burst int) {
+	remoteQPS = qps
+	remoteBurst = burst
+}
+
 type clusterCache struct {
 	rwMux sync.RWMutex
 	// Keeps cache of rest.Config for existing clusters
@@ -50,7 +64,7 @@ type clusterCache struct {
 
 	// key: secret, value: set of clusters
 	// A secret can potentially contain kubeconfig for one or more clusters
-	secrets map[corev1.ObjectReference]*libsveltosset.Set
+	secrets map[corev1.ObjectReference]*set.ObjectSet
 }
 
 // GetManager return manager instance
@@ -62,7 +76,7 @@ func GetManager() *clusterCache {
 			managerInstance = &clusterCache{
 				configs:  make(map[corev1.ObjectReference]*rest.Config),
 				clusters: make(map[corev1.ObjectReference]*corev1.ObjectReference),
-				secrets:  make(map[corev1.ObjectReference]*libsveltosset.Set),
+				secrets:  make(map[corev1.ObjectReference]*set.ObjectSet),
 				rwMux:    sync.RWMutex{},
 			}
 		}
@@ -103,8 +117,8 @@ func (m *clusterCache) RemoveSecret(sec *corev1.ObjectReference) {
 
 	clusters := v.Items()
 	for i := range clusters {
-		delete(m.configs, clusters[i])
-		delete(m.clusters, clusters[i])
+		delete(m.configs, clusters[i].ObjectReference())
+		delete(m.clusters, clusters[i].ObjectReference())
 	}
 }
 
@@ -118,8 +132,12 @@ func (m *clusterCache) GetKubernetesRestConfig(ctx context.Context, mgmtClient c
 
 	if adminNamespace != "" || adminName != "" {
 		// cluster configs for admins are not cached
-		return clusterproxy.GetKubernetesRestConfig(ctx, mgmtClient, clusterNamespace, clusterName,
+		remoteRestConfig, err := clusterproxy.GetKubernetesRestConfig(ctx, mgmtClient, clusterNamespace, clusterName,
 			adminNamespace, adminName, clusterType, logger)
+		if err != nil {
+			return nil, err
+		}
+		return withTracing(withRateLimits(remoteRestConfig)), nil
 	}
 
 	m.rwMux.Lock()
@@ -139,6 +157,7 @@ func (m *clusterCache) GetKubernetesRestConfig(ctx context.Context, mgmtClient c
 	if err != nil {
 		return nil, err
 	}
+	remoteRestConfig = withTracing(withRateLimits(remoteRestConfig))
 
 	secretInfo, err := getSecretObjectReference(ctx, mgmtClient, clusterNamespace, clusterName, clusterType)
 	if err == nil {
@@ -147,9 +166,9 @@ func (m *clusterCache) GetKubernetesRestConfig(ctx context.Context, mgmtClient c
 		m.clusters[*cluster] = secretInfo
 		v, ok := m.secrets[*secretInfo]
 		if !ok {
-			v = &libsveltosset.Set{}
+			v = &set.ObjectSet{}
 		}
-		v.Insert(cluster)
+		v.Insert(set.KeyFromObjectReference(cluster))
 		m.secrets[*secretInfo] = v
 	}
 
@@ -187,15 +206,36 @@ func (m *clusterCache) StoreRestConfig(clusterNamespace, clusterName string,
 }
 
 func (m *clusterCache) updateSecretMap(sec, cluster *corev1.ObjectReference) {
-	set, ok := m.secrets[*sec]
+	clusters, ok := m.secrets[*sec]
 	if ok {
-		set.Erase(cluster)
-		if set.Len() == 0 {
+		clusters.Erase(set.KeyFromObjectReference(cluster))
+		if clusters.Len() == 0 {
 			delete(m.secrets, *sec)
 		}
 	}
 }
 
+// withRateLimits sets QPS and Burst, if configured, for requests made using config to
+// managed cluster API server
+func withRateLimits(config *rest.Config) *rest.Config {
+	if remoteQPS != 0 {
+		config.QPS = remoteQPS
+	}
+	if remoteBurst != 0 {
+		config.Burst = remoteBurst
+	}
+	return config
+}
+
+// withTracing instruments all requests made using config to managed cluster API server.
+// If tracing is not enabled, spans are no-op.
+func withTracing(config *rest.Config) *rest.Config {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(rt)
+	})
+	return config
+}
+
 func getClusterObjectReference(clusterNamespace, clusterName string,
 	clusterType libsveltosv1beta1.ClusterType) *corev1.ObjectReference {
 
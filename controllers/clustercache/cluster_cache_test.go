@@ -25,6 +25,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2/textlogger"
 
 	"github.com/projectsveltos/addon-controller/controllers/clustercache"
@@ -49,6 +50,21 @@ var _ = Describe("Clustercache", func() {
 		}
 	})
 
+	It("withRateLimits overrides QPS and Burst only when set", func() {
+		const qps, burst = 5, 10
+		config := clustercache.WithRateLimits(&rest.Config{QPS: qps, Burst: burst})
+		Expect(config.QPS).To(Equal(float32(qps)))
+		Expect(config.Burst).To(Equal(burst))
+
+		const remoteQPS, remoteBurst = 50, 100
+		clustercache.SetRemoteClientRateLimits(remoteQPS, remoteBurst)
+		defer clustercache.SetRemoteClientRateLimits(0, 0)
+
+		config = clustercache.WithRateLimits(&rest.Config{QPS: qps, Burst: burst})
+		Expect(config.QPS).To(Equal(float32(remoteQPS)))
+		Expect(config.Burst).To(Equal(remoteBurst))
+	})
+
 	It("GetKubernetesRestConfig stores in memory first time. RemoveCluster removes any entry associated to cluster",
 		func() {
 			secret := createClusterResources(cluster)
@@ -40,5 +40,10 @@ func (m *clusterCache) GetClusterFromSecret(secret *corev1.ObjectReference) *cor
 	}
 
 	items := set.Items()
-	return &items[0]
+	cluster := items[0].ObjectReference()
+	return &cluster
 }
+
+var (
+	WithRateLimits = withRateLimits
+)
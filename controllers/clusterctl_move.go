@@ -0,0 +1,146 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/scope"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// deployedFeaturesAnnotation is set on ClusterSummary and contains the hash of each
+	// provisioned feature. clusterctl move does not preserve Status, so this is used to
+	// re-adopt a moved ClusterSummary without redeploying all of its features.
+	deployedFeaturesAnnotation = "projectsveltos.io/deployed-features"
+)
+
+// isDeletedForMove returns true if ClusterSummary is being deleted by clusterctl move.
+// The ClusterSummary has already been recreated in the target management cluster, so
+// nothing must be removed from the managed cluster.
+func isDeletedForMove(clusterSummary *configv1beta1.ClusterSummary) bool {
+	annotations := clusterSummary.GetAnnotations()
+	if annotations == nil {
+		return false
+	}
+	_, ok := annotations[clusterctlv1.DeleteForMoveAnnotation]
+	return ok
+}
+
+// getDeployedFeatures returns, for each provisioned feature, the hash reported in Status
+func getDeployedFeatures(clusterSummary *configv1beta1.ClusterSummary) map[configv1beta1.FeatureID][]byte {
+	deployed := make(map[configv1beta1.FeatureID][]byte)
+	for i := range clusterSummary.Status.FeatureSummaries {
+		fs := &clusterSummary.Status.FeatureSummaries[i]
+		if fs.Status == configv1beta1.FeatureStatusProvisioned && fs.Hash != nil {
+			deployed[fs.FeatureID] = fs.Hash
+		}
+	}
+	return deployed
+}
+
+// storeDeployedFeatures persists, in the deployedFeaturesAnnotation, the hash of each
+// provisioned feature.
+func storeDeployedFeatures(clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) {
+	clusterSummary := clusterSummaryScope.ClusterSummary
+
+	deployed := getDeployedFeatures(clusterSummary)
+	if len(deployed) == 0 {
+		return
+	}
+
+	value, err := json.Marshal(deployed)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to marshal deployed features: %v", err))
+		return
+	}
+
+	annotations := clusterSummary.GetAnnotations()
+	if annotations != nil && annotations[deployedFeaturesAnnotation] == string(value) {
+		return
+	}
+	addAnnotation(clusterSummary, deployedFeaturesAnnotation, string(value))
+}
+
+// restoreDeployedFeatures is used when a ClusterSummary is re-created by clusterctl move.
+// If Status does not report any feature, the provisioned features (and their hash) are
+// restored from the deployedFeaturesAnnotation. Features whose configuration has not changed
+// won't be redeployed.
+func restoreDeployedFeatures(clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) {
+	clusterSummary := clusterSummaryScope.ClusterSummary
+	if len(clusterSummary.Status.FeatureSummaries) != 0 {
+		return
+	}
+
+	annotations := clusterSummary.GetAnnotations()
+	if annotations == nil {
+		return
+	}
+	value, ok := annotations[deployedFeaturesAnnotation]
+	if !ok {
+		return
+	}
+
+	deployed := make(map[configv1beta1.FeatureID][]byte)
+	if err := json.Unmarshal([]byte(value), &deployed); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to unmarshal deployed features: %v", err))
+		return
+	}
+
+	for featureID, hash := range deployed {
+		logger.V(logs.LogDebug).Info("restoring feature status", "feature", featureID)
+		clusterSummaryScope.SetFeatureStatus(featureID, configv1beta1.FeatureStatusProvisioned, hash)
+	}
+}
+
+// preserveDeployedFeaturesAnnotation copies the deployedFeaturesAnnotation from current annotations
+// to the new ones. Returns true if new annotations (ignoring the deployedFeaturesAnnotation) are
+// same as the current ones.
+func preserveDeployedFeaturesAnnotation(current, annotations map[string]string) (map[string]string, bool) {
+	value, ok := current[deployedFeaturesAnnotation]
+	if !ok {
+		return annotations, reflect.DeepEqual(current, annotations)
+	}
+
+	result := make(map[string]string, len(annotations)+1)
+	for k := range annotations {
+		result[k] = annotations[k]
+	}
+	result[deployedFeaturesAnnotation] = value
+	return result, reflect.DeepEqual(current, result)
+}
+
+// hasOwnerWithUID returns true if one of the object OwnerReferences has the given UID.
+// clusterctl move preserves OwnerReferences by name, but owner UID might be different in the
+// target management cluster.
+func hasOwnerWithUID(obj metav1.Object, uid types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/go-logr/logr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -106,6 +107,16 @@ func (p ClusterPredicate) Update(obj event.TypedUpdateEvent[*clusterv1.Cluster])
 		return true
 	}
 
+	// return true if Cluster ControlPlaneInitialized condition has changed. Sveltos considers a cluster
+	// ready to be configured as soon as its control plane is initialized.
+	if conditions.IsTrue(oldCluster, clusterv1.ControlPlaneInitializedCondition) !=
+		conditions.IsTrue(newCluster, clusterv1.ControlPlaneInitializedCondition) {
+
+		log.V(logs.LogVerbose).Info(
+			"Cluster ControlPlaneInitialized changed. Will attempt to reconcile associated (Cluster)Profiles/(Cluster)Set.")
+		return true
+	}
+
 	// otherwise, return false
 	log.V(logs.LogVerbose).Info(
 		`Cluster did not match expected conditions. \
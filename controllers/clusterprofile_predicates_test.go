@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -331,6 +332,41 @@ var _ = Describe("ClusterProfile Predicates: ClusterPredicates", func() {
 			},
 		}
 
+		result := clusterPredicate.Update(event.TypedUpdateEvent[*clusterv1.Cluster]{ObjectNew: cluster, ObjectOld: oldCluster})
+		Expect(result).To(BeTrue())
+	})
+	It("Update reprocesses when v1Cluster ControlPlaneReady changes", func() {
+		clusterPredicate := controllers.ClusterPredicate{Logger: logger}
+
+		cluster.Status.ControlPlaneReady = true
+
+		oldCluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+		}
+
+		result := clusterPredicate.Update(event.TypedUpdateEvent[*clusterv1.Cluster]{ObjectNew: cluster, ObjectOld: oldCluster})
+		Expect(result).To(BeTrue())
+	})
+	It("Update reprocesses when v1Cluster ControlPlaneInitialized condition becomes true", func() {
+		clusterPredicate := controllers.ClusterPredicate{Logger: logger}
+
+		cluster.Status.Conditions = clusterv1.Conditions{
+			{Type: clusterv1.ControlPlaneInitializedCondition, Status: corev1.ConditionTrue},
+		}
+
+		oldCluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+		}
+		oldCluster.Status.Conditions = clusterv1.Conditions{
+			{Type: clusterv1.ControlPlaneInitializedCondition, Status: corev1.ConditionFalse},
+		}
+
 		result := clusterPredicate.Update(event.TypedUpdateEvent[*clusterv1.Cluster]{ObjectNew: cluster, ObjectOld: oldCluster})
 		Expect(result).To(BeTrue())
 	})
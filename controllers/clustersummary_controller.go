@@ -27,12 +27,16 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,6 +45,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -65,6 +70,10 @@ const (
 	// to ready after or workload features (for instance ingress or reporter) have failed
 	normalRequeueAfter = 10 * time.Second
 
+	// clusterNotReadyRequeueAfter is how long to wait before checking again whether the cluster
+	// control plane is ready
+	clusterNotReadyRequeueAfter = 30 * time.Second
+
 	// dryRunRequeueAfter is how long to wait before reconciling a ClusterSummary in DryRun mode
 	dryRunRequeueAfter = 20 * time.Second
 )
@@ -99,18 +108,36 @@ type ClusterSummaryReconciler struct {
 	ClusterMap           map[corev1.ObjectReference]*libsveltosset.Set // key: Sveltos/Cluster; value: set of all ClusterSummaries for that Cluster
 
 	ConflictRetryTime time.Duration
-	ctrl              controller.Controller
+	// FullResyncPeriod, when set, forces a deployed feature to be re-applied once this period has
+	// elapsed since it was last applied, even if its configuration has not changed.
+	FullResyncPeriod time.Duration
+	// StuckFeatureThreshold, when set, is the time after which a feature still in Provisioning
+	// or Failed state causes the ClusterSummary Degraded condition to be set.
+	StuckFeatureThreshold time.Duration
+	// RemoteCleanupOnClusterDeletion, when set, causes features to be removed from a managed
+	// cluster also when the CAPI Cluster itself is being deleted. Otherwise, only management cluster
+	// state is cleaned.
+	RemoteCleanupOnClusterDeletion bool
+	// EventRecorder, when set, is used to emit Kubernetes Events on ClusterSummary instances
+	// when a feature fails or gets provisioned.
+	EventRecorder record.EventRecorder
+	// ProtectReferencedResources, when set, causes ConfigMaps/Secrets referenced by a ClusterSummary
+	// to be protected, by a finalizer, from deletion while still in use.
+	ProtectReferencedResources bool
+
+	ctrl controller.Controller
 }
 
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clustersummaries,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clustersummaries/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clustersummaries/finalizers,verbs=update;patch
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clusterconfigurations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.projectsveltos.io,resources=templatelibraries,verbs=get;list;watch
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clusterconfigurations/status,verbs=get;list;update
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clusterreports,verbs=get;list;watch
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clusterreports/status,verbs=get;list;update
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch;update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;patch;update
 //+kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;watch;list
 //+kubebuilder:rbac:groups="infrastructure.cluster.x-k8s.io",resources="*",verbs=get;watch;list
 //+kubebuilder:rbac:groups="source.toolkit.fluxcd.io",resources=gitrepositories,verbs=get;watch;list
@@ -119,11 +146,18 @@ type ClusterSummaryReconciler struct {
 //+kubebuilder:rbac:groups="source.toolkit.fluxcd.io",resources=ocirepositories/status,verbs=get;watch;list
 //+kubebuilder:rbac:groups="source.toolkit.fluxcd.io",resources=buckets,verbs=get;watch;list
 //+kubebuilder:rbac:groups="source.toolkit.fluxcd.io",resources=buckets/status,verbs=get;watch;list
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *ClusterSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	logger := ctrl.LoggerFrom(ctx)
 	logger.V(logs.LogInfo).Info("Reconciling")
 
+	ctx, span := getTracer().Start(ctx, "ClusterSummary.Reconcile",
+		trace.WithAttributes(clusterSummaryAttribute.String(req.String())))
+	defer func() {
+		endSpan(span, reterr)
+	}()
+
 	// Fecth the clusterSummary instance
 	clusterSummary := &configv1beta1.ClusterSummary{}
 	if err := r.Get(ctx, req.NamespacedName, clusterSummary); err != nil {
@@ -137,6 +171,10 @@ func (r *ClusterSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		)
 	}
 
+	span.SetAttributes(clusterNamespaceAttribute.String(clusterSummary.Spec.ClusterNamespace),
+		clusterNameAttribute.String(clusterSummary.Spec.ClusterName),
+		clusterTypeAttribute.String(string(clusterSummary.Spec.ClusterType)))
+
 	// Fetch the (Cluster)Profile.
 	profile, _, err := configv1beta1.GetProfileOwnerAndTier(ctx, r.Client, clusterSummary)
 	if err != nil {
@@ -202,14 +240,30 @@ func (r *ClusterSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}, nil
 	}
 	if !isReady {
-		logger.V(logs.LogInfo).Info("cluster is not ready.")
-		r.setFailureMessage(clusterSummaryScope, "cluster is not ready")
-		r.resetFeatureStatus(clusterSummaryScope, configv1beta1.FeatureStatusFailed)
-		// if cluster is not ready, do nothing and don't queue for reconciliation.
-		// When cluster becomes ready, all matching clusterSummaries will be requeued for reconciliation
+		isPresent, _, err := r.isClusterPresent(ctx, clusterSummaryScope)
+		if err != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}, nil
+		}
+		if !isPresent {
+			// Cluster does not exist. Its creation causes a reconciliation (cluster map is updated below),
+			// so do not requeue.
+			logger.V(logs.LogDebug).Info("cluster not found.")
+			r.setFailureMessage(clusterSummaryScope, "cluster not found")
+			r.resetFeatureStatus(clusterSummaryScope, configv1beta1.FeatureStatusProvisioning)
+			_ = r.updateMaps(clusterSummaryScope, logger)
+			return reconcile.Result{}, nil
+		}
+
+		// Control plane is not ready/initialized yet (cluster is being provisioned). This is not a failure,
+		// features are reported as Provisioning and nothing is deployed till the API server is reachable.
+		logger.V(logs.LogDebug).Info("cluster control plane is not ready yet.")
+		r.setFailureMessage(clusterSummaryScope, "waiting for cluster control plane to be ready")
+		r.resetFeatureStatus(clusterSummaryScope, configv1beta1.FeatureStatusProvisioning)
 		_ = r.updateMaps(clusterSummaryScope, logger)
 
-		return reconcile.Result{}, nil
+		// Cluster changes to ControlPlaneReady cause a reconciliation. ControlPlaneInitialized condition
+		// changes do not, so requeue.
+		return reconcile.Result{Requeue: true, RequeueAfter: clusterNotReadyRequeueAfter}, nil
 	}
 
 	// Handle non-deleted clusterSummary
@@ -224,6 +278,14 @@ func (r *ClusterSummaryReconciler) reconcileDelete(
 
 	logger.V(logs.LogInfo).Info("Reconciling ClusterSummary delete")
 
+	if isDeletedForMove(clusterSummaryScope.ClusterSummary) {
+		// ClusterSummary has been moved to a different management cluster by clusterctl move.
+		// Deployed features must not be removed from the managed cluster.
+		logger.V(logs.LogInfo).Info("ClusterSummary deleted for move. Skip cleanup.")
+		r.cleanupQueuedCleanOperations(clusterSummaryScope.ClusterSummary)
+		return r.completeDelete(ctx, clusterSummaryScope, logger)
+	}
+
 	isReady, err := r.isReady(ctx, clusterSummaryScope.ClusterSummary, logger)
 	if err != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: deleteRequeueAfter}, nil
@@ -234,12 +296,19 @@ func (r *ClusterSummaryReconciler) reconcileDelete(
 	if err != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: deleteRequeueAfter}, nil
 	}
-	if isPresent && isReady { // if cluster is not ready, do not try to clean up. It would fail.
+	// When the cluster is being deleted, its API server is going away. Unless configured otherwise,
+	// only the management cluster is cleaned. When only the ClusterSummary is deleted, finalizer is
+	// removed only once all features have been verifiably removed from the managed cluster.
+	managementClusterOnly := r.isManagementClusterOnlyCleanup(clusterSummaryScope.ClusterSummary, isDeleted)
+
+	// if cluster is not ready, do not try to clean up the managed cluster. It would fail.
+	if isPresent && (isReady || managementClusterOnly) {
 		// Cleanup
 		paused, err := r.isPaused(ctx, clusterSummaryScope.ClusterSummary)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		r.setPausedCondition(clusterSummaryScope, paused)
 		if paused {
 			logger.V(logs.LogInfo).Info("cluster is paused. Do nothing.")
 			return reconcile.Result{}, nil
@@ -257,7 +326,7 @@ func (r *ClusterSummaryReconciler) reconcileDelete(
 
 		// still call undeploy even if cluster is deleted. Sveltos might have deployed resources
 		// in the management cluster and those need to be removed.
-		err = r.undeploy(ctx, clusterSummaryScope, logger)
+		err = r.undeploy(ctx, clusterSummaryScope, managementClusterOnly, logger)
 		if err != nil {
 			// In DryRun mode it is expected to always get an error back
 			if !clusterSummaryScope.IsDryRunSync() {
@@ -289,6 +358,17 @@ func (r *ClusterSummaryReconciler) reconcileDelete(
 	}
 
 	// Cluster is not present anymore or cleanup succeeded
+	return r.completeDelete(ctx, clusterSummaryScope, logger)
+}
+
+// completeDelete removes ClusterSummary finalizer and cleans all in-memory state
+// associated to the ClusterSummary
+func (r *ClusterSummaryReconciler) completeDelete(
+	ctx context.Context,
+	clusterSummaryScope *scope.ClusterSummaryScope,
+	logger logr.Logger,
+) (reconcile.Result, error) {
+
 	logger.V(logs.LogInfo).Info("Removing finalizer")
 	if controllerutil.ContainsFinalizer(clusterSummaryScope.ClusterSummary, configv1beta1.ClusterSummaryFinalizer) {
 		if finalizersUpdated := controllerutil.RemoveFinalizer(clusterSummaryScope.ClusterSummary,
@@ -301,16 +381,32 @@ func (r *ClusterSummaryReconciler) reconcileDelete(
 		return reconcile.Result{}, err
 	}
 
+	if err := r.releaseReferencedResources(ctx, clusterSummaryScope.ClusterSummary, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	r.cleanMaps(clusterSummaryScope)
+	r.cleanFeaturesConvergence(clusterSummaryScope.ClusterSummary)
+	removeClusterSummaryCheckpoints(clusterSummaryScope.ClusterSummary)
 
 	manager := getManager()
-	manager.stopStaleWatchForTemplateResourceRef(clusterSummaryScope.ClusterSummary, true)
+	manager.stopStaleWatchForTemplateResourceRef(ctx, clusterSummaryScope.ClusterSummary, true)
 
 	logger.V(logs.LogInfo).Info("Reconcile delete success")
 
 	return reconcile.Result{}, nil
 }
 
+// isManagementClusterOnlyCleanup returns true if, on ClusterSummary deletion, only management cluster
+// state must be cleaned. That is the case only for a CAPI Cluster being deleted, as its infrastructure
+// (and so its API server) is going away. Deleting a SveltosCluster does not remove the cluster itself.
+func (r *ClusterSummaryReconciler) isManagementClusterOnlyCleanup(clusterSummary *configv1beta1.ClusterSummary,
+	isClusterDeleted bool) bool {
+
+	return isClusterDeleted && !r.RemoteCleanupOnClusterDeletion &&
+		clusterSummary.Spec.ClusterType == libsveltosv1beta1.ClusterTypeCapi
+}
+
 func (r *ClusterSummaryReconciler) reconcileNormal(
 	ctx context.Context,
 	clusterSummaryScope *scope.ClusterSummaryScope,
@@ -336,15 +432,27 @@ func (r *ClusterSummaryReconciler) reconcileNormal(
 		return reconcile.Result{}, err
 	}
 
+	err = r.protectReferencedResources(ctx, clusterSummaryScope, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Error(err, "failed to protect referenced resources")
+		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}, nil
+	}
+
 	paused, err := r.isPaused(ctx, clusterSummaryScope.ClusterSummary)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	r.setPausedCondition(clusterSummaryScope, paused)
 	if paused {
 		logger.V(logs.LogInfo).Info("cluster is paused. Do nothing.")
 		return reconcile.Result{}, nil
 	}
 
+	err = r.validateReferences(ctx, clusterSummaryScope, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Error(err, "failed to validate referenced resources")
+	}
+
 	err = r.startWatcherForTemplateResourceRefs(ctx, clusterSummaryScope.ClusterSummary)
 	if err != nil {
 		logger.V(logs.LogInfo).Error(err, "failed to start watcher on resources referenced in TemplateResourceRefs.")
@@ -373,7 +481,14 @@ func (r *ClusterSummaryReconciler) reconcileNormal(
 		}
 	}
 
+	// ClusterSummary re-created by clusterctl move has no Status.
+	restoreDeployedFeatures(clusterSummaryScope, logger)
+
 	err = r.deploy(ctx, clusterSummaryScope, logger)
+	if !clusterSummaryScope.IsDryRunSync() {
+		storeDeployedFeatures(clusterSummaryScope, logger)
+	}
+	stuckRequeueAfter := r.updateFeaturesConvergence(clusterSummaryScope, logger)
 	if err != nil {
 		var conflictErr *deployer.ConflictError
 		ok := errors.As(err, &conflictErr)
@@ -381,6 +496,10 @@ func (r *ClusterSummaryReconciler) reconcileNormal(
 			logger.V(logs.LogInfo).Error(err, "failed to deploy because of conflict")
 			return reconcile.Result{Requeue: true, RequeueAfter: r.ConflictRetryTime}, nil
 		}
+		if isDeploymentTimeoutOnly(err) {
+			logger.V(logs.LogInfo).Error(err, "failed to deploy because of timeout")
+			return reconcile.Result{Requeue: true, RequeueAfter: deploymentTimeoutBackoff}, nil
+		}
 		logger.V(logs.LogInfo).Error(err, "failed to deploy")
 		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}, nil
 	}
@@ -393,11 +512,39 @@ func (r *ClusterSummaryReconciler) reconcileNormal(
 		return reconcile.Result{Requeue: true, RequeueAfter: dryRunRequeueAfter}, nil
 	}
 
+	// Reconcile again when the earliest full resync is due and, if a feature is not provisioned yet,
+	// once it should be considered stuck.
+	requeueAfter := r.getFullResyncRequeueAfter(clusterSummaryScope)
+	if stuckRequeueAfter != 0 && (requeueAfter == 0 || stuckRequeueAfter < requeueAfter) {
+		requeueAfter = stuckRequeueAfter
+	}
+	if requeueAfter != 0 {
+		return reconcile.Result{Requeue: true, RequeueAfter: requeueAfter}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterSummaryReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &configv1beta1.ClusterSummary{},
+		referencedResourceIndex, r.indexReferencedResources); err != nil {
+		return fmt.Errorf("error setting index field: %w", err)
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &configv1beta1.ClusterSummary{},
+		profileClusterIndex, indexClusterSummaryByProfileAndCluster); err != nil {
+		return fmt.Errorf("error setting index field: %w", err)
+	}
+
+	// One time cleanup of ConfigMaps/Secrets protected while ProtectReferencedResources was
+	// set differently. Afterwards, those are only listed by label and only if protection is enabled.
+	if err := mgr.Add(ctrlmanager.RunnableFunc(func(ctx context.Context) error {
+		return r.releaseAllReferencedResources(ctx, mgr.GetLogger().WithValues("cleanup", "referencedresources"))
+	})); err != nil {
+		return fmt.Errorf("error adding referenced resources cleanup: %w", err)
+	}
+
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&configv1beta1.ClusterSummary{}).
 		WithOptions(controller.Options{
@@ -421,6 +568,9 @@ func (r *ClusterSummaryReconciler) SetupWithManager(ctx context.Context, mgr ctr
 				SecretPredicates(mgr.GetLogger().WithValues("predicate", "secretpredicate")),
 			),
 		).
+		Watches(&configv1beta1.TemplateLibrary{},
+			handler.EnqueueRequestsFromMapFunc(r.requeueClusterSummaryForTemplateLibrary),
+		).
 		Build(r)
 	if err != nil {
 		return fmt.Errorf("error creating controller: %w", err)
@@ -512,37 +662,37 @@ func (r *ClusterSummaryReconciler) deploy(ctx context.Context, clusterSummarySco
 	clusterSummary := clusterSummaryScope.ClusterSummary
 	logger = logger.WithValues("clusternamespace", clusterSummary.Spec.ClusterNamespace, "clustername", clusterSummary.Spec.ClusterName)
 
-	var errs []error
-
-	resourceErr := r.deployResources(ctx, clusterSummaryScope, logger)
-
-	helmErr := r.deployHelm(ctx, clusterSummaryScope, logger)
-
-	kustomizeError := r.deployKustomizeRefs(ctx, clusterSummaryScope, logger)
-
-	if resourceErr != nil {
-		errs = append(errs, fmt.Errorf("deploying resources failed: %w", resourceErr))
-	}
-
-	if helmErr != nil {
-		errs = append(errs, fmt.Errorf("deploying helm charts failed: %w", helmErr))
-	}
-
-	if kustomizeError != nil {
-		errs = append(errs, fmt.Errorf("deploying kustomize resources failed: %w", kustomizeError))
-	}
-
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+	// Each feature might need to fetch many referenced resources before its deployment
+	// is queued. Features are independent so process those concurrently.
+	deployers := []struct {
+		description string
+		deploy      func(context.Context, *scope.ClusterSummaryScope, logr.Logger) error
+	}{
+		{description: "resources", deploy: r.deployResources},
+		{description: "helm charts", deploy: r.deployHelm},
+		{description: "kustomize resources", deploy: r.deployKustomizeRefs},
+	}
+
+	featureErrs := make([]error, len(deployers))
+	var g errgroup.Group
+	for i := range deployers {
+		g.Go(func() error {
+			if err := deployers[i].deploy(ctx, clusterSummaryScope, logger); err != nil {
+				featureErrs[i] = fmt.Errorf("deploying %s failed: %w", deployers[i].description, err)
+			}
+			// Errors are aggregated so one failing feature does not prevent others from being deployed
+			return nil
+		})
 	}
+	_ = g.Wait()
 
-	return nil
+	return errors.Join(featureErrs...)
 }
 
 func (r *ClusterSummaryReconciler) deployKustomizeRefs(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
 	if clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.KustomizationRefs == nil {
 		logger.V(logs.LogDebug).Info("no kustomize policy configuration")
-		if !r.isFeatureStatusPresent(clusterSummaryScope.ClusterSummary, configv1beta1.FeatureKustomize) {
+		if !r.hasFeatureStatus(clusterSummaryScope, configv1beta1.FeatureKustomize) {
 			logger.V(logs.LogDebug).Info("no policy status. Do not reconcile this")
 			return nil
 		}
@@ -556,7 +706,7 @@ func (r *ClusterSummaryReconciler) deployKustomizeRefs(ctx context.Context, clus
 func (r *ClusterSummaryReconciler) deployResources(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
 	if clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.PolicyRefs == nil {
 		logger.V(logs.LogDebug).Info("no policy configuration")
-		if !r.isFeatureStatusPresent(clusterSummaryScope.ClusterSummary, configv1beta1.FeatureResources) {
+		if !r.hasFeatureStatus(clusterSummaryScope, configv1beta1.FeatureResources) {
 			logger.V(logs.LogDebug).Info("no policy status. Do not reconcile this")
 			return nil
 		}
@@ -570,7 +720,7 @@ func (r *ClusterSummaryReconciler) deployResources(ctx context.Context, clusterS
 func (r *ClusterSummaryReconciler) deployHelm(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
 	if clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.HelmCharts == nil {
 		logger.V(logs.LogDebug).Info("no helm configuration")
-		if !r.isFeatureStatusPresent(clusterSummaryScope.ClusterSummary, configv1beta1.FeatureHelm) {
+		if !r.hasFeatureStatus(clusterSummaryScope, configv1beta1.FeatureHelm) {
 			logger.V(logs.LogDebug).Info("no helm status. Do not reconcile this")
 			return nil
 		}
@@ -597,14 +747,17 @@ func (r *ClusterSummaryReconciler) isClusterPresent(ctx context.Context,
 	return true, !cluster.GetDeletionTimestamp().IsZero(), err
 }
 
+// undeploy removes all features. If managementClusterOnly is set, only resources deployed
+// in the management cluster are removed and management cluster state is cleaned.
+// Nothing is removed from the managed cluster.
 func (r *ClusterSummaryReconciler) undeploy(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
-	logger logr.Logger) error {
+	managementClusterOnly bool, logger logr.Logger) error {
 
-	resourceErr := r.undeployResources(ctx, clusterSummaryScope, logger)
+	resourceErr := r.undeployResources(ctx, clusterSummaryScope, managementClusterOnly, logger)
 
-	kustomizeResourceErr := r.undeployKustomizeResources(ctx, clusterSummaryScope, logger)
+	kustomizeResourceErr := r.undeployKustomizeResources(ctx, clusterSummaryScope, managementClusterOnly, logger)
 
-	helmErr := r.undeployHelm(ctx, clusterSummaryScope, logger)
+	helmErr := r.undeployHelm(ctx, clusterSummaryScope, managementClusterOnly, logger)
 
 	if resourceErr != nil {
 		return resourceErr
@@ -621,19 +774,25 @@ func (r *ClusterSummaryReconciler) undeploy(ctx context.Context, clusterSummaryS
 	return nil
 }
 
-func (r *ClusterSummaryReconciler) undeployResources(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
+func (r *ClusterSummaryReconciler) undeployResources(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
+	managementClusterOnly bool, logger logr.Logger) error {
+
 	f := getHandlersForFeature(configv1beta1.FeatureResources)
-	return r.undeployFeature(ctx, clusterSummaryScope, f, logger)
+	return r.undeployFeature(ctx, clusterSummaryScope, f, managementClusterOnly, logger)
 }
 
-func (r *ClusterSummaryReconciler) undeployKustomizeResources(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
+func (r *ClusterSummaryReconciler) undeployKustomizeResources(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
+	managementClusterOnly bool, logger logr.Logger) error {
+
 	f := getHandlersForFeature(configv1beta1.FeatureKustomize)
-	return r.undeployFeature(ctx, clusterSummaryScope, f, logger)
+	return r.undeployFeature(ctx, clusterSummaryScope, f, managementClusterOnly, logger)
 }
 
-func (r *ClusterSummaryReconciler) undeployHelm(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
+func (r *ClusterSummaryReconciler) undeployHelm(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
+	managementClusterOnly bool, logger logr.Logger) error {
+
 	f := getHandlersForFeature(configv1beta1.FeatureHelm)
-	return r.undeployFeature(ctx, clusterSummaryScope, f, logger)
+	return r.undeployFeature(ctx, clusterSummaryScope, f, managementClusterOnly, logger)
 }
 
 func (r *ClusterSummaryReconciler) updateChartMap(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
@@ -857,9 +1016,8 @@ func (r *ClusterSummaryReconciler) getPolicyRefReferences(clusterSummaryScope *s
 		referencedNamespace := clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.PolicyRefs[i].Namespace
 		namespace := libsveltostemplate.GetReferenceResourceNamespace(clusterSummaryScope.Namespace(), referencedNamespace)
 
-		cs := clusterSummaryScope.ClusterSummary
-		referencedName, err := libsveltostemplate.GetReferenceResourceName(cs.Spec.ClusterNamespace, cs.Spec.ClusterName,
-			string(cs.Spec.ClusterType), clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.PolicyRefs[i].Name)
+		referencedName, err := getPolicyRefName(clusterSummaryScope.ClusterSummary,
+			&clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.PolicyRefs[i])
 		if err != nil {
 			return nil, err
 		}
@@ -1034,13 +1192,13 @@ func (r *ClusterSummaryReconciler) isReady(ctx context.Context,
 	return isClusterReady, nil
 }
 
-// isPaused returns true if Sveltos/Cluster is paused or ClusterSummary has paused annotation.
+// isPaused returns true if Sveltos/Cluster is paused (either Spec.Paused is set or it has the
+// cluster-api paused annotation) or ClusterSummary has paused annotation.
 func (r *ClusterSummaryReconciler) isPaused(ctx context.Context,
 	clusterSummary *configv1beta1.ClusterSummary) (bool, error) {
 
-	isClusterPaused, err := clusterproxy.IsClusterPaused(ctx, r.Client, clusterSummary.Spec.ClusterNamespace,
+	cluster, err := clusterproxy.GetCluster(ctx, r.Client, clusterSummary.Spec.ClusterNamespace,
 		clusterSummary.Spec.ClusterName, clusterSummary.Spec.ClusterType)
-
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return false, nil
@@ -1048,13 +1206,35 @@ func (r *ClusterSummaryReconciler) isPaused(ctx context.Context,
 		return false, err
 	}
 
-	if isClusterPaused {
+	if annotations.HasPaused(cluster) {
 		return true, nil
 	}
 
+	switch c := cluster.(type) {
+	case *clusterv1.Cluster:
+		if c.Spec.Paused {
+			return true, nil
+		}
+	case *libsveltosv1beta1.SveltosCluster:
+		if c.Spec.Paused {
+			return true, nil
+		}
+	}
+
 	return annotations.HasPaused(clusterSummary), nil
 }
 
+// setPausedCondition sets ClusterSummary Paused condition when paused is true and removes it otherwise
+func (r *ClusterSummaryReconciler) setPausedCondition(clusterSummaryScope *scope.ClusterSummaryScope, paused bool) {
+	if !paused {
+		clusterSummaryScope.RemoveCondition(configv1beta1.PausedCondition)
+		return
+	}
+
+	clusterSummaryScope.SetCondition(configv1beta1.PausedCondition, metav1.ConditionTrue,
+		configv1beta1.ClusterPausedReason, "cluster or ClusterSummary is paused. Nothing is deployed or withdrawn")
+}
+
 // canRemoveFinalizer returns true if finalizer can be removed.
 // A ClusterSummary in DryRun mode can be removed if deleted and ClusterProfile is also marked for deletion.
 // A ClusterSummary in not DryRun mode can be removed if deleted and all features are undeployed.
@@ -1243,7 +1423,7 @@ func (r *ClusterSummaryReconciler) areDependenciesDeployed(ctx context.Context,
 		profileName := clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.DependsOn[i]
 		logger.V(logs.LogDebug).Info(fmt.Sprintf("Considering %s %s", profileReference.Kind, profileName))
 		var cs *configv1beta1.ClusterSummary
-		cs, err = getClusterSummary(ctx, r.Client, profileReference.Kind, profileName,
+		cs, err = getClusterSummaryForProfileAndCluster(ctx, r.Client, profileReference.Kind, profileName,
 			clusterSummaryScope.ClusterSummary.Spec.ClusterNamespace, clusterSummaryScope.ClusterSummary.Spec.ClusterName,
 			clusterSummaryScope.ClusterSummary.Spec.ClusterType)
 		if err != nil {
@@ -1313,7 +1493,7 @@ func (r *ClusterSummaryReconciler) startWatcherForTemplateResourceRefs(ctx conte
 		}
 	}
 
-	manager.stopStaleWatchForTemplateResourceRef(clusterSummary, false)
+	manager.stopStaleWatchForTemplateResourceRef(ctx, clusterSummary, false)
 	return nil
 }
 
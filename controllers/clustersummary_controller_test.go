@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/textlogger"
@@ -109,6 +110,22 @@ var _ = Describe("ClustersummaryController", func() {
 			types.NamespacedName{Namespace: clusterSummary.Namespace, Name: clusterSummary.Name}, clusterSummary)).To(Succeed())
 	})
 
+	It("isManagementClusterOnlyCleanup returns true only for CAPI Clusters being deleted", func() {
+		reconciler := &controllers.ClusterSummaryReconciler{}
+
+		clusterSummary.Spec.ClusterType = libsveltosv1beta1.ClusterTypeCapi
+		Expect(controllers.IsManagementClusterOnlyCleanup(reconciler, clusterSummary, true)).To(BeTrue())
+		Expect(controllers.IsManagementClusterOnlyCleanup(reconciler, clusterSummary, false)).To(BeFalse())
+
+		// A deleted SveltosCluster does not remove the cluster, so features are removed from it
+		clusterSummary.Spec.ClusterType = libsveltosv1beta1.ClusterTypeSveltos
+		Expect(controllers.IsManagementClusterOnlyCleanup(reconciler, clusterSummary, true)).To(BeFalse())
+
+		reconciler.RemoteCleanupOnClusterDeletion = true
+		clusterSummary.Spec.ClusterType = libsveltosv1beta1.ClusterTypeCapi
+		Expect(controllers.IsManagementClusterOnlyCleanup(reconciler, clusterSummary, true)).To(BeFalse())
+	})
+
 	It("isReady returns true if CAPI Cluster has Status.ControlPlaneReady set to true", func() {
 		cluster.Status.ControlPlaneReady = true
 
@@ -163,6 +180,27 @@ var _ = Describe("ClustersummaryController", func() {
 		Expect(controllers.IsPaused(reconciler, context.TODO(), clusterSummary)).To(BeTrue())
 	})
 
+	It("isPaused returns true if CAPI Cluster has paused annotation", func() {
+		initObjects := []client.Object{
+			clusterProfile,
+			clusterSummary,
+			cluster,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+
+		Expect(controllers.IsPaused(reconciler, context.TODO(), clusterSummary)).To(BeFalse())
+
+		cluster.Annotations = map[string]string{
+			clusterv1.PausedAnnotation: "true",
+		}
+		Expect(c.Update(context.TODO(), cluster)).To(Succeed())
+
+		Expect(controllers.IsPaused(reconciler, context.TODO(), clusterSummary)).To(BeTrue())
+	})
+
 	It("isPaused returns false when Cluster does not exist", func() {
 		clusterSummary.Annotations = map[string]string{
 			"cluster.x-k8s.io/paused": "ok",
@@ -482,6 +520,365 @@ var _ = Describe("ClustersummaryController", func() {
 		Expect(featureKustomizeVerified).To(BeTrue())
 	})
 
+	It("updateFeaturesConvergence sets Degraded condition when a feature is not provisioned for too long", func() {
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{FeatureID: configv1beta1.FeatureHelm, Status: configv1beta1.FeatureStatusProvisioned},
+			{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusFailed},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		// StuckFeatureThreshold not set
+		Expect(controllers.UpdateFeaturesConvergence(reconciler, clusterSummaryScope, logger)).To(BeZero())
+		Expect(meta.FindStatusCondition(clusterSummary.Status.Conditions, configv1beta1.DegradedCondition)).To(BeNil())
+
+		// Resources is failing but threshold is not reached yet
+		reconciler.StuckFeatureThreshold = time.Hour
+		requeueAfter := controllers.UpdateFeaturesConvergence(reconciler, clusterSummaryScope, logger)
+		Expect(requeueAfter).To(BeNumerically(">", 0))
+		Expect(requeueAfter).To(BeNumerically("<=", time.Hour))
+		Expect(meta.IsStatusConditionFalse(clusterSummary.Status.Conditions, configv1beta1.DegradedCondition)).To(BeTrue())
+
+		// Resources has been failing for longer than threshold
+		reconciler.StuckFeatureThreshold = time.Nanosecond
+		Expect(controllers.UpdateFeaturesConvergence(reconciler, clusterSummaryScope, logger)).To(BeZero())
+		Expect(meta.IsStatusConditionTrue(clusterSummary.Status.Conditions, configv1beta1.DegradedCondition)).To(BeTrue())
+
+		// Resources is now provisioned
+		clusterSummary.Status.FeatureSummaries[1].Status = configv1beta1.FeatureStatusProvisioned
+		Expect(controllers.UpdateFeaturesConvergence(reconciler, clusterSummaryScope, logger)).To(BeZero())
+		Expect(meta.IsStatusConditionFalse(clusterSummary.Status.Conditions, configv1beta1.DegradedCondition)).To(BeTrue())
+		Expect(meta.FindStatusCondition(clusterSummary.Status.Conditions, "ResourcesConverged")).To(BeNil())
+	})
+
+	It("updateFeaturesConvergence relies on persisted status to know since when a feature is not provisioned", func() {
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusFailed},
+		}
+		// Set by a previous controller instance
+		clusterSummary.Status.Conditions = []metav1.Condition{
+			{
+				Type:               "ResourcesConverged",
+				Status:             metav1.ConditionFalse,
+				Reason:             string(configv1beta1.FeatureStatusFailed),
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+		reconciler.StuckFeatureThreshold = time.Hour
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		Expect(controllers.UpdateFeaturesConvergence(reconciler, clusterSummaryScope, logger)).To(BeZero())
+		Expect(meta.IsStatusConditionTrue(clusterSummary.Status.Conditions, configv1beta1.DegradedCondition)).To(BeTrue())
+	})
+
+	It("restoreDeployedFeatures restores provisioned features after clusterctl move", func() {
+		helmHash := []byte(randomString())
+		resourcesHash := []byte(randomString())
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{FeatureID: configv1beta1.FeatureHelm, Status: configv1beta1.FeatureStatusProvisioned, Hash: helmHash},
+			{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusProvisioned, Hash: resourcesHash},
+			{FeatureID: configv1beta1.FeatureKustomize, Status: configv1beta1.FeatureStatusFailed, Hash: []byte(randomString())},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		controllers.StoreDeployedFeatures(clusterSummaryScope, logger)
+
+		// clusterctl move does not preserve Status
+		clusterSummary.Status = configv1beta1.ClusterSummaryStatus{}
+		controllers.RestoreDeployedFeatures(clusterSummaryScope, logger)
+
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(2))
+		for i := range clusterSummary.Status.FeatureSummaries {
+			fs := &clusterSummary.Status.FeatureSummaries[i]
+			Expect(fs.Status).To(Equal(configv1beta1.FeatureStatusProvisioned))
+			switch fs.FeatureID {
+			case configv1beta1.FeatureHelm:
+				Expect(fs.Hash).To(Equal(helmHash))
+			case configv1beta1.FeatureResources:
+				Expect(fs.Hash).To(Equal(resourcesHash))
+			default:
+				Fail(fmt.Sprintf("unexpected feature %s", fs.FeatureID))
+			}
+		}
+	})
+
+	It("protectReferencedResources adds finalizer to referenced ConfigMaps and releases unreferenced ones", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+		}
+
+		clusterSummary.Spec.ClusterProfileSpec.PolicyRefs = []configv1beta1.PolicyRef{
+			{
+				Namespace: configMap.Namespace, Name: configMap.Name, Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			configMap,
+		}
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Scheme:                     scheme,
+			ClusterMap:                 make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap:               make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:                  sync.Mutex{},
+			ProtectReferencedResources: true,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).
+			WithIndex(&configv1beta1.ClusterSummary{}, controllers.ReferencedResourceIndex,
+				func(o client.Object) []string { return controllers.IndexReferencedResources(reconciler, o) }).
+			Build()
+		reconciler.Client = c
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		Expect(controllers.ProtectReferencedResources(reconciler, context.TODO(), clusterSummaryScope, logger)).To(Succeed())
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeTrue())
+		Expect(currentConfigMap.Labels).To(HaveKey(configv1beta1.ReferencedResourceLabel))
+
+		Expect(controllers.ReleaseReferencedResources(reconciler, context.TODO(), clusterSummary, logger)).To(Succeed())
+
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeFalse())
+		Expect(currentConfigMap.Labels).ToNot(HaveKey(configv1beta1.ReferencedResourceLabel))
+	})
+
+	It("protectReferencedResources releases resources not referenced anymore relying only on API state", func() {
+		referenced := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  randomString(),
+				Name:       randomString(),
+				Finalizers: []string{configv1beta1.ReferencedResourceFinalizer},
+			},
+		}
+		// Protected before a restart, by a reference which was removed meanwhile
+		stale := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  randomString(),
+				Name:       randomString(),
+				Finalizers: []string{configv1beta1.ReferencedResourceFinalizer},
+				Labels:     map[string]string{configv1beta1.ReferencedResourceLabel: "true"},
+			},
+		}
+
+		clusterSummary.Spec.ClusterProfileSpec.PolicyRefs = []configv1beta1.PolicyRef{
+			{
+				Namespace: referenced.Namespace, Name: referenced.Name, Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			referenced,
+			stale,
+		}
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Scheme:                     scheme,
+			ClusterMap:                 make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap:               make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:                  sync.Mutex{},
+			ProtectReferencedResources: true,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).
+			WithIndex(&configv1beta1.ClusterSummary{}, controllers.ReferencedResourceIndex,
+				func(o client.Object) []string { return controllers.IndexReferencedResources(reconciler, o) }).
+			Build()
+		reconciler.Client = c
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		Expect(controllers.ProtectReferencedResources(reconciler, context.TODO(), clusterSummaryScope, logger)).To(Succeed())
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(referenced), currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeTrue())
+
+		currentSecret := &corev1.Secret{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(stale), currentSecret)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentSecret, configv1beta1.ReferencedResourceFinalizer)).To(BeFalse())
+
+		// With protection disabled, reconciliations do not touch ConfigMaps/Secrets anymore
+		reconciler.ProtectReferencedResources = false
+		Expect(controllers.ProtectReferencedResources(reconciler, context.TODO(), clusterSummaryScope, logger)).To(Succeed())
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(referenced), currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeTrue())
+
+		// The startup cleanup releases resources even if still referenced
+		Expect(controllers.ReleaseAllReferencedResources(reconciler, context.TODO(), logger)).To(Succeed())
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(referenced), currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeFalse())
+		Expect(currentConfigMap.Labels).ToNot(HaveKey(configv1beta1.ReferencedResourceLabel))
+	})
+
+	It("releaseAllReferencedResources labels resources protected before the label was introduced", func() {
+		legacy := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  randomString(),
+				Name:       randomString(),
+				Finalizers: []string{configv1beta1.ReferencedResourceFinalizer},
+			},
+		}
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Scheme:                     scheme,
+			ClusterMap:                 make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap:               make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:                  sync.Mutex{},
+			ProtectReferencedResources: true,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacy).
+			WithIndex(&configv1beta1.ClusterSummary{}, controllers.ReferencedResourceIndex,
+				func(o client.Object) []string { return controllers.IndexReferencedResources(reconciler, o) }).
+			Build()
+		reconciler.Client = c
+
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		Expect(controllers.ReleaseAllReferencedResources(reconciler, context.TODO(), logger)).To(Succeed())
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(legacy), currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeTrue())
+		Expect(currentConfigMap.Labels).To(HaveKey(configv1beta1.ReferencedResourceLabel))
+
+		// Not referenced by any ClusterSummary, so it is released by the following label based cleanup
+		Expect(controllers.ReleaseReferencedResources(reconciler, context.TODO(), nil, logger)).To(Succeed())
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(legacy), currentConfigMap)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(currentConfigMap, configv1beta1.ReferencedResourceFinalizer)).To(BeFalse())
+	})
+
+	It("validateReferences reports missing and unparsable referenced resources", func() {
+		validConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: randomString(), Name: randomString()},
+			Data: map[string]string{
+				"namespace": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: " + randomString(),
+			},
+		}
+		invalidConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: randomString(), Name: randomString()},
+			Data: map[string]string{
+				"invalid": "kind: [",
+			},
+		}
+		missingName := randomString()
+		missingNamespace := randomString()
+
+		clusterSummary.Spec.ClusterProfileSpec.PolicyRefs = []configv1beta1.PolicyRef{
+			{Namespace: validConfigMap.Namespace, Name: validConfigMap.Name, Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind)},
+			{Namespace: invalidConfigMap.Namespace, Name: invalidConfigMap.Name, Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind)},
+			{Namespace: missingNamespace, Name: missingName, Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind)},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			validConfigMap,
+			invalidConfigMap,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		clusterSummaryScope, err := scope.NewClusterSummaryScope(&scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			ClusterSummary: clusterSummary,
+			ControllerName: "clustersummary",
+		})
+		Expect(err).To(BeNil())
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Client:       c,
+			Scheme:       scheme,
+			ClusterMap:   make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap: make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:    sync.Mutex{},
+		}
+
+		Expect(controllers.ValidateReferences(reconciler, context.TODO(), clusterSummaryScope,
+			textlogger.NewLogger(textlogger.NewConfig()))).To(Succeed())
+
+		Expect(meta.IsStatusConditionFalse(clusterSummary.Status.Conditions, configv1beta1.ReferencesValidCondition)).To(BeTrue())
+		Expect(len(clusterSummary.Status.InvalidReferences)).To(Equal(2))
+		for i := range clusterSummary.Status.InvalidReferences {
+			ref := &clusterSummary.Status.InvalidReferences[i]
+			switch ref.Name {
+			case missingName:
+				Expect(ref.Reason).To(Equal(configv1beta1.ReferenceNotFound))
+			case invalidConfigMap.Name:
+				Expect(ref.Reason).To(Equal(configv1beta1.ReferenceUnparsable))
+			default:
+				Fail(fmt.Sprintf("unexpected invalid reference %s", ref.Name))
+			}
+		}
+		// Both are ConfigMaps, so invalid references are sorted by namespace
+		Expect(clusterSummary.Status.InvalidReferences[0].Namespace <
+			clusterSummary.Status.InvalidReferences[1].Namespace).To(BeTrue())
+
+		clusterSummary.Spec.ClusterProfileSpec.PolicyRefs = clusterSummary.Spec.ClusterProfileSpec.PolicyRefs[:1]
+		Expect(controllers.ValidateReferences(reconciler, context.TODO(), clusterSummaryScope,
+			textlogger.NewLogger(textlogger.NewConfig()))).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(clusterSummary.Status.Conditions, configv1beta1.ReferencesValidCondition)).To(BeTrue())
+		Expect(clusterSummary.Status.InvalidReferences).To(BeEmpty())
+	})
+
 	It("shouldReconcile returns true when mode is OneTime but not all helm charts are deployed", func() {
 		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeOneTime
 		clusterSummary.Spec.ClusterProfileSpec.HelmCharts = []configv1beta1.HelmChart{
@@ -602,6 +999,43 @@ var _ = Describe("ClustersummaryController", func() {
 		).Should(BeTrue())
 	})
 
+	It("Reconcile does not requeue when cluster is not found", func() {
+		initObjects := []client.Object{
+			clusterProfile,
+			clusterSummary,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		deployer := fakedeployer.GetClient(context.TODO(), textlogger.NewLogger(textlogger.NewConfig()), c)
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Client:       c,
+			Scheme:       scheme,
+			Deployer:     deployer,
+			ClusterMap:   make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap: make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:    sync.Mutex{},
+			Logger:       textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		clusterSummaryName := client.ObjectKey{
+			Name:      clusterSummary.Name,
+			Namespace: clusterSummary.Namespace,
+		}
+		result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{
+			NamespacedName: clusterSummaryName,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		// Cluster creation must trigger a reconciliation
+		Expect(addTypeInformationToObject(scheme, cluster)).To(Succeed())
+		requests := controllers.RequeueClusterSummaryForCluster(reconciler, context.TODO(), cluster)
+		Expect(requests).To(ContainElement(reconcile.Request{NamespacedName: clusterSummaryName}))
+	})
+
 	It("shouldRedeploy returns true in DryRun mode", func() {
 		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeDryRun
 		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
@@ -939,6 +1373,9 @@ var _ = Describe("ClustersummaryController", func() {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      clusterSummaryAName,
 				Namespace: namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: configv1beta1.GroupVersion.String(), Kind: configv1beta1.ClusterProfileKind, Name: clusterProfileAName},
+				},
 				Labels: map[string]string{
 					controllers.ClusterProfileLabelName: clusterProfileAName,
 					configv1beta1.ClusterNameLabel:      clusterName,
@@ -968,6 +1405,9 @@ var _ = Describe("ClustersummaryController", func() {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      clusterSummaryBName,
 				Namespace: namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: configv1beta1.GroupVersion.String(), Kind: configv1beta1.ClusterProfileKind, Name: clusterProfileBName},
+				},
 				Labels: map[string]string{
 					controllers.ClusterProfileLabelName: clusterProfileBName,
 					configv1beta1.ClusterNameLabel:      clusterName,
@@ -1005,7 +1445,10 @@ var _ = Describe("ClustersummaryController", func() {
 			clusterProfile,
 		}
 
-		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).
+			WithIndex(&configv1beta1.ClusterSummary{}, controllers.ProfileClusterIndex,
+				controllers.IndexClusterSummaryByProfileAndCluster).
+			Build()
 
 		addOwnerReference(context.TODO(), c, clusterSummary, clusterProfile)
 
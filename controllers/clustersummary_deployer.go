@@ -24,8 +24,8 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
@@ -38,6 +38,9 @@ import (
 
 const (
 	driftDetectionInMgtmCluster = "driftDetectionInMgtmCluster"
+
+	featureFailedEventReason      = "FeatureFailed"
+	featureProvisionedEventReason = "FeatureProvisioned"
 )
 
 func startDriftDetectionInMgmtCluster(o deployer.Options) bool {
@@ -71,12 +74,14 @@ func (r *ClusterSummaryReconciler) deployFeature(ctx context.Context, clusterSum
 
 	clusterSummary := clusterSummaryScope.ClusterSummary
 
-	logger = logger.WithValues("clusternamespace", clusterSummary.Spec.ClusterNamespace,
-		"clustername", clusterSummary.Spec.ClusterNamespace,
-		"applicant", clusterSummary.Name,
-		"feature", string(f.id))
+	logger = getFeatureLogger(logger, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
+		clusterSummary.Name, string(f.id))
 	logger.V(logs.LogDebug).Info("request to deploy")
 
+	ctx, span := startFeatureSpan(ctx, "deployFeature", clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Name, string(f.id), clusterSummary.Spec.ClusterType)
+	defer span.End()
+
 	r.Deployer.CleanupEntries(clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName, clusterSummary.Name,
 		string(f.id), clusterSummary.Spec.ClusterType, true)
 
@@ -95,6 +100,11 @@ func (r *ClusterSummaryReconciler) deployFeature(ctx context.Context, clusterSum
 	if err != nil {
 		return err
 	}
+	setHashAttribute(span, currentHash)
+
+	// Features are deployed concurrently. From here on ClusterSummary Status is read and updated.
+	clusterSummaryScope.LockStatus()
+	defer clusterSummaryScope.UnlockStatus()
 
 	hash := r.getHash(clusterSummaryScope, f.id)
 
@@ -102,6 +112,11 @@ func (r *ClusterSummaryReconciler) deployFeature(ctx context.Context, clusterSum
 	if !isConfigSame {
 		logger.V(logs.LogDebug).Info(fmt.Sprintf("configuration has changed. Current hash %x. Previous hash %x",
 			currentHash, hash))
+	} else if r.isFullResyncDue(clusterSummaryScope, f.id) {
+		// Treat configuration as changed so feature is re-applied. This self-heals any drift
+		// which was not detected.
+		logger.V(logs.LogDebug).Info("full resync period elapsed. Forcing redeployment")
+		isConfigSame = false
 	}
 
 	if !r.shouldRedeploy(clusterSummaryScope, f, isConfigSame, logger) {
@@ -122,6 +137,18 @@ func (r *ClusterSummaryReconciler) deployFeature(ctx context.Context, clusterSum
 	}
 
 	if status != nil {
+		if *status == configv1beta1.FeatureStatusFailed && isDeploymentTimeoutError(resultError) {
+			fs := getFeatureSummaryForFeatureID(clusterSummary, f.id)
+			if !isFailedForDeploymentTimeout(fs) {
+				// Record the timeout. Feature will be deployed again only once backoff is over.
+				r.updateFeatureStatus(clusterSummaryScope, f.id, status, currentHash, resultError, logger)
+				return resultError
+			}
+			if !isDeploymentTimeoutBackoffOver(fs) {
+				logger.V(logs.LogDebug).Info("deployment timed out. Waiting before deploying again")
+				return resultError
+			}
+		}
 		logger.V(logs.LogDebug).Info(fmt.Sprintf("result is available. updating status: %v", *status))
 		r.updateFeatureStatus(clusterSummaryScope, f.id, status, currentHash, resultError, logger)
 		if *status == configv1beta1.FeatureStatusProvisioned {
@@ -152,6 +179,8 @@ func (r *ClusterSummaryReconciler) deployFeature(ctx context.Context, clusterSum
 	if r.AgentInMgmtCluster {
 		options.HandlerOptions[driftDetectionInMgtmCluster] = "management"
 	}
+	setDeploymentTimeoutOption(clusterSummary, &options)
+	injectTraceContext(ctx, options.HandlerOptions)
 
 	logger.V(logs.LogDebug).Info("queueing request to deploy")
 	if err := r.Deployer.Deploy(ctx, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
@@ -175,9 +204,25 @@ func genericDeploy(ctx context.Context, c client.Client,
 
 	// Before any per feature specific code
 
+	ctx, span := startFeatureSpan(extractTraceContext(ctx, o.HandlerOptions), "deploy",
+		clusterNamespace, clusterName, applicant, featureID, clusterType)
+	logger = getFeatureLogger(logger, clusterNamespace, clusterName, applicant, featureID)
+	done, err := trackHandler(clusterNamespace, clusterName, applicant, featureID, clusterType, false)
+	if err != nil {
+		endSpan(span, err)
+		return err
+	}
+	defer done()
+
+	// Deployment is bounded by DeploymentTimeout, if set
+	ctx, cancel, classifyError := withDeploymentTimeout(ctx, o)
+	defer cancel()
+
 	// Invoking per feature specific code
 	featureHandler := getHandlersForFeature(configv1beta1.FeatureID(featureID))
-	err := featureHandler.deploy(ctx, c, clusterNamespace, clusterName, applicant, featureID, clusterType, o, logger)
+	err = classifyError(featureHandler.deploy(ctx, c, clusterNamespace, clusterName, applicant, featureID,
+		clusterType, o, logger))
+	endSpan(span, err)
 	if err != nil {
 		return err
 	}
@@ -188,16 +233,18 @@ func genericDeploy(ctx context.Context, c client.Client,
 }
 
 func (r *ClusterSummaryReconciler) undeployFeature(ctx context.Context, clusterSummaryScope *scope.ClusterSummaryScope,
-	f feature, logger logr.Logger) error {
+	f feature, managementClusterOnly bool, logger logr.Logger) error {
 
 	clusterSummary := clusterSummaryScope.ClusterSummary
 
-	logger = logger.WithValues("clusternamespace", clusterSummary.Spec.ClusterNamespace,
-		"clustername", clusterSummary.Spec.ClusterNamespace,
-		"applicant", clusterSummary.Name,
-		"feature", string(f.id))
+	logger = getFeatureLogger(logger, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
+		clusterSummary.Name, string(f.id))
 	logger.V(logs.LogDebug).Info("request to un-deploy")
 
+	ctx, span := startFeatureSpan(ctx, "undeployFeature", clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Name, string(f.id), clusterSummary.Spec.ClusterType)
+	defer span.End()
+
 	r.Deployer.CleanupEntries(clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName, clusterSummary.Name,
 		string(f.id), clusterSummary.Spec.ClusterType, false)
 
@@ -249,9 +296,15 @@ func (r *ClusterSummaryReconciler) undeployFeature(ctx context.Context, clusterS
 		r.updateFeatureStatus(clusterSummaryScope, f.id, status, nil, nil, logger)
 	}
 
+	options := deployer.Options{HandlerOptions: map[string]string{}}
+	injectTraceContext(ctx, options.HandlerOptions)
+	if managementClusterOnly {
+		options.HandlerOptions[managementClusterOnlyOption] = "true"
+	}
+
 	logger.V(logs.LogDebug).Info("queueing request to un-deploy")
 	if err := r.Deployer.Deploy(ctx, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
-		clusterSummary.Name, string(f.id), clusterSummary.Spec.ClusterType, true, genericUndeploy, programDuration, deployer.Options{}); err != nil {
+		clusterSummary.Name, string(f.id), clusterSummary.Spec.ClusterType, true, genericUndeploy, programDuration, options); err != nil {
 		r.updateFeatureStatus(clusterSummaryScope, f.id, status, nil, err, logger)
 		return err
 	}
@@ -269,7 +322,13 @@ func genericUndeploy(ctx context.Context, c client.Client,
 
 	// Before any per feature specific code
 
-	var err error
+	logger = getFeatureLogger(logger, clusterNamespace, clusterName, applicant, featureID)
+	done, err := trackHandler(clusterNamespace, clusterName, applicant, featureID, clusterType, true)
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	_, err = clusterproxy.GetCluster(ctx, c, clusterNamespace, clusterName, clusterType)
 
 	if err != nil {
@@ -280,9 +339,14 @@ func genericUndeploy(ctx context.Context, c client.Client,
 		return err
 	}
 
+	ctx, span := startFeatureSpan(extractTraceContext(ctx, o.HandlerOptions), "undeploy",
+		clusterNamespace, clusterName, applicant, featureID, clusterType)
+
 	// Invoking per feature specific code
 	featureHandler := getHandlersForFeature(configv1beta1.FeatureID(featureID))
-	if err := featureHandler.undeploy(ctx, c, clusterNamespace, clusterName, applicant, featureID, clusterType, o, logger); err != nil {
+	err = featureHandler.undeploy(ctx, c, clusterNamespace, clusterName, applicant, featureID, clusterType, o, logger)
+	endSpan(span, err)
+	if err != nil {
 		return err
 	}
 
@@ -303,6 +367,17 @@ func (r *ClusterSummaryReconciler) isFeatureStatusPresent(clusterSummary *config
 	return false
 }
 
+// hasFeatureStatus is same as isFeatureStatusPresent but it is safe to call
+// while features are being processed concurrently.
+func (r *ClusterSummaryReconciler) hasFeatureStatus(clusterSummaryScope *scope.ClusterSummaryScope,
+	featureID configv1beta1.FeatureID) bool {
+
+	clusterSummaryScope.LockStatus()
+	defer clusterSummaryScope.UnlockStatus()
+
+	return r.isFeatureStatusPresent(clusterSummaryScope.ClusterSummary, featureID)
+}
+
 // isFeatureDeployed returns true if feature is marked as deployed (present in FeatureSummaries and status
 // is set to Provisioned).
 func (r *ClusterSummaryReconciler) isFeatureDeployed(clusterSummary *configv1beta1.ClusterSummary,
@@ -364,26 +439,66 @@ func (r *ClusterSummaryReconciler) updateFeatureStatus(clusterSummaryScope *scop
 	}
 	logger = logger.WithValues("hash", fmt.Sprintf("%x", hash), "status", *status)
 	logger.V(logs.LogDebug).Info("updating clustersummary status")
-	now := metav1.NewTime(time.Now())
+
+	r.recordFeatureStatusEvent(clusterSummaryScope, featureID, *status, statusError)
 
 	switch *status {
 	case configv1beta1.FeatureStatusProvisioned:
-		clusterSummaryScope.SetFeatureStatus(featureID, configv1beta1.FeatureStatusProvisioned, hash)
-		clusterSummaryScope.SetFailureMessage(featureID, nil)
+		clusterSummaryScope.MarkFeatureProvisioned(featureID, hash)
 	case configv1beta1.FeatureStatusRemoved:
-		clusterSummaryScope.SetFeatureStatus(featureID, configv1beta1.FeatureStatusRemoved, hash)
-		clusterSummaryScope.SetFailureMessage(featureID, nil)
-	case configv1beta1.FeatureStatusProvisioning:
-		clusterSummaryScope.SetFeatureStatus(featureID, configv1beta1.FeatureStatusProvisioning, hash)
-	case configv1beta1.FeatureStatusRemoving:
-		clusterSummaryScope.SetFeatureStatus(featureID, configv1beta1.FeatureStatusRemoving, hash)
+		clusterSummaryScope.MarkFeatureRemoved(featureID, hash)
+	case configv1beta1.FeatureStatusProvisioning, configv1beta1.FeatureStatusRemoving:
+		clusterSummaryScope.MarkFeatureInProgress(featureID, *status, hash)
 	case configv1beta1.FeatureStatusFailed, configv1beta1.FeatureStatusFailedNonRetriable:
-		clusterSummaryScope.SetFeatureStatus(featureID, *status, hash)
-		err := statusError.Error()
-		clusterSummaryScope.SetFailureMessage(featureID, &err)
+		clusterSummaryScope.MarkFeatureFailed(featureID, *status, hash, statusError.Error(),
+			getFailureReason(statusError), getFailedResources(statusError))
+	}
+}
+
+// getFailureReason returns the FeatureSummary FailureReason for err, if any
+func getFailureReason(err error) *string {
+	if isDeploymentTimeoutError(err) {
+		reason := deploymentTimeoutFailureReason
+		return &reason
+	}
+
+	var nonRetriableError *NonRetriableError
+	if errors.As(err, &nonRetriableError) && nonRetriableError.Reason != "" {
+		reason := nonRetriableError.Reason
+		return &reason
+	}
+
+	return nil
+}
+
+// recordFeatureStatusEvent emits an Event on the ClusterSummary when a feature transitions
+// to Failed (Warning) or Provisioned (Normal). Nothing is emitted if the status is unchanged.
+func (r *ClusterSummaryReconciler) recordFeatureStatusEvent(clusterSummaryScope *scope.ClusterSummaryScope,
+	featureID configv1beta1.FeatureID, status configv1beta1.FeatureStatus, statusError error) {
+
+	if r.EventRecorder == nil {
+		return
+	}
+
+	clusterSummary := clusterSummaryScope.ClusterSummary
+	if fs := getFeatureSummaryForFeatureID(clusterSummary, featureID); fs != nil && fs.Status == status {
+		return
 	}
 
-	clusterSummaryScope.SetLastAppliedTime(featureID, &now)
+	switch status {
+	case configv1beta1.FeatureStatusFailed, configv1beta1.FeatureStatusFailedNonRetriable:
+		message := fmt.Sprintf("feature %s failed in cluster %s/%s", featureID,
+			clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName)
+		if statusError != nil {
+			message += fmt.Sprintf(": %v", statusError)
+		}
+		r.EventRecorder.Event(clusterSummary, corev1.EventTypeWarning, featureFailedEventReason, message)
+	case configv1beta1.FeatureStatusProvisioned:
+		r.EventRecorder.Eventf(clusterSummary, corev1.EventTypeNormal, featureProvisionedEventReason,
+			"feature %s provisioned in cluster %s/%s", featureID,
+			clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName)
+	default:
+	}
 }
 
 func (r *ClusterSummaryReconciler) convertResultStatus(result deployer.Result) *configv1beta1.FeatureStatus {
@@ -407,6 +522,74 @@ func (r *ClusterSummaryReconciler) convertResultStatus(result deployer.Result) *
 	return nil
 }
 
+// getFullResyncPeriod returns the ResyncPeriod set on the ClusterProfile/Profile if any.
+// Otherwise the controller FullResyncPeriod.
+func (r *ClusterSummaryReconciler) getFullResyncPeriod(clusterSummaryScope *scope.ClusterSummaryScope) time.Duration {
+	resyncPeriod := clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.ResyncPeriod
+	if resyncPeriod != nil {
+		return resyncPeriod.Duration
+	}
+
+	return r.FullResyncPeriod
+}
+
+// isFullResyncDue returns true if feature is deployed and it was last applied more than
+// full resync period ago. Features in OneTime or DryRun mode are never resynced.
+func (r *ClusterSummaryReconciler) isFullResyncDue(clusterSummaryScope *scope.ClusterSummaryScope,
+	featureID configv1beta1.FeatureID) bool {
+
+	fullResyncPeriod := r.getFullResyncPeriod(clusterSummaryScope)
+	if fullResyncPeriod == 0 {
+		return false
+	}
+
+	if clusterSummaryScope.IsOneTimeSync() || clusterSummaryScope.IsDryRunSync() {
+		return false
+	}
+
+	fs := getFeatureSummaryForFeatureID(clusterSummaryScope.ClusterSummary, featureID)
+	if fs == nil || fs.Status != configv1beta1.FeatureStatusProvisioned || fs.LastAppliedTime == nil {
+		return false
+	}
+
+	return time.Since(fs.LastAppliedTime.Time) > fullResyncPeriod
+}
+
+// getFullResyncRequeueAfter returns the time left before the earliest full resync of a
+// provisioned feature is due. Zero is returned if full resync does not apply.
+func (r *ClusterSummaryReconciler) getFullResyncRequeueAfter(clusterSummaryScope *scope.ClusterSummaryScope,
+) time.Duration {
+
+	fullResyncPeriod := r.getFullResyncPeriod(clusterSummaryScope)
+	if fullResyncPeriod == 0 {
+		return 0
+	}
+
+	if clusterSummaryScope.IsOneTimeSync() || clusterSummaryScope.IsDryRunSync() {
+		return 0
+	}
+
+	var requeueAfter time.Duration
+	featureSummaries := clusterSummaryScope.ClusterSummary.Status.FeatureSummaries
+	for i := range featureSummaries {
+		fs := &featureSummaries[i]
+		if fs.Status != configv1beta1.FeatureStatusProvisioned || fs.LastAppliedTime == nil {
+			continue
+		}
+
+		// isFullResyncDue requires more than fullResyncPeriod to have elapsed
+		left := fullResyncPeriod - time.Since(fs.LastAppliedTime.Time) + time.Second
+		if left < time.Second {
+			left = time.Second
+		}
+		if requeueAfter == 0 || left < requeueAfter {
+			requeueAfter = left
+		}
+	}
+
+	return requeueAfter
+}
+
 // shouldRedeploy returns true if this feature requires to be redeployed.
 func (r *ClusterSummaryReconciler) shouldRedeploy(clusterSummaryScope *scope.ClusterSummaryScope, f feature,
 	isConfigSame bool, logger logr.Logger) bool {
@@ -18,8 +18,10 @@ package controllers_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/gdexlab/go-render/render"
 	"github.com/go-logr/logr"
@@ -29,6 +31,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -234,6 +237,119 @@ var _ = Describe("ClustersummaryDeployer", func() {
 		Expect(reflect.DeepEqual(currentHash, hash)).To(BeTrue())
 	})
 
+	It("isFullResyncDue returns true only when feature was applied more than FullResyncPeriod ago", func() {
+		lastAppliedTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeContinuous
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{
+				FeatureID:       configv1beta1.FeatureResources,
+				Status:          configv1beta1.FeatureStatusProvisioned,
+				LastAppliedTime: &lastAppliedTime,
+			},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		// FullResyncPeriod not set
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeFalse())
+
+		reconciler.FullResyncPeriod = 2 * time.Hour
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeFalse())
+
+		reconciler.FullResyncPeriod = 30 * time.Minute
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeTrue())
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm)).To(BeFalse())
+
+		clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeOneTime
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeFalse())
+	})
+
+	It("isFullResyncDue uses ResyncPeriod when set on the profile", func() {
+		lastAppliedTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeContinuous
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{
+				FeatureID:       configv1beta1.FeatureResources,
+				Status:          configv1beta1.FeatureStatusProvisioned,
+				LastAppliedTime: &lastAppliedTime,
+			},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+		reconciler.FullResyncPeriod = 2 * time.Hour
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeFalse())
+
+		// Profile ResyncPeriod overrides controller setting
+		clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.ResyncPeriod = &metav1.Duration{Duration: 30 * time.Minute}
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeTrue())
+
+		// A zero ResyncPeriod disables full resync for this profile
+		reconciler.FullResyncPeriod = 30 * time.Minute
+		clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.ResyncPeriod = &metav1.Duration{}
+		Expect(controllers.IsFullResyncDue(reconciler, clusterSummaryScope, configv1beta1.FeatureResources)).To(BeFalse())
+	})
+
+	It("getFullResyncRequeueAfter returns time left before the earliest full resync", func() {
+		resourcesAppliedTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		helmAppliedTime := metav1.NewTime(time.Now().Add(-90 * time.Minute))
+		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeContinuous
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{
+				FeatureID:       configv1beta1.FeatureResources,
+				Status:          configv1beta1.FeatureStatusProvisioned,
+				LastAppliedTime: &resourcesAppliedTime,
+			},
+			{
+				FeatureID:       configv1beta1.FeatureHelm,
+				Status:          configv1beta1.FeatureStatusProvisioned,
+				LastAppliedTime: &helmAppliedTime,
+			},
+		}
+
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		// FullResyncPeriod not set
+		Expect(controllers.GetFullResyncRequeueAfter(reconciler, clusterSummaryScope)).To(BeZero())
+
+		// Helm was applied first, so its resync is the earliest one
+		reconciler.FullResyncPeriod = 2 * time.Hour
+		requeueAfter := controllers.GetFullResyncRequeueAfter(reconciler, clusterSummaryScope)
+		Expect(requeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+
+		// Resync already due
+		clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.ResyncPeriod = &metav1.Duration{Duration: 30 * time.Minute}
+		Expect(controllers.GetFullResyncRequeueAfter(reconciler, clusterSummaryScope)).To(Equal(time.Second))
+
+		clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeOneTime
+		Expect(controllers.GetFullResyncRequeueAfter(reconciler, clusterSummaryScope)).To(BeZero())
+	})
+
 	It("updateFeatureStatus updates ClusterSummary Status FeatureSummary", func() {
 		initObjects := []client.Object{
 			clusterSummary,
@@ -266,6 +382,151 @@ var _ = Describe("ClustersummaryDeployer", func() {
 		Expect(clusterSummary.Status.FeatureSummaries[0].FailureMessage).To(BeNil())
 	})
 
+	It("updateFeatureStatus sets FailureReason to Timeout when deployment timed out", func() {
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		hash := []byte(randomString())
+		status := configv1beta1.FeatureStatusFailed
+		statusErr := fmt.Errorf("deploying helm charts failed: %w",
+			&controllers.DeploymentTimeoutError{Timeout: time.Minute})
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm, &status,
+			hash, statusErr, textlogger.NewLogger(textlogger.NewConfig()))
+
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailureReason).ToNot(BeNil())
+		Expect(*clusterSummary.Status.FeatureSummaries[0].FailureReason).To(Equal("Timeout"))
+
+		// Any other error does not set a FailureReason
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm, &status,
+			hash, fmt.Errorf("failed to deploy"), textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailureReason).To(BeNil())
+	})
+
+	It("updateFeatureStatus sets FailureReason to ReferenceMissing when a referenced resource does not exist", func() {
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		hash := []byte(randomString())
+		status := configv1beta1.FeatureStatusFailedNonRetriable
+		statusErr := &controllers.NonRetriableError{
+			Message: "Referenced resource: ConfigMap default/policies does not exist",
+			Reason:  "ReferenceMissing",
+		}
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureResources, &status,
+			hash, statusErr, textlogger.NewLogger(textlogger.NewConfig()))
+
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		Expect(clusterSummary.Status.FeatureSummaries[0].Status).To(Equal(configv1beta1.FeatureStatusFailedNonRetriable))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailureReason).ToNot(BeNil())
+		Expect(*clusterSummary.Status.FeatureSummaries[0].FailureReason).To(Equal("ReferenceMissing"))
+	})
+
+	It("updateFeatureStatus reports resources which failed to be deployed", func() {
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := getClusterSummaryReconciler(c, nil)
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		failure := configv1beta1.ResourceFailure{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Namespace:  randomString(),
+			Name:       randomString(),
+			Message:    randomString(),
+		}
+
+		hash := []byte(randomString())
+		status := configv1beta1.FeatureStatusFailed
+		statusErr := fmt.Errorf("deploying resources failed: %w",
+			&controllers.ResourceDeploymentError{Message: failure.Message,
+				FailedResources: []configv1beta1.ResourceFailure{failure}})
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureResources, &status,
+			hash, statusErr, textlogger.NewLogger(textlogger.NewConfig()))
+
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailedResources).To(ConsistOf(failure))
+
+		status = configv1beta1.FeatureStatusProvisioned
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureResources, &status,
+			hash, nil, textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailedResources).To(BeNil())
+	})
+
+	It("withDeploymentTimeout converts errors caused by DeploymentTimeout", func() {
+		options := deployer.Options{HandlerOptions: map[string]string{}}
+		ctx, cancel, classifyError := controllers.WithDeploymentTimeout(context.TODO(), options)
+		defer cancel()
+		Expect(ctx).To(Equal(context.TODO()))
+		Expect(classifyError(context.DeadlineExceeded)).To(Equal(context.DeadlineExceeded))
+
+		clusterSummary.Spec.ClusterProfileSpec.DeploymentTimeout = &metav1.Duration{Duration: time.Millisecond}
+		controllers.SetDeploymentTimeoutOption(clusterSummary, &options)
+
+		ctx, cancel, classifyError = controllers.WithDeploymentTimeout(context.TODO(), options)
+		defer cancel()
+		<-ctx.Done()
+		Expect(classifyError(nil)).To(BeNil())
+		err := classifyError(ctx.Err())
+		Expect(controllers.IsDeploymentTimeoutOnly(err)).To(BeTrue())
+		Expect(controllers.IsDeploymentTimeoutOnly(errors.Join(err, fmt.Errorf("failed to deploy")))).To(BeFalse())
+	})
+
+	It("updateFeatureStatus emits events when a feature fails or gets provisioned", func() {
+		initObjects := []client.Object{
+			clusterSummary,
+			clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		recorder := record.NewFakeRecorder(10)
+		reconciler := getClusterSummaryReconciler(c, nil)
+		reconciler.EventRecorder = recorder
+
+		clusterSummaryScope := getClusterSummaryScope(c, logger, clusterProfile, clusterSummary)
+
+		hash := []byte(randomString())
+		status := configv1beta1.FeatureStatusFailed
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm, &status,
+			hash, fmt.Errorf("failed to deploy"), textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(recorder.Events).To(Receive(And(ContainSubstring(corev1.EventTypeWarning),
+			ContainSubstring("FeatureFailed"), ContainSubstring("failed to deploy"))))
+
+		// Same status: no new event
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm, &status,
+			hash, fmt.Errorf("failed to deploy"), textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(recorder.Events).ToNot(Receive())
+
+		status = configv1beta1.FeatureStatusProvisioned
+		controllers.UpdateFeatureStatus(reconciler, clusterSummaryScope, configv1beta1.FeatureHelm, &status,
+			hash, nil, textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(recorder.Events).To(Receive(And(ContainSubstring(corev1.EventTypeNormal),
+			ContainSubstring("FeatureProvisioned"))))
+	})
+
 	It("deployFeature when feature is deployed and hash has not changed, does nothing", func() {
 		clusterRole := &rbacv1.ClusterRole{
 			ObjectMeta: metav1.ObjectMeta{
@@ -482,7 +743,7 @@ var _ = Describe("ClustersummaryDeployer", func() {
 		// UndeployFeature is supposed to return before calling dep.Deploy (fake deployer Deploy once called simply
 		// adds key to InProgress).
 		// So run UndeployFeature then validate key is not added to InProgress
-		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, textlogger.NewLogger(textlogger.NewConfig()))
+		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, false, textlogger.NewLogger(textlogger.NewConfig()))
 		Expect(err).To(BeNil())
 
 		key := deployer.GetKey(clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
@@ -518,7 +779,7 @@ var _ = Describe("ClustersummaryDeployer", func() {
 		// The feature is not marked as removed in ClusterSummary Status. In such situation, UndeployFeature calls dep.Deploy.
 		// fake deployer Deploy simply adds key to InProgress.
 		// So run UndeployFeature then validate key is added to InProgress
-		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, textlogger.NewLogger(textlogger.NewConfig()))
+		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, false, textlogger.NewLogger(textlogger.NewConfig()))
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("cleanup request is queued"))
 
@@ -592,7 +853,7 @@ var _ = Describe("ClustersummaryDeployer", func() {
 
 		f := controllers.GetHandlersForFeature(configv1beta1.FeatureResources)
 
-		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, textlogger.NewLogger(textlogger.NewConfig()))
+		err := controllers.UndeployFeature(reconciler, context.TODO(), clusterSummaryScope, f, false, textlogger.NewLogger(textlogger.NewConfig()))
 		Expect(err).ToNot(BeNil())
 		Expect(err.Error()).To(Equal("deploying Resources still in progress. Wait before cleanup"))
 	})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -29,6 +30,7 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
 
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/controllers/clustercache"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
@@ -136,6 +138,10 @@ func (r *ClusterSummaryReconciler) requeueClusterSummaryForReference(
 
 	logger.V(logs.LogVerbose).Info("reacting to configMap/secret change")
 
+	if isDeploymentPoliciesConfigMap(o) {
+		return r.requeueClusterSummaryForDeploymentPolicies(ctx, logger)
+	}
+
 	r.PolicyMux.Lock()
 	defer r.PolicyMux.Unlock()
 
@@ -185,6 +191,36 @@ func (r *ClusterSummaryReconciler) requeueClusterSummaryForReference(
 	return requests
 }
 
+// requeueClusterSummaryForDeploymentPolicies requeues ClusterSummaries with a feature which failed
+// because of a deployment policy violation. Such failures are not retried otherwise.
+func (r *ClusterSummaryReconciler) requeueClusterSummaryForDeploymentPolicies(
+	ctx context.Context, logger logr.Logger,
+) []reconcile.Request {
+
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	if err := r.Client.List(ctx, clusterSummaries); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to list ClusterSummaries: %v", err))
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0)
+	for i := range clusterSummaries.Items {
+		cs := &clusterSummaries.Items[i]
+		for j := range cs.Status.FeatureSummaries {
+			fs := &cs.Status.FeatureSummaries[j]
+			if fs.FailureReason != nil && *fs.FailureReason == policyViolationFailureReason {
+				logger.V(logs.LogDebug).Info(fmt.Sprintf("requeue consumer: %s/%s", cs.Namespace, cs.Name))
+				requests = append(requests, ctrl.Request{
+					NamespacedName: client.ObjectKey{Name: cs.Name, Namespace: cs.Namespace},
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
 // requeueClusterSummaryForCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
 // for ClusterSummary to update when its own Sveltos Cluster gets updated.
 func (r *ClusterSummaryReconciler) requeueClusterSummaryForSveltosCluster(
@@ -245,3 +281,53 @@ func (r *ClusterSummaryReconciler) requeueClusterSummaryForACluster(
 
 	return requests
 }
+
+// requeueClusterSummaryForTemplateLibrary is a handler.ToRequestsFunc to be used to enqueue requests for
+// reconciliation for ClusterSummaries when a TemplateLibrary changes.
+// Published template versions are immutable, so a TemplateLibrary change can only add, remove or fix
+// templates. Only ClusterSummaries with at least one feature not provisioned are requeued.
+func (r *ClusterSummaryReconciler) requeueClusterSummaryForTemplateLibrary(
+	ctx context.Context, o client.Object,
+) []reconcile.Request {
+
+	logger := r.Logger.WithValues(
+		"objectMapper",
+		"requeueClusterSummaryForTemplateLibrary",
+		"templateLibrary",
+		o.GetName(),
+	)
+
+	logger.V(logs.LogVerbose).Info("reacting to TemplateLibrary change")
+
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	if err := r.List(ctx, clusterSummaries); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to list ClusterSummaries: %v", err))
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0)
+	for i := range clusterSummaries.Items {
+		cs := &clusterSummaries.Items[i]
+		if !hasFeatureNotProvisioned(cs) {
+			continue
+		}
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("requeue ClusterSummary %s/%s", cs.Namespace, cs.Name))
+		requests = append(requests, ctrl.Request{
+			NamespacedName: client.ObjectKey{
+				Namespace: cs.Namespace,
+				Name:      cs.Name,
+			},
+		})
+	}
+
+	return requests
+}
+
+func hasFeatureNotProvisioned(cs *configv1beta1.ClusterSummary) bool {
+	for i := range cs.Status.FeatureSummaries {
+		if cs.Status.FeatureSummaries[i].Status != configv1beta1.FeatureStatusProvisioned {
+			return true
+		}
+	}
+	return false
+}
@@ -130,4 +130,63 @@ var _ = Describe("ClusterSummaryTransformations map functions", func() {
 		Expect(requests).To(ContainElement(reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterSummary0.Name}}))
 		Expect(requests).To(ContainElement(reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterSummary1.Name}}))
 	})
+
+	It("RequeueClusterSummaryForReference returns ClusterSummaries which failed because of deployment policies", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      randomString(),
+				Namespace: "projectsveltos",
+			},
+		}
+
+		controllers.SetDeploymentPoliciesConfigMap(configMap.Name)
+		defer controllers.SetDeploymentPoliciesConfigMap("")
+
+		policyViolation := "PolicyViolation"
+		failed := &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      randomString(),
+			},
+			Status: configv1beta1.ClusterSummaryStatus{
+				FeatureSummaries: []configv1beta1.FeatureSummary{
+					{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusFailedNonRetriable,
+						FailureReason: &policyViolation},
+				},
+			},
+		}
+
+		provisioned := &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      randomString(),
+			},
+			Status: configv1beta1.ClusterSummaryStatus{
+				FeatureSummaries: []configv1beta1.FeatureSummary{
+					{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusProvisioned},
+				},
+			},
+		}
+
+		initObjects := []client.Object{
+			configMap,
+			failed,
+			provisioned,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		reconciler := &controllers.ClusterSummaryReconciler{
+			Client:       c,
+			Scheme:       scheme,
+			ClusterMap:   make(map[corev1.ObjectReference]*libsveltosset.Set),
+			ReferenceMap: make(map[corev1.ObjectReference]*libsveltosset.Set),
+			PolicyMux:    sync.Mutex{},
+		}
+
+		requests := controllers.RequeueClusterSummaryForReference(reconciler, context.TODO(), configMap)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests).To(ContainElement(reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: failed.Namespace, Name: failed.Name}}))
+	})
 })
@@ -0,0 +1,169 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// ComplianceReportPath is the path, on the diagnostics server, serving the
+	// compliance report
+	ComplianceReportPath = "/compliance"
+)
+
+// ClusterCompliance reports whether a cluster is compliant with a profile
+type ClusterCompliance struct {
+	ClusterNamespace string `json:"clusterNamespace"`
+	ClusterName      string `json:"clusterName"`
+	ClusterType      string `json:"clusterType"`
+
+	// Compliant is true when all features are provisioned, i.e. the current
+	// profile configuration is deployed and, when requested, healthy
+	Compliant bool `json:"compliant"`
+
+	// FailureMessages contains, per feature, why the cluster is not compliant
+	FailureMessages map[string]string `json:"failureMessages,omitempty"`
+}
+
+// ProfileCompliance reports the compliance of all clusters matching a
+// ClusterProfile/Profile
+type ProfileCompliance struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	CompliantClusters    int `json:"compliantClusters"`
+	NonCompliantClusters int `json:"nonCompliantClusters"`
+
+	Clusters []ClusterCompliance `json:"clusters"`
+}
+
+// getComplianceReport aggregates, per ClusterProfile/Profile, which clusters are compliant
+// and which are not. Report is built from ClusterSummary status.
+func getComplianceReport(ctx context.Context, c client.Client) ([]ProfileCompliance, error) {
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	if err := c.List(ctx, clusterSummaries); err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]*ProfileCompliance)
+	for i := range clusterSummaries.Items {
+		cs := &clusterSummaries.Items[i]
+
+		kind := configv1beta1.ClusterProfileKind
+		name, ok := cs.Labels[ClusterProfileLabelName]
+		namespace := ""
+		if !ok {
+			kind = configv1beta1.ProfileKind
+			name, ok = cs.Labels[ProfileLabelName]
+			if !ok {
+				continue
+			}
+			namespace = cs.Namespace
+		}
+
+		key := fmt.Sprintf("%s:%s/%s", kind, namespace, name)
+		profile, ok := profiles[key]
+		if !ok {
+			profile = &ProfileCompliance{Kind: kind, Namespace: namespace, Name: name,
+				Clusters: make([]ClusterCompliance, 0)}
+			profiles[key] = profile
+		}
+
+		clusterCompliance := getClusterCompliance(cs)
+		if clusterCompliance.Compliant {
+			profile.CompliantClusters++
+		} else {
+			profile.NonCompliantClusters++
+		}
+		profile.Clusters = append(profile.Clusters, clusterCompliance)
+	}
+
+	keys := make([]string, 0, len(profiles))
+	for k := range profiles {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	report := make([]ProfileCompliance, len(keys))
+	for i := range keys {
+		report[i] = *profiles[keys[i]]
+		sort.Slice(report[i].Clusters, func(j, k int) bool {
+			if report[i].Clusters[j].ClusterNamespace == report[i].Clusters[k].ClusterNamespace {
+				return report[i].Clusters[j].ClusterName < report[i].Clusters[k].ClusterName
+			}
+			return report[i].Clusters[j].ClusterNamespace < report[i].Clusters[k].ClusterNamespace
+		})
+	}
+
+	return report, nil
+}
+
+func getClusterCompliance(clusterSummary *configv1beta1.ClusterSummary) ClusterCompliance {
+	clusterCompliance := ClusterCompliance{
+		ClusterNamespace: clusterSummary.Spec.ClusterNamespace,
+		ClusterName:      clusterSummary.Spec.ClusterName,
+		ClusterType:      string(clusterSummary.Spec.ClusterType),
+		Compliant:        isCluterSummaryProvisioned(clusterSummary),
+	}
+
+	for i := range clusterSummary.Status.FeatureSummaries {
+		fs := &clusterSummary.Status.FeatureSummaries[i]
+		if fs.Status == configv1beta1.FeatureStatusProvisioned {
+			continue
+		}
+		if clusterCompliance.FailureMessages == nil {
+			clusterCompliance.FailureMessages = make(map[string]string)
+		}
+		message := string(fs.Status)
+		if fs.FailureMessage != nil {
+			message = *fs.FailureMessage
+		}
+		clusterCompliance.FailureMessages[string(fs.FeatureID)] = message
+	}
+
+	return clusterCompliance
+}
+
+// NewComplianceReportHandler returns an http.Handler serving, in JSON, the compliance
+// report across all managed clusters
+func NewComplianceReportHandler(c client.Client, logger logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, err := getComplianceReport(r.Context(), c)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to build compliance report: %v", err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to write compliance report: %v", err))
+		}
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Compliance report", func() {
+	getClusterSummary := func(profileName string, status configv1beta1.FeatureStatus,
+		failureMessage *string) *configv1beta1.ClusterSummary {
+
+		return &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      randomString(),
+				Namespace: randomString(),
+				Labels: map[string]string{
+					controllers.ClusterProfileLabelName: profileName,
+				},
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+				ClusterProfileSpec: configv1beta1.Spec{
+					PolicyRefs: []configv1beta1.PolicyRef{
+						{Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind), Namespace: randomString(), Name: randomString()},
+					},
+				},
+			},
+			Status: configv1beta1.ClusterSummaryStatus{
+				FeatureSummaries: []configv1beta1.FeatureSummary{
+					{FeatureID: configv1beta1.FeatureResources, Status: status, FailureMessage: failureMessage},
+				},
+			},
+		}
+	}
+
+	It("getComplianceReport reports compliant and non compliant clusters per profile", func() {
+		profileName := randomString()
+		failureMessage := randomString()
+
+		compliant := getClusterSummary(profileName, configv1beta1.FeatureStatusProvisioned, nil)
+		nonCompliant := getClusterSummary(profileName, configv1beta1.FeatureStatusFailed, &failureMessage)
+
+		initObjects := []client.Object{compliant, nonCompliant}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).Build()
+
+		report, err := controllers.GetComplianceReport(context.TODO(), c)
+		Expect(err).To(BeNil())
+		Expect(len(report)).To(Equal(1))
+		Expect(report[0].Kind).To(Equal(configv1beta1.ClusterProfileKind))
+		Expect(report[0].Name).To(Equal(profileName))
+		Expect(report[0].CompliantClusters).To(Equal(1))
+		Expect(report[0].NonCompliantClusters).To(Equal(1))
+
+		for i := range report[0].Clusters {
+			cluster := &report[0].Clusters[i]
+			if cluster.ClusterName == nonCompliant.Spec.ClusterName {
+				Expect(cluster.Compliant).To(BeFalse())
+				Expect(cluster.FailureMessages).To(HaveKeyWithValue(string(configv1beta1.FeatureResources), failureMessage))
+			} else {
+				Expect(cluster.ClusterName).To(Equal(compliant.Spec.ClusterName))
+				Expect(cluster.Compliant).To(BeTrue())
+				Expect(cluster.FailureMessages).To(BeEmpty())
+			}
+		}
+	})
+
+	It("NewComplianceReportHandler serves the compliance report in JSON", func() {
+		clusterSummary := getClusterSummary(randomString(), configv1beta1.FeatureStatusProvisioned, nil)
+
+		initObjects := []client.Object{clusterSummary}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).Build()
+
+		recorder := httptest.NewRecorder()
+		handler := controllers.NewComplianceReportHandler(c, logr.Discard())
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, controllers.ComplianceReportPath, http.NoBody))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var report []controllers.ProfileCompliance
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &report)).To(Succeed())
+		Expect(len(report)).To(Equal(1))
+		Expect(report[0].CompliantClusters).To(Equal(1))
+		Expect(report[0].Clusters[0].ClusterName).To(Equal(clusterSummary.Spec.ClusterName))
+	})
+})
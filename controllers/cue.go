@@ -0,0 +1,145 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// cueAnnotation, when set on a referenced ConfigMap/Secret (or Flux Source),
+	// indicates its content is a CUE package that must be evaluated before deployment
+	cueAnnotation = "projectsveltos.io/cue"
+
+	// cueClusterField is the field filled with the cluster information
+	// (kind, namespace, name, labels and annotations)
+	cueClusterField = "cluster"
+
+	// cueResourcesField is the field containing the resources to deploy
+	cueResourcesField = "resources"
+
+	cueExtension = ".cue"
+)
+
+func isCUE(referencedObject client.Object) bool {
+	annotations := referencedObject.GetAnnotations()
+	if annotations != nil {
+		if _, ok := annotations[cueAnnotation]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderCUE builds every .cue entry in data as a single package, fills the cluster field
+// (which must be declared, e.g. cluster: _) with the cluster information and returns the
+// resources contained in the resources field.
+// resources can be a list of resources or a struct whose fields are resources.
+// Constraints (for instance an organizational schema resources must satisfy) are
+// enforced by unification: if any resource violates them, the constraint errors are
+// returned.
+func renderCUE(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
+	data map[string]string, logger logr.Logger) ([]*unstructured.Unstructured, error) {
+
+	cluster, err := getTemplateResourceCluster(ctx, clusterSummary)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(data))
+	for k := range data {
+		if filepath.Ext(k) == cueExtension {
+			files = append(files, k)
+		}
+	}
+	// Build in a consistent order
+	sort.Strings(files)
+
+	// All files are part of the same instance, so they share the package scope
+	instance := build.NewContext().NewInstance("", nil)
+	for i := range files {
+		file, err := parser.ParseFile(files[i], data[files[i]])
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse cue %s: %v", files[i], err))
+			return nil, getCUEError(err)
+		}
+		if err := instance.AddSyntax(file); err != nil {
+			return nil, getCUEError(err)
+		}
+	}
+
+	cueCtx := cuecontext.New()
+	value := cueCtx.BuildInstance(instance)
+	if value.Err() != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to build cue: %v", value.Err()))
+		return nil, getCUEError(value.Err())
+	}
+
+	value = value.FillPath(cue.ParsePath(cueClusterField), cluster)
+
+	resources := value.LookupPath(cue.ParsePath(cueResourcesField))
+	if !resources.Exists() {
+		return nil, fmt.Errorf("cue does not define field %s", cueResourcesField)
+	}
+
+	if err := resources.Validate(cue.Concrete(true)); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("cue validation failed: %v", err))
+		return nil, getCUEError(err)
+	}
+
+	if resources.Kind() == cue.StructKind && !resources.LookupPath(cue.ParsePath("kind")).Exists() {
+		// struct whose fields are resources
+		list := make([]cue.Value, 0)
+		iter, err := resources.Fields()
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			list = append(list, iter.Value())
+		}
+		resources = cueCtx.NewList(list...)
+	}
+
+	output, err := resources.MarshalJSON()
+	if err != nil {
+		return nil, getCUEError(err)
+	}
+
+	return getResourcesFromJSON(string(output))
+}
+
+// getCUEError returns an error listing all CUE errors (for instance all violated
+// constraints) with their position
+func getCUEError(err error) error {
+	return fmt.Errorf("cue evaluation failed: %s", cueerrors.Details(err, nil))
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+const (
+	cueSchema = `package fleet
+
+cluster: _
+
+#Namespace: {
+	apiVersion: "v1"
+	kind:       "Namespace"
+	metadata: name: =~"^[a-z0-9-]+$"
+}
+
+resources: [string]: #Namespace
+`
+)
+
+var _ = Describe("CUE", func() {
+	var clusterSummary *configv1beta1.ClusterSummary
+
+	BeforeEach(func() {
+		clusterSummary = &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+			},
+		}
+	})
+
+	It("renderCUE evaluates the package using cluster information", func() {
+		data := map[string]string{
+			"schema.cue": cueSchema,
+			"namespaces.cue": `package fleet
+
+resources: team: metadata: name: "team-\(cluster.metadata.name)"
+`,
+		}
+
+		resources, err := controllers.RenderCUE(context.TODO(), clusterSummary, data, logr.Discard())
+		Expect(err).To(BeNil())
+		Expect(len(resources)).To(Equal(1))
+		Expect(resources[0].GetKind()).To(Equal("Namespace"))
+		Expect(resources[0].GetName()).To(Equal("team-" + clusterSummary.Spec.ClusterName))
+	})
+
+	It("renderCUE returns constraint errors when resources violate the schema", func() {
+		data := map[string]string{
+			"schema.cue": cueSchema,
+			"namespaces.cue": `package fleet
+
+resources: team: metadata: name: "Invalid_Name"
+`,
+		}
+
+		_, err := controllers.RenderCUE(context.TODO(), clusterSummary, data, logr.Discard())
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("Invalid_Name"))
+	})
+})
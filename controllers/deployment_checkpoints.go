@@ -0,0 +1,117 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+)
+
+// When deploying the resources contained in a referenced resource fails halfway (for instance
+// because of a transient error), following attempt resumes from the first resource not applied
+// yet instead of re-applying all of them. This matters for bundles with hundreds of resources.
+// A checkpoint is valid only as long as the resources to deploy do not change (same hash). It is
+// kept in memory only and removed as soon as all resources are successfully deployed.
+
+type deploymentCheckpoint struct {
+	// hash of the resources being deployed
+	hash string
+
+	// applied is the number of resources, from the first one, successfully deployed
+	applied int
+}
+
+var (
+	checkpointsMux        sync.Mutex
+	deploymentCheckpoints = make(map[string]deploymentCheckpoint)
+)
+
+// getCheckpointKey returns the key identifying the resources deployed, in the management or managed
+// cluster, because of featureID and referencedObject
+func getCheckpointKey(clusterSummary *configv1beta1.ClusterSummary, featureID configv1beta1.FeatureID,
+	referencedObject *corev1.ObjectReference, deployingToMgmtCluster bool) string {
+
+	return fmt.Sprintf("%s/%s:%s:%s:%s/%s:%t", clusterSummary.Namespace, clusterSummary.Name, featureID,
+		referencedObject.Kind, referencedObject.Namespace, referencedObject.Name, deployingToMgmtCluster)
+}
+
+// getResourcesHash returns the hash of the resources to deploy
+func getResourcesHash(resources []*unstructured.Unstructured) (string, error) {
+	h := sha256.New()
+	for i := range resources {
+		data, err := resources[i].MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		h.Write(data)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// getCheckpoint returns the number of resources already successfully deployed. Zero if there is no
+// checkpoint or if the checkpoint was taken for different resources.
+func getCheckpoint(key, hash string) int {
+	checkpointsMux.Lock()
+	defer checkpointsMux.Unlock()
+
+	checkpoint, ok := deploymentCheckpoints[key]
+	if !ok || checkpoint.hash != hash {
+		return 0
+	}
+	return checkpoint.applied
+}
+
+// setCheckpoint records that the first applied resources were successfully deployed
+func setCheckpoint(key, hash string, applied int) {
+	checkpointsMux.Lock()
+	defer checkpointsMux.Unlock()
+
+	if applied == 0 {
+		delete(deploymentCheckpoints, key)
+		return
+	}
+
+	deploymentCheckpoints[key] = deploymentCheckpoint{hash: hash, applied: applied}
+}
+
+// removeCheckpoint removes the checkpoint, if any, for key
+func removeCheckpoint(key string) {
+	checkpointsMux.Lock()
+	defer checkpointsMux.Unlock()
+
+	delete(deploymentCheckpoints, key)
+}
+
+// removeClusterSummaryCheckpoints removes all checkpoints for a ClusterSummary
+func removeClusterSummaryCheckpoints(clusterSummary *configv1beta1.ClusterSummary) {
+	checkpointsMux.Lock()
+	defer checkpointsMux.Unlock()
+
+	prefix := fmt.Sprintf("%s/%s:", clusterSummary.Namespace, clusterSummary.Name)
+	for key := range deploymentCheckpoints {
+		if strings.HasPrefix(key, prefix) {
+			delete(deploymentCheckpoints, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Deployment checkpoints", func() {
+	var clusterSummary *configv1beta1.ClusterSummary
+	var referencedObject *corev1.ObjectReference
+
+	BeforeEach(func() {
+		clusterSummary = &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+		}
+		referencedObject = &corev1.ObjectReference{
+			Kind:      string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			Namespace: randomString(),
+			Name:      randomString(),
+		}
+	})
+
+	It("getCheckpoint returns applied resources only for same resources hash", func() {
+		key := controllers.GetCheckpointKey(clusterSummary, configv1beta1.FeatureResources, referencedObject, false)
+		hash := randomString()
+
+		Expect(controllers.GetCheckpoint(key, hash)).To(BeZero())
+
+		controllers.SetCheckpoint(key, hash, 50)
+		Expect(controllers.GetCheckpoint(key, hash)).To(Equal(50))
+		Expect(controllers.GetCheckpoint(key, randomString())).To(BeZero())
+
+		controllers.RemoveCheckpoint(key)
+		Expect(controllers.GetCheckpoint(key, hash)).To(BeZero())
+	})
+
+	It("removeClusterSummaryCheckpoints removes all checkpoints for a ClusterSummary", func() {
+		resourcesKey := controllers.GetCheckpointKey(clusterSummary, configv1beta1.FeatureResources, referencedObject, false)
+		kustomizeKey := controllers.GetCheckpointKey(clusterSummary, configv1beta1.FeatureKustomize, referencedObject, true)
+		hash := randomString()
+
+		controllers.SetCheckpoint(resourcesKey, hash, 10)
+		controllers.SetCheckpoint(kustomizeKey, hash, 20)
+
+		otherClusterSummary := clusterSummary.DeepCopy()
+		otherClusterSummary.Name = randomString()
+		otherKey := controllers.GetCheckpointKey(otherClusterSummary, configv1beta1.FeatureResources, referencedObject, false)
+		controllers.SetCheckpoint(otherKey, hash, 30)
+
+		controllers.RemoveClusterSummaryCheckpoints(clusterSummary)
+		Expect(controllers.GetCheckpoint(resourcesKey, hash)).To(BeZero())
+		Expect(controllers.GetCheckpoint(kustomizeKey, hash)).To(BeZero())
+		Expect(controllers.GetCheckpoint(otherKey, hash)).To(Equal(30))
+
+		controllers.RemoveCheckpoint(otherKey)
+	})
+
+	It("getResourcesHash changes when resources change", func() {
+		resource := &unstructured.Unstructured{}
+		resource.SetAPIVersion("v1")
+		resource.SetKind("ConfigMap")
+		resource.SetName(randomString())
+
+		hash, err := controllers.GetResourcesHash([]*unstructured.Unstructured{resource})
+		Expect(err).To(BeNil())
+
+		sameHash, err := controllers.GetResourcesHash([]*unstructured.Unstructured{resource.DeepCopy()})
+		Expect(err).To(BeNil())
+		Expect(sameHash).To(Equal(hash))
+
+		resource.SetNamespace(randomString())
+		newHash, err := controllers.GetResourcesHash([]*unstructured.Unstructured{resource})
+		Expect(err).To(BeNil())
+		Expect(newHash).ToNot(Equal(hash))
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Deployment policies are CEL expressions defined by the admin in a ConfigMap in the management
+// cluster (one expression per key, the key being the policy name). Before being deployed, every
+// resource is evaluated, as variable object, against all policies. Resources are deployed only if
+// all policies evaluate to true. For instance:
+//
+//	no-host-path: "object.kind != 'Pod' || !has(object.spec.volumes) || object.spec.volumes.all(v, !has(v.hostPath))"
+
+const (
+	// policyViolationFailureReason is the FeatureSummary FailureReason set when
+	// a feature failed because resources violate deployment policies
+	policyViolationFailureReason = "PolicyViolation"
+)
+
+// deploymentPolicy is a compiled deployment policy
+type deploymentPolicy struct {
+	name    string
+	program cel.Program
+}
+
+var (
+	// compiledPolicies caches the deployment policies compiled from the ConfigMap with
+	// resourceVersion compiledPoliciesVersion. CEL programs are safe for concurrent use.
+	compiledPolicies        []deploymentPolicy
+	compiledPoliciesVersion string
+	compiledPoliciesMux     sync.Mutex
+)
+
+// getDeploymentPoliciesInstance returns the ConfigMap set via SetDeploymentPoliciesConfigMap.
+// Nil if no ConfigMap is set or it does not exist.
+func getDeploymentPoliciesInstance(ctx context.Context, c client.Client) (*corev1.ConfigMap, error) {
+	name := getDeploymentPoliciesConfigMap()
+	if name == "" {
+		return nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: projectsveltos, Name: name}, configMap)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+// isDeploymentPoliciesConfigMap returns true if o is the ConfigMap set via SetDeploymentPoliciesConfigMap
+func isDeploymentPoliciesConfigMap(o client.Object) bool {
+	name := getDeploymentPoliciesConfigMap()
+	return name != "" && o.GetNamespace() == projectsveltos && o.GetName() == name
+}
+
+// getDeploymentPolicies returns the deployment policies defined in the ConfigMap set via
+// SetDeploymentPoliciesConfigMap. Nil if no ConfigMap is set or it does not exist.
+// Policies are compiled only when the ConfigMap resourceVersion changes.
+func getDeploymentPolicies(ctx context.Context) ([]deploymentPolicy, error) {
+	configMap, err := getDeploymentPoliciesInstance(ctx, getManagementClusterClient())
+	if err != nil || configMap == nil {
+		return nil, err
+	}
+
+	compiledPoliciesMux.Lock()
+	defer compiledPoliciesMux.Unlock()
+
+	if compiledPoliciesVersion != "" && compiledPoliciesVersion == configMap.ResourceVersion {
+		return compiledPolicies, nil
+	}
+
+	policies, err := compileDeploymentPolicies(configMap.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	compiledPolicies = policies
+	compiledPoliciesVersion = configMap.ResourceVersion
+	return policies, nil
+}
+
+// writeDeploymentPoliciesHash writes the content of the deployment policies ConfigMap into w, so
+// changing the policies causes resources to be evaluated (and deployed) again.
+// Nothing is written if no deployment policies ConfigMap is set or it does not exist.
+func writeDeploymentPoliciesHash(ctx context.Context, c client.Client, w io.Writer) error {
+	configMap, err := getDeploymentPoliciesInstance(ctx, c)
+	if err != nil || configMap == nil {
+		return err
+	}
+
+	writeDataSectionHash(w, configMap.Data)
+	return nil
+}
+
+// compileDeploymentPolicies compiles CEL expressions. Policies are sorted by name.
+func compileDeploymentPolicies(expressions map[string]string) ([]deploymentPolicy, error) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(expressions))
+	for name := range expressions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policies := make([]deploymentPolicy, len(names))
+	for i := range names {
+		ast, issues := env.Compile(expressions[names[i]])
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("deployment policy %s is invalid: %w", names[i], issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("deployment policy %s does not evaluate to a bool", names[i])
+		}
+
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("deployment policy %s is invalid: %w", names[i], err)
+		}
+		policies[i] = deploymentPolicy{name: names[i], program: program}
+	}
+
+	return policies, nil
+}
+
+// evaluateDeploymentPolicies evaluates resources against policies. If any resource violates any policy,
+// a NonRetriableError listing all violations is returned. A policy which cannot be evaluated on a
+// resource (for instance because of a missing field) is considered violated.
+func evaluateDeploymentPolicies(policies []deploymentPolicy, resources []*unstructured.Unstructured) error {
+	violations := make([]string, 0)
+	for i := range resources {
+		for j := range policies {
+			allowed, err := evaluateDeploymentPolicy(&policies[j], resources[i])
+			if err != nil {
+				violations = append(violations, err.Error())
+				continue
+			}
+			if !allowed {
+				violations = append(violations, fmt.Sprintf("%s %s/%s violates deployment policy %s",
+					resources[i].GetKind(), resources[i].GetNamespace(), resources[i].GetName(), policies[j].name))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &NonRetriableError{Message: strings.Join(violations, "\n"), Reason: policyViolationFailureReason}
+}
+
+func evaluateDeploymentPolicy(policy *deploymentPolicy, resource *unstructured.Unstructured) (bool, error) {
+	out, _, err := policy.program.Eval(map[string]interface{}{"object": resource.Object})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate deployment policy %s on %s %s/%s: %w", policy.name,
+			resource.GetKind(), resource.GetNamespace(), resource.GetName(), err)
+	}
+
+	allowed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("deployment policy %s does not evaluate to a bool on %s %s/%s", policy.name,
+			resource.GetKind(), resource.GetNamespace(), resource.GetName())
+	}
+	return allowed, nil
+}
+
+// validateDeploymentPolicies verifies resources do not violate any deployment policy
+func validateDeploymentPolicies(ctx context.Context, resources []*unstructured.Unstructured) error {
+	policies, err := getDeploymentPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(policies) == 0 {
+		return nil
+	}
+
+	return evaluateDeploymentPolicies(policies, resources)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/projectsveltos/addon-controller/controllers"
+)
+
+const (
+	noHostPathPolicy = "object.kind != 'Pod' || !has(object.spec.volumes) || object.spec.volumes.all(v, !has(v.hostPath))"
+)
+
+var _ = Describe("Deployment policies", func() {
+	var pod *unstructured.Unstructured
+
+	BeforeEach(func() {
+		pod = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"namespace": randomString(),
+					"name":      randomString(),
+				},
+				"spec": map[string]interface{}{
+					"volumes": []interface{}{
+						map[string]interface{}{
+							"name":     "data",
+							"emptyDir": map[string]interface{}{},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("evaluateDeploymentPolicies allows resources satisfying all policies", func() {
+		policies, err := controllers.CompileDeploymentPolicies(map[string]string{"no-host-path": noHostPathPolicy})
+		Expect(err).To(BeNil())
+
+		Expect(controllers.EvaluateDeploymentPolicies(policies, []*unstructured.Unstructured{pod})).To(Succeed())
+	})
+
+	It("evaluateDeploymentPolicies returns a NonRetriableError on policy violation", func() {
+		policies, err := controllers.CompileDeploymentPolicies(map[string]string{"no-host-path": noHostPathPolicy})
+		Expect(err).To(BeNil())
+
+		Expect(unstructured.SetNestedSlice(pod.Object, []interface{}{
+			map[string]interface{}{
+				"name":     "host",
+				"hostPath": map[string]interface{}{"path": "/var/run"},
+			},
+		}, "spec", "volumes")).To(Succeed())
+
+		err = controllers.EvaluateDeploymentPolicies(policies, []*unstructured.Unstructured{pod})
+		Expect(err).ToNot(BeNil())
+		var nonRetriableError *controllers.NonRetriableError
+		Expect(errors.As(err, &nonRetriableError)).To(BeTrue())
+		Expect(nonRetriableError.Reason).To(Equal("PolicyViolation"))
+		Expect(err.Error()).To(ContainSubstring("no-host-path"))
+	})
+
+	It("evaluateDeploymentPolicies reports policies failing to evaluate as violations", func() {
+		policies, err := controllers.CompileDeploymentPolicies(map[string]string{
+			"min-replicas": "object.kind != 'Deployment' || object.spec.replicas > 1",
+		})
+		Expect(err).To(BeNil())
+
+		deployment := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"namespace": randomString(),
+					"name":      randomString(),
+				},
+				"spec": map[string]interface{}{},
+			},
+		}
+
+		err = controllers.EvaluateDeploymentPolicies(policies, []*unstructured.Unstructured{pod, deployment})
+		Expect(err).ToNot(BeNil())
+		var nonRetriableError *controllers.NonRetriableError
+		Expect(errors.As(err, &nonRetriableError)).To(BeTrue())
+		Expect(nonRetriableError.Reason).To(Equal("PolicyViolation"))
+		Expect(err.Error()).To(ContainSubstring("min-replicas"))
+		Expect(err.Error()).To(ContainSubstring(deployment.GetName()))
+		Expect(err.Error()).ToNot(ContainSubstring(pod.GetName()))
+	})
+
+	It("compileDeploymentPolicies returns an error for invalid policies", func() {
+		_, err := controllers.CompileDeploymentPolicies(map[string]string{"invalid": "object.metadata.name +"})
+		Expect(err).ToNot(BeNil())
+
+		_, err = controllers.CompileDeploymentPolicies(map[string]string{"not-bool": "'a string'"})
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,139 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/deployer"
+)
+
+const (
+	// deploymentTimeoutOption is the deployer option carrying the ClusterProfile/Profile
+	// DeploymentTimeout
+	deploymentTimeoutOption = "deploymentTimeout"
+
+	// deploymentTimeoutFailureReason is the FeatureSummary FailureReason set when
+	// a feature failed because its deployment exceeded DeploymentTimeout
+	deploymentTimeoutFailureReason = "Timeout"
+
+	// deploymentTimeoutBackoff is how long to wait before deploying again a feature
+	// whose deployment exceeded DeploymentTimeout
+	deploymentTimeoutBackoff = 5 * time.Minute
+)
+
+// DeploymentTimeoutError is returned when deploying a feature takes longer than
+// DeploymentTimeout
+type DeploymentTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *DeploymentTimeoutError) Error() string {
+	return fmt.Sprintf("deployment did not complete within %s", e.Timeout)
+}
+
+// setDeploymentTimeoutOption adds DeploymentTimeout, if set, to the deployer options
+func setDeploymentTimeoutOption(clusterSummary *configv1beta1.ClusterSummary, o *deployer.Options) {
+	timeout := clusterSummary.Spec.ClusterProfileSpec.DeploymentTimeout
+	if timeout == nil || timeout.Duration == 0 {
+		return
+	}
+
+	o.HandlerOptions[deploymentTimeoutOption] = timeout.Duration.String()
+}
+
+// getDeploymentTimeout returns DeploymentTimeout from the deployer options. Zero if not set.
+func getDeploymentTimeout(o deployer.Options) time.Duration {
+	if o.HandlerOptions == nil {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(o.HandlerOptions[deploymentTimeoutOption])
+	if err != nil {
+		return 0
+	}
+
+	return timeout
+}
+
+// withDeploymentTimeout bounds ctx by DeploymentTimeout, if any. Returned function converts an
+// error caused by the timeout to a DeploymentTimeoutError.
+func withDeploymentTimeout(ctx context.Context, o deployer.Options) (context.Context, context.CancelFunc,
+	func(error) error) {
+
+	timeout := getDeploymentTimeout(o)
+	if timeout == 0 {
+		return ctx, func() {}, func(err error) error { return err }
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, func(err error) error {
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &DeploymentTimeoutError{Timeout: timeout}
+		}
+		return err
+	}
+}
+
+func isDeploymentTimeoutError(err error) bool {
+	var timeoutError *DeploymentTimeoutError
+	return errors.As(err, &timeoutError)
+}
+
+// isDeploymentTimeoutOnly returns true if err is a DeploymentTimeoutError or is made exclusively
+// of DeploymentTimeoutErrors
+func isDeploymentTimeoutOnly(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		found := false
+		for i := range errs {
+			if errs[i] == nil {
+				continue
+			}
+			if !isDeploymentTimeoutOnly(errs[i]) {
+				return false
+			}
+			found = true
+		}
+		return found
+	}
+
+	return isDeploymentTimeoutError(err)
+}
+
+// isDeploymentTimeoutBackoffOver returns false if feature failed because of DeploymentTimeout
+// less than deploymentTimeoutBackoff ago
+func isDeploymentTimeoutBackoffOver(fs *configv1beta1.FeatureSummary) bool {
+	if fs == nil || !isFailedForDeploymentTimeout(fs) || fs.LastAppliedTime == nil {
+		return true
+	}
+
+	return time.Since(fs.LastAppliedTime.Time) > deploymentTimeoutBackoff
+}
+
+func isFailedForDeploymentTimeout(fs *configv1beta1.FeatureSummary) bool {
+	return fs != nil && fs.Status == configv1beta1.FeatureStatusFailed &&
+		fs.FailureReason != nil && *fs.FailureReason == deploymentTimeoutFailureReason
+}
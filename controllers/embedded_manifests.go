@@ -0,0 +1,189 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	driftdetection "github.com/projectsveltos/addon-controller/pkg/drift-detection"
+	"github.com/projectsveltos/libsveltos/lib/crd"
+	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
+)
+
+// Embedded manifests never change. Each one is parsed the first time it is needed
+// and callers always get a deep copy of the parsed objects.
+var (
+	debuggingConfigurationCRD = sync.OnceValues(func() (*unstructured.Unstructured, error) {
+		return k8s_utils.GetUnstructured(crd.GetDebuggingConfigurationCRDYAML())
+	})
+
+	resourceSummaryCRD = sync.OnceValues(func() (*unstructured.Unstructured, error) {
+		return k8s_utils.GetUnstructured(crd.GetResourceSummaryCRDYAML())
+	})
+
+	driftDetectionManagerObjects = sync.OnceValues(func() ([]*unstructured.Unstructured, error) {
+		return parseEmbeddedManifest(string(driftdetection.GetDriftDetectionManagerYAML()))
+	})
+
+	driftDetectionManagerInMgmtClusterObjects = sync.OnceValues(func() ([]*unstructured.Unstructured, error) {
+		return parseEmbeddedManifest(string(driftdetection.GetDriftDetectionManagerInMgmtClusterYAML()))
+	})
+)
+
+// imageRegistryOverride, when set, replaces the registry of every container image in
+// embedded manifests. Used for clusters pulling images from a private mirror.
+var imageRegistryOverride string
+
+func SetImageRegistryOverride(registry string) {
+	imageRegistryOverride = strings.TrimSuffix(registry, "/")
+}
+
+func parseEmbeddedManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	elements, err := customSplit(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, len(elements))
+	for i := range elements {
+		objects[i], err = k8s_utils.GetUnstructured([]byte(elements[i]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+// getDebuggingConfigurationCRD returns the DebuggingConfiguration CRD
+func getDebuggingConfigurationCRD() (*unstructured.Unstructured, error) {
+	u, err := debuggingConfigurationCRD()
+	if err != nil {
+		return nil, err
+	}
+	return u.DeepCopy(), nil
+}
+
+// getResourceSummaryCRD returns the ResourceSummary CRD
+func getResourceSummaryCRD() (*unstructured.Unstructured, error) {
+	u, err := resourceSummaryCRD()
+	if err != nil {
+		return nil, err
+	}
+	return u.DeepCopy(), nil
+}
+
+// getDriftDetectionManagerObjects returns the drift-detection-manager resources to deploy in a
+// managed cluster. Every string in those resources is instantiated using replacer.
+func getDriftDetectionManagerObjects(replacer *strings.Replacer) ([]*unstructured.Unstructured, error) {
+	objects, err := driftDetectionManagerObjects()
+	if err != nil {
+		return nil, err
+	}
+	return instantiateEmbeddedObjects(objects, replacer), nil
+}
+
+// getDriftDetectionManagerInMgmtClusterObjects returns the drift-detection-manager resources to deploy
+// in the management cluster. Every string in those resources is instantiated using replacer.
+func getDriftDetectionManagerInMgmtClusterObjects(replacer *strings.Replacer) ([]*unstructured.Unstructured, error) {
+	objects, err := driftDetectionManagerInMgmtClusterObjects()
+	if err != nil {
+		return nil, err
+	}
+	return instantiateEmbeddedObjects(objects, replacer), nil
+}
+
+// instantiateEmbeddedObjects returns a deep copy of objects with replacer applied to all string values
+// and, if set, imageRegistryOverride applied to all container images.
+func instantiateEmbeddedObjects(objects []*unstructured.Unstructured,
+	replacer *strings.Replacer) []*unstructured.Unstructured {
+
+	result := make([]*unstructured.Unstructured, len(objects))
+	for i := range objects {
+		u := objects[i].DeepCopy()
+		u.Object = replaceStringValues(u.Object, replacer).(map[string]interface{})
+		overrideImageRegistry(u, imageRegistryOverride)
+		result[i] = u
+	}
+	return result
+}
+
+// overrideImageRegistry replaces the registry of containers and initContainers images in
+// the pod template of u, if any.
+func overrideImageRegistry(u *unstructured.Unstructured, registry string) {
+	if registry == "" {
+		return
+	}
+
+	for _, field := range []string{"containers", "initContainers"} {
+		path := []string{"spec", "template", "spec", field}
+		containers, found, err := unstructured.NestedSlice(u.Object, path...)
+		if err != nil || !found {
+			continue
+		}
+
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if image, ok := container["image"].(string); ok {
+				container["image"] = replaceImageRegistry(image, registry)
+			}
+		}
+
+		// containers were modified in place but NestedSlice returns a deep copy
+		_ = unstructured.SetNestedSlice(u.Object, containers, path...)
+	}
+}
+
+// replaceImageRegistry returns image with its registry replaced by registry.
+// An image without explicit registry (e.g. projectsveltos/drift-detection-manager:main)
+// is considered to be hosted on docker.io.
+func replaceImageRegistry(image, registry string) string {
+	repository := image
+	if i := strings.Index(image, "/"); i != -1 {
+		host := image[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			repository = image[i+1:]
+		}
+	}
+
+	return registry + "/" + repository
+}
+
+func replaceStringValues(value interface{}, replacer *strings.Replacer) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replacer.Replace(v)
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = replaceStringValues(v[k], replacer)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = replaceStringValues(v[i], replacer)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -16,6 +16,10 @@ limitations under the License.
 
 package controllers
 
+import (
+	"strings"
+)
+
 var (
 	UpdateClusterSummaries                = updateClusterSummaries
 	CreateClusterSummary                  = createClusterSummary
@@ -58,11 +62,25 @@ var (
 	ShouldReconcile                      = (*ClusterSummaryReconciler).shouldReconcile
 	UpdateChartMap                       = (*ClusterSummaryReconciler).updateChartMap
 	ShouldRedeploy                       = (*ClusterSummaryReconciler).shouldRedeploy
+	IsFullResyncDue                      = (*ClusterSummaryReconciler).isFullResyncDue
+	GetFullResyncRequeueAfter            = (*ClusterSummaryReconciler).getFullResyncRequeueAfter
 	CanRemoveFinalizer                   = (*ClusterSummaryReconciler).canRemoveFinalizer
 	ReconcileDelete                      = (*ClusterSummaryReconciler).reconcileDelete
+	IsManagementClusterOnlyCleanup       = (*ClusterSummaryReconciler).isManagementClusterOnlyCleanup
 	AreDependenciesDeployed              = (*ClusterSummaryReconciler).areDependenciesDeployed
 	SetFailureMessage                    = (*ClusterSummaryReconciler).setFailureMessage
 	ResetFeatureStatus                   = (*ClusterSummaryReconciler).resetFeatureStatus
+	UpdateFeaturesConvergence            = (*ClusterSummaryReconciler).updateFeaturesConvergence
+
+	StoreDeployedFeatures   = storeDeployedFeatures
+	RestoreDeployedFeatures = restoreDeployedFeatures
+
+	ProtectReferencedResources    = (*ClusterSummaryReconciler).protectReferencedResources
+	ReleaseReferencedResources    = (*ClusterSummaryReconciler).releaseReferencedResources
+	IndexReferencedResources      = (*ClusterSummaryReconciler).indexReferencedResources
+	ReleaseAllReferencedResources = (*ClusterSummaryReconciler).releaseAllReferencedResources
+	ReferencedResourceIndex       = referencedResourceIndex
+	ValidateReferences            = (*ClusterSummaryReconciler).validateReferences
 
 	ConvertResultStatus               = (*ClusterSummaryReconciler).convertResultStatus
 	RequeueClusterSummaryForReference = (*ClusterSummaryReconciler).requeueClusterSummaryForReference
@@ -78,6 +96,7 @@ var (
 	GetClusterSummary            = getClusterSummary
 	AddLabel                     = addLabel
 	UpdateResource               = updateResource
+	ValidateResources            = validateResources
 	CreateNamespace              = createNamespace
 	GetEntryKey                  = getEntryKey
 	DeployContentOfConfigMap     = deployContentOfConfigMap
@@ -95,6 +114,13 @@ var (
 	HandleResourceDelete         = handleResourceDelete
 	GetSecret                    = getSecret
 	ReadFiles                    = readFiles
+	IsManagementClusterOnly      = isManagementClusterOnly
+	IsUnmanagedResource          = isUnmanagedResource
+	GenerateUnmanagedReport      = generateUnmanagedResourceReport
+	IsImmutableFieldError        = isImmutableFieldError
+	ShouldRecreateOnImmutable    = shouldRecreateOnImmutableChange
+	MoveWebhooksLast             = moveWebhookConfigurationsLast
+	GetPolicyRefName             = getPolicyRefName
 
 	AddExtraLabels      = addExtraLabels
 	AddExtraAnnotations = addExtraAnnotations
@@ -103,14 +129,30 @@ var (
 	ResourcesHash   = resourcesHash
 	GetResourceRefs = getResourceRefs
 
-	UndeployKustomizeRefs             = undeployKustomizeRefs
-	KustomizationHash                 = kustomizationHash
-	GetKustomizeReferenceResourceHash = getKustomizeReferenceResourceHash
-	ExtractTarGz                      = extractTarGz
-	//nolint: gocritic // getDataSectionHash is generic and needs instantiation
-	GetStringDataSectionHash = func(aMap map[string]string) string { return getDataSectionHash(aMap) }
-	//nolint: gocritic // getDataSectionHash is generic and needs instantiation
-	GetByteDataSectionHash                  = func(aMap map[string][]byte) string { return getDataSectionHash(aMap) }
+	UndeployKustomizeRefs               = undeployKustomizeRefs
+	KustomizationHash                   = kustomizationHash
+	WriteKustomizeReferenceResourceHash = writeKustomizeReferenceResourceHash
+	ExtractTarGz                        = extractTarGz
+	GetStringDataSectionHash            = func(aMap map[string]string) string {
+		var sb strings.Builder
+		writeDataSectionHash(&sb, aMap)
+		return sb.String()
+	}
+	GetByteDataSectionHash = func(aMap map[string][]byte) string {
+		var sb strings.Builder
+		writeDataSectionHash(&sb, aMap)
+		return sb.String()
+	}
+	GetReferenceHashKey = func(apiVersion, kind, namespace, name string) string {
+		var sb strings.Builder
+		writeReferenceHashKey(&sb, apiVersion, kind, namespace, name)
+		return sb.String()
+	}
+	GetMissingReferenceHash = func(apiVersion, kind, namespace, name string) string {
+		var sb strings.Builder
+		writeMissingReferenceHash(&sb, apiVersion, kind, namespace, name)
+		return sb.String()
+	}
 	InstantiateKustomizeSubstituteValues    = instantiateKustomizeSubstituteValues
 	GetKustomizeSubstituteValuesFrom        = getKustomizeSubstituteValuesFrom
 	GetKustomizeSubstituteValues            = getKustomizeSubstituteValues
@@ -126,10 +168,14 @@ var (
 	CreateReportForUnmanagedHelmRelease      = createReportForUnmanagedHelmRelease
 	UpdateClusterReportWithHelmReports       = updateClusterReportWithHelmReports
 	HandleCharts                             = handleCharts
-	GetHelmReferenceResourceHash             = getHelmReferenceResourceHash
+	WriteHelmReferenceResourceHash           = writeHelmReferenceResourceHash
 	GetHelmChartValuesHash                   = getHelmChartValuesHash
 	GetCredentialsAndCAFiles                 = getCredentialsAndCAFiles
+	GetRepositoryCredentials                 = getRepositoryCredentials
+	SetRepositoryCredentials                 = setRepositoryCredentials
+	GetReleaseLabels                         = getReleaseLabels
 	GetInstantiatedChart                     = getInstantiatedChart
+	GetInstantiatedValues                    = getInstantiatedValues
 
 	InstantiateTemplateValues = instantiateTemplateValues
 
@@ -140,9 +186,19 @@ var (
 )
 
 type (
-	ReleaseInfo = releaseInfo
+	ReleaseInfo           = releaseInfo
+	RegistryClientOptions = registryClientOptions
 )
 
+func NewRegistryClientOptions(username, password, caPath string, skipTLSVerify bool) *RegistryClientOptions {
+	return &registryClientOptions{
+		username:      username,
+		password:      password,
+		caPath:        caPath,
+		skipTLSVerify: skipTLSVerify,
+	}
+}
+
 var (
 	GetClusterReportName        = getClusterReportName
 	GetClusterConfigurationName = getClusterConfigurationName
@@ -160,6 +216,10 @@ var (
 	GetDriftDetectionNamespaceInMgmtCluster          = getDriftDetectionNamespaceInMgmtCluster
 	TransformDriftExclusionsToPatches                = transformDriftExclusionsToPatches
 
+	GetDriftDetectionManagerReplacer             = getDriftDetectionManagerReplacer
+	GetDriftDetectionManagerInMgmtClusterObjects = getDriftDetectionManagerInMgmtClusterObjects
+	ReplaceImageRegistry                         = replaceImageRegistry
+
 	GetResourceSummaryNamespace = getResourceSummaryNamespace
 	GetResourceSummaryName      = getResourceSummaryName
 )
@@ -190,6 +250,9 @@ var (
 	UpdateReloaderWithDeployedResources     = updateReloaderWithDeployedResources
 	ConvertResourceReportsToObjectReference = convertResourceReportsToObjectReference
 	ConvertHelmResourcesToObjectReference   = convertHelmResourcesToObjectReference
+	PodSpecConsumes                         = podSpecConsumes
+	ReloadConsumers                         = reloadConsumers
+	RestorePolicyHash                       = restorePolicyHash
 )
 
 var (
@@ -206,3 +269,60 @@ var (
 var (
 	RemoveDuplicates = removeDuplicates
 )
+
+var (
+	RenderJsonnet        = renderJsonnet
+	GetResourcesFromJSON = getResourcesFromJSON
+	RenderCUE            = renderCUE
+)
+
+var (
+	GetComplianceReport = getComplianceReport
+	GetInventory        = getInventory
+)
+
+var (
+	SetDeploymentTimeoutOption = setDeploymentTimeoutOption
+	WithDeploymentTimeout      = withDeploymentTimeout
+	IsDeploymentTimeoutOnly    = isDeploymentTimeoutOnly
+)
+
+var (
+	GenerateWebhookCertificates     = generateWebhookCertificates
+	InjectWebhookCABundle           = injectWebhookCABundle
+	WebhookCertificateNeedsRotation = webhookCertificateNeedsRotation
+	WriteWebhookCertificates        = writeWebhookCertificates
+)
+
+var (
+	AddArchitectures       = addArchitectures
+	GetArchitecturePatches = getArchitecturePatches
+)
+
+var (
+	GetCheckpointKey                = getCheckpointKey
+	GetResourcesHash                = getResourcesHash
+	GetCheckpoint                   = getCheckpoint
+	SetCheckpoint                   = setCheckpoint
+	RemoveCheckpoint                = removeCheckpoint
+	RemoveClusterSummaryCheckpoints = removeClusterSummaryCheckpoints
+)
+
+var (
+	CompileDeploymentPolicies  = compileDeploymentPolicies
+	EvaluateDeploymentPolicies = evaluateDeploymentPolicies
+)
+
+var (
+	GetLibraryTemplates    = getLibraryTemplates
+	ExecuteLibraryTemplate = executeLibraryTemplate
+)
+
+var (
+	GetFeatureLogger = getFeatureLogger
+)
+
+var (
+	ProfileClusterIndex                    = profileClusterIndex
+	IndexClusterSummaryByProfileAndCluster = indexClusterSummaryByProfileAndCluster
+)
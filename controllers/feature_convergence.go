@@ -0,0 +1,131 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/scope"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// isFeatureConverged returns true if feature is not in Provisioning or Failed state
+func isFeatureConverged(status configv1beta1.FeatureStatus) bool {
+	switch status {
+	case configv1beta1.FeatureStatusProvisioning,
+		configv1beta1.FeatureStatusFailed,
+		configv1beta1.FeatureStatusFailedNonRetriable:
+		return false
+	default:
+		return true
+	}
+}
+
+// getFeatureConvergedCondition returns the type of the condition tracking since when featureID
+// has not converged
+func getFeatureConvergedCondition(featureID configv1beta1.FeatureID) string {
+	return string(featureID) + configv1beta1.FeatureConvergedConditionSuffix
+}
+
+// getNotConvergedSince returns the time feature was first seen not converged. Time is persisted
+// as LastTransitionTime of the feature converged condition, so it survives controller restarts.
+// If feature was not tracked yet, it starts being tracked from now.
+func getNotConvergedSince(clusterSummaryScope *scope.ClusterSummaryScope, fs *configv1beta1.FeatureSummary) time.Time {
+	conditionType := getFeatureConvergedCondition(fs.FeatureID)
+	clusterSummaryScope.SetCondition(conditionType, metav1.ConditionFalse, string(fs.Status), "")
+
+	condition := meta.FindStatusCondition(clusterSummaryScope.ClusterSummary.Status.Conditions, conditionType)
+	return condition.LastTransitionTime.Time
+}
+
+// forgetFeatureConvergence stops tracking feature and removes corresponding metric
+func forgetFeatureConvergence(clusterSummaryScope *scope.ClusterSummaryScope, featureID configv1beta1.FeatureID) {
+	clusterSummaryScope.RemoveCondition(getFeatureConvergedCondition(featureID))
+	deleteFeatureNotConvergedMetric(clusterSummaryScope.ClusterSummary, featureID)
+}
+
+// cleanFeaturesConvergence removes the metrics of all features for a ClusterSummary
+func (r *ClusterSummaryReconciler) cleanFeaturesConvergence(clusterSummary *configv1beta1.ClusterSummary) {
+	for _, featureID := range []configv1beta1.FeatureID{configv1beta1.FeatureResources,
+		configv1beta1.FeatureHelm, configv1beta1.FeatureKustomize} {
+
+		deleteFeatureNotConvergedMetric(clusterSummary, featureID)
+	}
+}
+
+// updateFeaturesConvergence tracks how long each feature has been in Provisioning/Failed state and
+// exposes it as a metric.
+// When StuckFeatureThreshold is set, ClusterSummary Degraded condition is set to True if at least one
+// feature has not converged for longer than the threshold. In that case, returned duration is zero.
+// Otherwise returned duration is the time after which the first not converged feature will be considered
+// stuck (zero if all features are converged).
+func (r *ClusterSummaryReconciler) updateFeaturesConvergence(clusterSummaryScope *scope.ClusterSummaryScope,
+	logger logr.Logger) time.Duration {
+
+	clusterSummary := clusterSummaryScope.ClusterSummary
+	now := time.Now()
+
+	var stuck []string
+	var requeueAfter time.Duration
+	for i := range clusterSummary.Status.FeatureSummaries {
+		fs := &clusterSummary.Status.FeatureSummaries[i]
+		// In DryRun mode features are always reported as Provisioning
+		if clusterSummaryScope.IsDryRunSync() || isFeatureConverged(fs.Status) {
+			forgetFeatureConvergence(clusterSummaryScope, fs.FeatureID)
+			continue
+		}
+
+		since := getNotConvergedSince(clusterSummaryScope, fs)
+		trackFeatureNotConverged(clusterSummary, fs.FeatureID, since)
+
+		if r.StuckFeatureThreshold == 0 {
+			continue
+		}
+
+		remaining := r.StuckFeatureThreshold - now.Sub(since)
+		if remaining <= 0 {
+			stuck = append(stuck, fmt.Sprintf("%s (%s)", fs.FeatureID, fs.Status))
+		} else if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+
+	if r.StuckFeatureThreshold == 0 {
+		clusterSummaryScope.RemoveCondition(configv1beta1.DegradedCondition)
+		return 0
+	}
+
+	if len(stuck) > 0 {
+		msg := fmt.Sprintf("features not provisioned for more than %s: %s",
+			r.StuckFeatureThreshold, strings.Join(stuck, ", "))
+		logger.V(logs.LogInfo).Info(msg)
+		clusterSummaryScope.SetCondition(configv1beta1.DegradedCondition, metav1.ConditionTrue,
+			configv1beta1.FeaturesNotConvergedReason, msg)
+		return 0
+	}
+
+	clusterSummaryScope.SetCondition(configv1beta1.DegradedCondition, metav1.ConditionFalse,
+		configv1beta1.FeaturesConvergedReason, "")
+	return requeueAfter
+}
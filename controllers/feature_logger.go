@@ -0,0 +1,113 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+)
+
+var (
+	// featureLogVerbosity contains, per feature, the log verbosity overriding the global one
+	featureLogVerbosity = map[configv1beta1.FeatureID]int{}
+)
+
+// SetFeatureLogVerbosity sets, per feature, the log verbosity used by that feature handlers.
+// Features not present keep using the global verbosity.
+func SetFeatureLogVerbosity(settings map[string]int) error {
+	verbosity := make(map[configv1beta1.FeatureID]int, len(settings))
+	for k, v := range settings {
+		featureID := configv1beta1.FeatureID(k)
+		switch featureID {
+		case configv1beta1.FeatureResources, configv1beta1.FeatureHelm, configv1beta1.FeatureKustomize:
+			verbosity[featureID] = v
+		default:
+			return fmt.Errorf("unknown feature %q", k)
+		}
+	}
+
+	featureLogVerbosity = verbosity
+	return nil
+}
+
+// getFeatureLogger returns a logger carrying cluster, ClusterSummary and feature fields.
+// If a log verbosity is configured for the feature, returned logger uses it.
+func getFeatureLogger(logger logr.Logger, clusterNamespace, clusterName, clusterSummaryName, featureID string) logr.Logger {
+	logger = logger.WithValues("clusternamespace", clusterNamespace,
+		"clustername", clusterName,
+		"clustersummary", clusterSummaryName,
+		"feature", featureID)
+
+	verbosity, ok := featureLogVerbosity[configv1beta1.FeatureID(featureID)]
+	if !ok {
+		return logger
+	}
+
+	sink := logger.GetSink()
+	if sink == nil {
+		return logger
+	}
+	// featureLogSink adds one frame on top of the wrapped sink
+	if cd, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = cd.WithCallDepth(1)
+	}
+	return logr.New(&featureLogSink{sink: sink, verbosity: verbosity})
+}
+
+// featureLogSink is a logr.LogSink overriding the verbosity of the wrapped LogSink.
+type featureLogSink struct {
+	sink      logr.LogSink
+	verbosity int
+}
+
+// Init is a no-op. Wrapped LogSink is already initialized.
+func (s *featureLogSink) Init(_ logr.RuntimeInfo) {
+}
+
+// Enabled returns true if level is enabled by the feature verbosity. The global verbosity
+// is not considered, so it can be both raised and lowered per feature.
+func (s *featureLogSink) Enabled(level int) bool {
+	return level <= s.verbosity
+}
+
+// Info forwards the message with its original level. A wrapped LogSink filtering again on
+// Info (as klog does using the global verbosity) drops messages above its own verbosity.
+func (s *featureLogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *featureLogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *featureLogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &featureLogSink{sink: s.sink.WithValues(keysAndValues...), verbosity: s.verbosity}
+}
+
+func (s *featureLogSink) WithName(name string) logr.LogSink {
+	return &featureLogSink{sink: s.sink.WithName(name), verbosity: s.verbosity}
+}
+
+func (s *featureLogSink) WithCallDepth(depth int) logr.LogSink {
+	if cd, ok := s.sink.(logr.CallDepthLogSink); ok {
+		return &featureLogSink{sink: cd.WithCallDepth(depth), verbosity: s.verbosity}
+	}
+	return s
+}
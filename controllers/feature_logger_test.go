@@ -0,0 +1,121 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+)
+
+// recordingLogSink is a logr.LogSink enabling levels up to verbosity
+// and recording the level of each message it is asked to log
+type recordingLogSink struct {
+	verbosity *int
+	levels    *[]int
+	values    []interface{}
+}
+
+func (s *recordingLogSink) Init(_ logr.RuntimeInfo) {
+}
+
+func (s *recordingLogSink) Enabled(level int) bool {
+	return level <= *s.verbosity
+}
+
+func (s *recordingLogSink) Info(level int, _ string, _ ...interface{}) {
+	*s.levels = append(*s.levels, level)
+}
+
+func (s *recordingLogSink) Error(_ error, _ string, _ ...interface{}) {
+}
+
+func (s *recordingLogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	values := append([]interface{}{}, s.values...)
+	return &recordingLogSink{verbosity: s.verbosity, levels: s.levels, values: append(values, keysAndValues...)}
+}
+
+func (s *recordingLogSink) WithName(_ string) logr.LogSink {
+	return s
+}
+
+var _ = Describe("Feature logger", func() {
+	var globalVerbosity int
+	var levels []int
+	var logger logr.Logger
+
+	BeforeEach(func() {
+		globalVerbosity = 0
+		levels = make([]int, 0)
+		logger = logr.New(&recordingLogSink{verbosity: &globalVerbosity, levels: &levels})
+	})
+
+	AfterEach(func() {
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{})).To(Succeed())
+	})
+
+	It("SetFeatureLogVerbosity accepts only known features", func() {
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{
+			string(configv1beta1.FeatureHelm):      5,
+			string(configv1beta1.FeatureResources): 1,
+			string(configv1beta1.FeatureKustomize): 0,
+		})).To(Succeed())
+
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{"Unknown": 5})).ToNot(Succeed())
+	})
+
+	It("getFeatureLogger uses the global verbosity when feature has no verbosity set", func() {
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{string(configv1beta1.FeatureHelm): 5})).To(Succeed())
+
+		featureLogger := controllers.GetFeatureLogger(logger, randomString(), randomString(), randomString(),
+			string(configv1beta1.FeatureResources))
+		featureLogger.V(5).Info("debug")
+		featureLogger.Info("info")
+		Expect(levels).To(Equal([]int{0}))
+
+		sink, ok := featureLogger.GetSink().(*recordingLogSink)
+		Expect(ok).To(BeTrue())
+		Expect(sink.values).To(ContainElements("clusternamespace", "clustername", "clustersummary", "feature",
+			string(configv1beta1.FeatureResources)))
+	})
+
+	It("getFeatureLogger logs messages enabled by the feature verbosity with their original level", func() {
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{string(configv1beta1.FeatureHelm): 5})).To(Succeed())
+
+		featureLogger := controllers.GetFeatureLogger(logger, randomString(), randomString(), randomString(),
+			string(configv1beta1.FeatureHelm))
+		featureLogger.V(5).Info("debug")
+		featureLogger.V(10).Info("verbose")
+		Expect(levels).To(Equal([]int{5}))
+	})
+
+	It("getFeatureLogger ignores the global verbosity when feature verbosity is set", func() {
+		Expect(controllers.SetFeatureLogVerbosity(map[string]int{string(configv1beta1.FeatureHelm): 1})).To(Succeed())
+
+		featureLogger := controllers.GetFeatureLogger(logger, randomString(), randomString(), randomString(),
+			string(configv1beta1.FeatureHelm))
+
+		// As logsettings does when DebuggingConfiguration sets debug level
+		globalVerbosity = 5
+		featureLogger.V(5).Info("debug")
+		featureLogger.V(1).Info("info")
+		Expect(levels).To(Equal([]int{1}))
+	})
+})
@@ -26,7 +26,9 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -89,6 +91,8 @@ const (
 	notInstalledMessage        = "Not installed yet and action is uninstall"
 	defaultMaxHistory          = 2
 	defaultDeletionPropagation = "background"
+	// deleteCRDsReleaseLabel is set on helm releases whose chart CRDs must be removed on uninstall
+	deleteCRDsReleaseLabel = "projectsveltos.io/delete-crds"
 )
 
 type registryClientOptions struct {
@@ -96,6 +100,9 @@ type registryClientOptions struct {
 	caPath          string
 	skipTLSVerify   bool
 	plainHTTP       bool
+	// username and password are used for classic (non OCI) helm repositories
+	username string
+	password string
 }
 
 type releaseInfo struct {
@@ -232,6 +239,11 @@ func undeployHelmCharts(ctx context.Context, c client.Client,
 
 	logger.V(logs.LogDebug).Info("undeployHelmCharts")
 
+	if isManagementClusterOnly(o) {
+		logger.V(logs.LogDebug).Info("managed cluster is being deleted. Skip uninstalling helm charts")
+		return cleanHelmChartsManagementState(ctx, c, clusterSummary, nil, logger)
+	}
+
 	kubeconfigContent, err := clusterproxy.GetSecretData(ctx, c, clusterNamespace, clusterName,
 		adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
 	if err != nil {
@@ -265,6 +277,14 @@ func undeployHelmChartResources(ctx context.Context, c client.Client, clusterSum
 	}
 	releaseReports = append(releaseReports, undeployedReports...)
 
+	return cleanHelmChartsManagementState(ctx, c, clusterSummary, releaseReports, logger)
+}
+
+// cleanHelmChartsManagementState cleans, in the management cluster, all the state about the helm
+// releases managed by this clusterSummary
+func cleanHelmChartsManagementState(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
+	releaseReports []configv1beta1.ReleaseReport, logger logr.Logger) error {
+
 	profileOwnerRef, err := configv1beta1.GetProfileOwnerReference(clusterSummary)
 	if err != nil {
 		return err
@@ -398,44 +418,40 @@ func helmHash(ctx context.Context, c client.Client, clusterSummaryScope *scope.C
 	}
 
 	h := sha256.New()
-	var config string
-	config += string(clusterProfileSpecHash)
-
 	clusterSummary := clusterSummaryScope.ClusterSummary
 	if clusterSummary.Spec.ClusterProfileSpec.HelmCharts == nil {
 		return h.Sum(nil), nil
 	}
+
+	h.Write(clusterProfileSpecHash)
 	for i := range clusterSummary.Spec.ClusterProfileSpec.HelmCharts {
 		currentChart := &clusterSummary.Spec.ClusterProfileSpec.HelmCharts[i]
 
-		config += render.AsCode(*currentChart)
+		fmt.Fprint(h, render.AsCode(*currentChart))
 
-		valueFromHash, err := getHelmReferenceResourceHash(ctx, c, clusterSummaryScope.ClusterSummary,
+		err = writeHelmReferenceResourceHash(ctx, c, h, clusterSummaryScope.ClusterSummary,
 			currentChart, logger)
 		if err != nil {
 			logger.V(logs.LogInfo).Info(
 				fmt.Sprintf("failed to get hash from referenced ConfigMap/Secret in ValuesFrom %v", err))
 			return nil, err
 		}
-
-		config += valueFromHash
 	}
 
 	for i := range clusterSummary.Spec.ClusterProfileSpec.ValidateHealths {
-		h := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
-		if h.FeatureID == configv1beta1.FeatureHelm {
-			config += render.AsCode(h)
+		vh := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
+		if vh.FeatureID == configv1beta1.FeatureHelm {
+			fmt.Fprint(h, render.AsCode(vh))
 		}
 	}
 
-	h.Write([]byte(config))
 	return h.Sum(nil), nil
 }
 
-func getHelmReferenceResourceHash(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
-	helmChart *configv1beta1.HelmChart, logger logr.Logger) (string, error) {
+func writeHelmReferenceResourceHash(ctx context.Context, c client.Client, w io.Writer,
+	clusterSummary *configv1beta1.ClusterSummary, helmChart *configv1beta1.HelmChart, logger logr.Logger) error {
 
-	return getValuesFromResourceHash(ctx, c, clusterSummary, helmChart.ValuesFrom, logger)
+	return writeValuesFromResourceHash(ctx, c, w, clusterSummary, helmChart.ValuesFrom, logger)
 }
 
 func getHelmRefs(clusterSummary *configv1beta1.ClusterSummary) []configv1beta1.PolicyRef {
@@ -814,12 +830,20 @@ func handleChart(ctx context.Context, clusterSummary *configv1beta1.ClusterSumma
 	logger = logger.WithValues("releaseNamespace", currentChart.ReleaseNamespace, "releaseName",
 		currentChart.ReleaseName, "version", currentChart.ChartVersion)
 
-	if registryOptions.credentialsPath != "" {
+	if currentChart.RegistryCredentialsConfig != nil &&
+		currentChart.RegistryCredentialsConfig.CredentialsSecretRef != nil {
+
 		credentialSecretNamespace := libsveltostemplate.GetReferenceResourceNamespace(clusterSummary.Spec.ClusterNamespace,
 			currentChart.RegistryCredentialsConfig.CredentialsSecretRef.Namespace)
-		err = doLogin(ctx, getManagementClusterClient(), registryOptions, currentChart.ReleaseNamespace,
-			credentialSecretNamespace, currentChart.RegistryCredentialsConfig.CredentialsSecretRef.Name,
-			currentChart.RepositoryURL)
+		if registry.IsOCI(currentChart.RepositoryURL) {
+			err = doLogin(ctx, getManagementClusterClient(), registryOptions, currentChart.ReleaseNamespace,
+				credentialSecretNamespace, currentChart.RegistryCredentialsConfig.CredentialsSecretRef.Name,
+				currentChart.RepositoryURL)
+		} else {
+			registryOptions.username, registryOptions.password, err = getRepositoryCredentials(ctx,
+				getManagementClusterClient(), credentialSecretNamespace,
+				currentChart.RegistryCredentialsConfig.CredentialsSecretRef.Name, currentChart.RepositoryURL)
+		}
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to login %v", err))
 			return nil, nil, err
@@ -877,10 +901,20 @@ func handleChart(ctx context.Context, clusterSummary *configv1beta1.ClusterSumma
 	return currentRelease, report, nil
 }
 
-// repoAddOrUpdate adds/updates repo with given name and url
-func repoAddOrUpdate(settings *cli.EnvSettings, name, repoURL string, logger logr.Logger) error {
+// repoAddOrUpdate adds/updates repo with given name and url.
+// Repositories file is shared by all clusters, so it never contains credentials or TLS settings.
+// Classic (non OCI) repositories needing those are not added: charts are pulled passing credentials
+// and TLS settings of the request (see setRepositoryCredentials).
+func repoAddOrUpdate(settings *cli.EnvSettings, name, repoURL string, registryOptions *registryClientOptions,
+	logger logr.Logger) error {
+
 	logger = logger.WithValues("repoURL", repoURL, "repoName", name)
 
+	if requiresRepositoryCredentials(repoURL, registryOptions) {
+		logger.V(logs.LogDebug).Info("repository requires credentials. Not added to repositories file")
+		return nil
+	}
+
 	entry := &repo.Entry{Name: name, URL: repoURL}
 	chartRepo, err := repo.NewChartRepository(entry, getter.All(settings))
 	if err != nil {
@@ -889,7 +923,7 @@ func repoAddOrUpdate(settings *cli.EnvSettings, name, repoURL string, logger log
 
 	chartRepo.CachePath = settings.RepositoryCache
 
-	if storage.Has(entry.Name) {
+	if storage.Has(entry.Name) && reflect.DeepEqual(storage.Get(entry.Name), entry) {
 		logger.V(logs.LogDebug).Info("repository name already exists")
 		return nil
 	}
@@ -912,6 +946,37 @@ func repoAddOrUpdate(settings *cli.EnvSettings, name, repoURL string, logger log
 	return nil
 }
 
+// requiresRepositoryCredentials returns true if repoURL is a classic (non OCI) helm repository
+// accessed with credentials or TLS settings
+func requiresRepositoryCredentials(repoURL string, registryOptions *registryClientOptions) bool {
+	if registry.IsOCI(repoURL) || registryOptions == nil {
+		return false
+	}
+
+	return registryOptions.username != "" || registryOptions.password != "" ||
+		registryOptions.caPath != "" || registryOptions.skipTLSVerify
+}
+
+// setRepositoryCredentials configures chartPathOptions so that a chart from a classic helm repository
+// needing credentials or TLS settings is pulled directly from repoURL using those. It returns the name
+// of the chart to locate.
+func setRepositoryCredentials(chartPathOptions *action.ChartPathOptions, requestedChart *configv1beta1.HelmChart,
+	chartName, repoURL string, registryOptions *registryClientOptions) string {
+
+	if !requiresRepositoryCredentials(repoURL, registryOptions) {
+		return chartName
+	}
+
+	chartPathOptions.RepoURL = repoURL
+	chartPathOptions.Username = registryOptions.username
+	chartPathOptions.Password = registryOptions.password
+	chartPathOptions.CaFile = registryOptions.caPath
+	chartPathOptions.InsecureSkipTLSverify = registryOptions.skipTLSVerify
+
+	// Chart is located in repoURL, so repository name must not be part of the chart name
+	return strings.TrimPrefix(chartName, requestedChart.RepositoryName+"/")
+}
+
 // installRelease installs helm release in the CAPI cluster.
 // No action in DryRun mode.
 func installRelease(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary, settings *cli.EnvSettings,
@@ -946,6 +1011,8 @@ func installRelease(ctx context.Context, clusterSummary *configv1beta1.ClusterSu
 		return nil, err
 	}
 
+	chartName = setRepositoryCredentials(&installClient.ChartPathOptions, requestedChart, chartName, repoURL,
+		registryOptions)
 	cp, err := installClient.ChartPathOptions.LocateChart(chartName, settings)
 	if err != nil {
 		logger.V(logs.LogDebug).Info("LocateChart failed")
@@ -1059,16 +1126,70 @@ func uninstallRelease(ctx context.Context, clusterSummary *configv1beta1.Cluster
 		return err
 	}
 
+	var crds []chart.CRD
+	if helmChart != nil && getDeleteCRDsValue(helmChart.Options) {
+		crds, err = getReleaseCRDs(actionConfig, releaseName)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = uninstallClient.Run(releaseName)
 	if err != nil {
 		return err
 	}
 
+	if err := deleteCRDs(actionConfig, crds, logger); err != nil {
+		return err
+	}
+
 	logger.V(logs.LogDebug).Info("uninstalling release done")
 
 	return nil
 }
 
+// getReleaseCRDs returns the CRDs contained in the crds/ directory of the chart
+// used to install the release
+func getReleaseCRDs(actionConfig *action.Configuration, releaseName string) ([]chart.CRD, error) {
+	rel, err := action.NewGet(actionConfig).Run(releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if rel.Chart == nil {
+		return nil, nil
+	}
+
+	return rel.Chart.CRDObjects(), nil
+}
+
+// deleteCRDs removes the CRDs from the managed cluster
+func deleteCRDs(actionConfig *action.Configuration, crds []chart.CRD, logger logr.Logger) error {
+	for i := range crds {
+		if crds[i].File == nil {
+			continue
+		}
+
+		resources, err := actionConfig.KubeClient.Build(bytes.NewBuffer(crds[i].File.Data), false)
+		if err != nil {
+			return err
+		}
+
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("deleting CRDs in %s", crds[i].Filename))
+		_, errs := actionConfig.KubeClient.Delete(resources)
+		for j := range errs {
+			if !apierrors.IsNotFound(errs[j]) {
+				return errs[j]
+			}
+		}
+	}
+
+	return nil
+}
+
 // upgradeRelease upgrades helm release in managed cluster.
 // No action in DryRun mode.
 func upgradeRelease(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
@@ -1118,6 +1239,8 @@ func upgradeRelease(ctx context.Context, clusterSummary *configv1beta1.ClusterSu
 		return err
 	}
 
+	chartName = setRepositoryCredentials(&upgradeClient.ChartPathOptions, requestedChart, chartName, repoURL,
+		registryOptions)
 	cp, err := upgradeClient.ChartPathOptions.LocateChart(chartName, settings)
 	if err != nil {
 		return err
@@ -1443,7 +1566,7 @@ func doInstallRelease(ctx context.Context, clusterSummary *configv1beta1.Cluster
 	settings := getSettings(requestedChart.ReleaseNamespace, registryOptions)
 
 	err := repoAddOrUpdate(settings, requestedChart.RepositoryName,
-		requestedChart.RepositoryURL, logger)
+		requestedChart.RepositoryURL, registryOptions, logger)
 	if err != nil {
 		return err
 	}
@@ -1502,7 +1625,7 @@ func doUpgradeRelease(ctx context.Context, clusterSummary *configv1beta1.Cluster
 	settings := getSettings(requestedChart.ReleaseNamespace, registryOptions)
 
 	err := repoAddOrUpdate(settings, requestedChart.RepositoryName,
-		requestedChart.RepositoryURL, logger)
+		requestedChart.RepositoryURL, registryOptions, logger)
 	if err != nil {
 		return err
 	}
@@ -1568,7 +1691,7 @@ func undeployStaleReleases(ctx context.Context, c client.Client, clusterSummary
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("helm release %s (namespace %s) used to be managed but not referenced anymore",
 				managedHelmReleases[i].Name, managedHelmReleases[i].Namespace))
 
-			_, err := getReleaseInfo(managedHelmReleases[i].Name,
+			currentRelease, err := getReleaseInfo(managedHelmReleases[i].Name,
 				managedHelmReleases[i].Namespace, kubeconfig, &registryClientOptions{}, false)
 			if err != nil {
 				if errors.Is(err, driver.ErrReleaseNotFound) {
@@ -1578,8 +1701,8 @@ func undeployStaleReleases(ctx context.Context, c client.Client, clusterSummary
 			}
 
 			if err := uninstallRelease(ctx, clusterSummary, managedHelmReleases[i].Name,
-				managedHelmReleases[i].Namespace, kubeconfig, &registryClientOptions{}, nil,
-				logger); err != nil {
+				managedHelmReleases[i].Namespace, kubeconfig, &registryClientOptions{},
+				getStaleReleaseChart(currentRelease), logger); err != nil {
 				return nil, err
 			}
 
@@ -1833,6 +1956,10 @@ func updateClusterReportWithHelmReports(ctx context.Context, c client.Client,
 	return err
 }
 
+// getInstantiatedValues returns the values for requestedChart. Values are merged in order:
+// first the chart Values, then the content of each ValuesFrom entry, in the order those
+// are listed (keys within a ConfigMap/Secret are considered in alphabetical order).
+// A later entry overrides values set by an earlier one, merging nested maps.
 func getInstantiatedValues(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
 	mgmtResources map[string]*unstructured.Unstructured, requestedChart *configv1beta1.HelmChart,
 	logger logr.Logger) (chartutil.Values, error) {
@@ -1844,36 +1971,69 @@ func getInstantiatedValues(ctx context.Context, clusterSummary *configv1beta1.Cl
 		return nil, err
 	}
 
-	c := getManagementClusterClient()
-	templatedValuesFrom, valuesFrom, err := getHelmChartValuesFrom(ctx, c, clusterSummary, requestedChart, logger)
+	values, err := chartutil.ReadValues([]byte(instantiatedValues))
 	if err != nil {
 		return nil, err
 	}
 
-	for k := range templatedValuesFrom {
-		instantiatedValuesFrom, err := instantiateTemplateValues(ctx, getManagementClusterConfig(), getManagementClusterClient(),
-			clusterSummary.Spec.ClusterType, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
-			requestedChart.ChartName, templatedValuesFrom[k], mgmtResources, logger)
+	c := getManagementClusterClient()
+	for i := range requestedChart.ValuesFrom {
+		templatedValuesFrom, valuesFrom, err := getHelmChartValuesFrom(ctx, c, clusterSummary,
+			&requestedChart.ValuesFrom[i], logger)
 		if err != nil {
 			return nil, err
 		}
-		instantiatedValues += fmt.Sprintf("\n\n%s", instantiatedValuesFrom)
+
+		for _, k := range getSortedKeys(templatedValuesFrom) {
+			instantiatedValuesFrom, err := instantiateTemplateValues(ctx, getManagementClusterConfig(), getManagementClusterClient(),
+				clusterSummary.Spec.ClusterType, clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
+				requestedChart.ChartName, templatedValuesFrom[k], mgmtResources, logger)
+			if err != nil {
+				return nil, err
+			}
+			values, err = mergeValues(values, instantiatedValuesFrom)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, k := range getSortedKeys(valuesFrom) {
+			values, err = mergeValues(values, valuesFrom[k])
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	for k := range valuesFrom {
-		instantiatedValues += fmt.Sprintf("\n\n%s", valuesFrom[k])
+	logger.V(logs.LogDebug).Info(fmt.Sprintf("Deploying helm charts with Values %v", values))
+
+	return values, nil
+}
+
+// mergeValues merges values contained in text into current. Values in text take precedence.
+func mergeValues(current chartutil.Values, text string) (chartutil.Values, error) {
+	values, err := chartutil.ReadValues([]byte(text))
+	if err != nil {
+		return nil, err
 	}
 
-	logger.V(logs.LogDebug).Info(fmt.Sprintf("Deploying helm charts with Values %q", instantiatedValues))
+	return chartutil.CoalesceTables(values, current), nil
+}
 
-	return chartutil.ReadValues([]byte(instantiatedValues))
+func getSortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // getHelmChartValuesFrom return key-value pair from referenced ConfigMap/Secret
 func getHelmChartValuesFrom(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
-	helmChart *configv1beta1.HelmChart, logger logr.Logger) (templatedValues, nonTemplatedValues map[string]string, err error) {
+	valueFrom *configv1beta1.ValueFrom, logger logr.Logger) (templatedValues, nonTemplatedValues map[string]string, err error) {
 
-	return getValuesFrom(ctx, c, clusterSummary, helmChart.ValuesFrom, false, logger)
+	return getValuesFrom(ctx, c, clusterSummary, []configv1beta1.ValueFrom{*valueFrom}, false, logger)
 }
 
 // collectResourcesFromManagedHelmChartsForDriftDetection collects resources considering all
@@ -2121,6 +2281,41 @@ func getLabelsValue(options *configv1beta1.HelmOptions) map[string]string {
 	return map[string]string{}
 }
 
+// getReleaseLabels returns the labels to set on the helm release. On top of the labels
+// requested in the options, the release records whether chart CRDs must be removed on
+// uninstall, so the option is still honored once the chart is not referenced anymore.
+// On upgrade a "null" value makes helm remove the label from the release.
+func getReleaseLabels(options *configv1beta1.HelmOptions, isUpgrade bool) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range getLabelsValue(options) {
+		labels[k] = v
+	}
+
+	if getDeleteCRDsValue(options) {
+		labels[deleteCRDsReleaseLabel] = "true"
+	} else if isUpgrade {
+		labels[deleteCRDsReleaseLabel] = "null"
+	}
+
+	return labels
+}
+
+// getStaleReleaseChart returns the HelmChart to use when uninstalling a release not
+// referenced anymore. Only the options recorded on the release are known at this point.
+func getStaleReleaseChart(currentRelease *releaseInfo) *configv1beta1.HelmChart {
+	if currentRelease.ReleaseLabels[deleteCRDsReleaseLabel] != "true" {
+		return nil
+	}
+
+	return &configv1beta1.HelmChart{
+		ReleaseName:      currentRelease.ReleaseName,
+		ReleaseNamespace: currentRelease.ReleaseNamespace,
+		Options: &configv1beta1.HelmOptions{
+			UninstallOptions: configv1beta1.HelmUninstallOptions{DeleteCRDs: true},
+		},
+	}
+}
+
 func getReplaceValue(options *configv1beta1.HelmOptions) bool {
 	if options != nil {
 		return options.InstallOptions.Replace
@@ -2179,6 +2374,14 @@ func getKeepHistoryValue(options *configv1beta1.HelmOptions) bool {
 	return false
 }
 
+func getDeleteCRDsValue(options *configv1beta1.HelmOptions) bool {
+	if options != nil {
+		return options.UninstallOptions.DeleteCRDs
+	}
+
+	return false
+}
+
 func getDeletionPropagation(options *configv1beta1.HelmOptions) string {
 	if options != nil {
 		return options.UninstallOptions.DeletionPropagation
@@ -2248,7 +2451,7 @@ func getHelmInstallClient(requestedChart *configv1beta1.HelmChart, kubeconfig st
 	}
 	installClient.SkipSchemaValidation = getSkipSchemaValidation(requestedChart.Options)
 	installClient.Replace = getReplaceValue(requestedChart.Options)
-	installClient.Labels = getLabelsValue(requestedChart.Options)
+	installClient.Labels = getReleaseLabels(requestedChart.Options, false)
 	installClient.Description = getDescriptionValue(requestedChart.Options)
 	if actionConfig.RegistryClient != nil {
 		installClient.SetRegistryClient(actionConfig.RegistryClient)
@@ -2286,7 +2489,7 @@ func getHelmUpgradeClient(requestedChart *configv1beta1.HelmChart, actionConfig
 	upgradeClient.ReuseValues = getReuseValues(requestedChart.Options)
 	upgradeClient.ResetThenReuseValues = getResetThenReuseValues(requestedChart.Options)
 	upgradeClient.Force = getForceValue(requestedChart.Options)
-	upgradeClient.Labels = getLabelsValue(requestedChart.Options)
+	upgradeClient.Labels = getReleaseLabels(requestedChart.Options, true)
 	upgradeClient.Description = getDescriptionValue(requestedChart.Options)
 	upgradeClient.MaxHistory = getMaxHistoryValue(requestedChart.Options)
 	upgradeClient.CleanupOnFail = getCleanupOnFailValue(requestedChart.Options)
@@ -2412,15 +2615,13 @@ func getResourceNamespace(r *unstructured.Unstructured, releaseNamespace string,
 func getHelmChartValuesHash(ctx context.Context, c client.Client, requestedChart *configv1beta1.HelmChart,
 	clusterSummary *configv1beta1.ClusterSummary, logger logr.Logger) ([]byte, error) {
 
-	valuesFromHash, err := getHelmReferenceResourceHash(ctx, c, clusterSummary, requestedChart, logger)
+	h := sha256.New()
+	fmt.Fprint(h, render.AsCode(requestedChart.Values))
+	err := writeHelmReferenceResourceHash(ctx, c, h, clusterSummary, requestedChart, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	h := sha256.New()
-	config := render.AsCode(requestedChart.Values)
-	config += valuesFromHash
-	h.Write([]byte(config))
 	return h.Sum(nil), nil
 }
 
@@ -2500,6 +2701,13 @@ func createFileWithCredentials(ctx context.Context, c client.Client, clusterName
 
 		return "", nil
 	}
+
+	// Registry configuration file is only used for OCI registries. Credentials for classic
+	// helm repositories are set on the repository entry (see repoAddOrUpdate)
+	if !registry.IsOCI(requestedChart.RepositoryURL) {
+		return "", nil
+	}
+
 	credSecretRef := requestedChart.RegistryCredentialsConfig.CredentialsSecretRef
 	namespace := libsveltostemplate.GetReferenceResourceNamespace(
 		clusterNamespace, requestedChart.RegistryCredentialsConfig.CredentialsSecretRef.Namespace)
@@ -2696,6 +2904,26 @@ func getUsernameAndPasswordFromSecret(registryURL string, secret *corev1.Secret)
 	return username, password, parsedURL.Host, nil
 }
 
+// getRepositoryCredentials returns username and password, stored in the referenced Secret,
+// to access a classic (non OCI) helm repository
+func getRepositoryCredentials(ctx context.Context, c client.Client, secretNamespace, secretName,
+	repositoryURL string) (username, password string, err error) {
+
+	secret := &corev1.Secret{}
+	err = c.Get(ctx,
+		types.NamespacedName{
+			Namespace: secretNamespace,
+			Name:      secretName,
+		},
+		secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	username, password, _, err = getUsernameAndPasswordFromSecret(repositoryURL, secret)
+	return username, password, err
+}
+
 func requeueAllOtherClusterSummaries(ctx context.Context, c client.Client,
 	namespace string, clusterSummaryNames []string, logger logr.Logger) error {
 
@@ -2729,7 +2957,7 @@ func evaluateValuesDiff(ctx context.Context, currentValues map[string]interface{
 	settings := getSettings(requestedChart.ReleaseNamespace, registryOptions)
 
 	err := repoAddOrUpdate(settings, requestedChart.RepositoryName,
-		requestedChart.RepositoryURL, logger)
+		requestedChart.RepositoryURL, registryOptions, logger)
 	if err != nil {
 		return "", err
 	}
@@ -24,11 +24,13 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/gdexlab/go-render/render"
+	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -514,6 +516,76 @@ var _ = Describe("HandlersHelm", func() {
 		Expect(instaniatedChart.ChartVersion).To(Equal("25.0.2"))
 	})
 
+	It("getInstantiatedValues merges Values and ValuesFrom with later entries taking precedence", func() {
+		clusterSummary.Namespace = defaulNamespace
+		clusterSummary.Spec.ClusterNamespace = defaulNamespace
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterSummary.Spec.ClusterName,
+				Namespace: clusterSummary.Spec.ClusterNamespace,
+			},
+		}
+
+		Expect(testEnv.Create(context.TODO(), cluster)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, cluster)).To(Succeed())
+
+		common := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaulNamespace,
+				Name:      randomString(),
+			},
+			Data: map[string]string{
+				"values": `controller:
+  replicas: 2
+  logLevel: info`,
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), common)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, common)).To(Succeed())
+
+		override := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaulNamespace,
+				Name:      randomString(),
+				Annotations: map[string]string{
+					libsveltosv1beta1.PolicyTemplateAnnotation: "ok",
+				},
+			},
+			Data: map[string]string{
+				"values": `controller:
+  replicas: 3
+  cluster: {{ .Cluster.metadata.name }}`,
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), override)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, override)).To(Succeed())
+
+		helmChart := &configv1beta1.HelmChart{
+			ReleaseName: randomString(), ReleaseNamespace: randomString(),
+			ChartName: randomString(), ChartVersion: randomString(),
+			RepositoryURL: randomString(), RepositoryName: randomString(),
+			HelmChartAction: configv1beta1.HelmChartActionInstall,
+			Values: `controller:
+  replicas: 1
+  service: NodePort`,
+			ValuesFrom: []configv1beta1.ValueFrom{
+				{Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind), Namespace: common.Namespace, Name: common.Name},
+				{Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind), Namespace: override.Namespace, Name: override.Name},
+			},
+		}
+
+		values, err := controllers.GetInstantiatedValues(context.TODO(), clusterSummary, nil, helmChart,
+			textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).To(BeNil())
+		controller, err := values.Table("controller")
+		Expect(err).To(BeNil())
+		Expect(controller["replicas"]).To(BeEquivalentTo(3))
+		Expect(controller["logLevel"]).To(Equal("info"))
+		Expect(controller["service"]).To(Equal("NodePort"))
+		Expect(controller["cluster"]).To(Equal(cluster.Name))
+	})
+
 	It("updateClusterReportWithHelmReports updates ClusterReports with HelmReports", func() {
 		helmChart := &configv1beta1.HelmChart{
 			ReleaseName: randomString(), ReleaseNamespace: randomString(),
@@ -772,7 +844,7 @@ var _ = Describe("Hash methods", func() {
 		Expect(reflect.DeepEqual(hash, expectHash)).To(BeTrue())
 	})
 
-	It(`getHelmReferenceResourceHash returns the hash considering all referenced 
+	It(`writeHelmReferenceResourceHash writes the hash considering all referenced 
 	ConfigMap/Secret in the ValueFrom section`, func() {
 		namespace := randomString()
 
@@ -801,7 +873,11 @@ var _ = Describe("Hash methods", func() {
 		}
 
 		var expectedHash string
+		expectedHash += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			configMap.Namespace, configMap.Name)
 		expectedHash += controllers.GetStringDataSectionHash(configMap.Data)
+		expectedHash += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.SecretReferencedResourceKind),
+			secret.Namespace, secret.Name)
 		expectedHash += controllers.GetByteDataSectionHash(secret.Data)
 
 		helmChart := configv1beta1.HelmChart{
@@ -842,10 +918,11 @@ var _ = Describe("Hash methods", func() {
 		}
 		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
 
-		hash, err := controllers.GetHelmReferenceResourceHash(context.TODO(), c, clusterSummary,
+		var hash strings.Builder
+		err := controllers.WriteHelmReferenceResourceHash(context.TODO(), c, &hash, clusterSummary,
 			&helmChart, textlogger.NewLogger(textlogger.NewConfig()))
 		Expect(err).To(BeNil())
-		Expect(expectedHash).To(Equal(hash))
+		Expect(expectedHash).To(Equal(hash.String()))
 	})
 
 	It("getHelmChartValuesHash returns hash considering Values and ValuesFrom", func() {
@@ -889,7 +966,9 @@ var _ = Describe("Hash methods", func() {
 
 		h := sha256.New()
 		expectedHash := render.AsCode(requestedChart.Values)
-		expectedHash += render.AsCode(configMap.Data[key])
+		expectedHash += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			configMap.Namespace, configMap.Name)
+		expectedHash += controllers.GetStringDataSectionHash(configMap.Data)
 		h.Write([]byte(expectedHash))
 
 		initObjects := []client.Object{
@@ -947,6 +1026,7 @@ var _ = Describe("Hash methods", func() {
 		}
 
 		requestedChart := configv1beta1.HelmChart{
+			RepositoryURL: "oci://registry-1.docker.io/bitnamicharts",
 			RegistryCredentialsConfig: &configv1beta1.RegistryCredentialsConfig{
 				CredentialsSecretRef: &corev1.SecretReference{
 					Namespace: secretCredentials.Namespace,
@@ -971,6 +1051,96 @@ var _ = Describe("Hash methods", func() {
 		Expect(caPath).ToNot(BeEmpty())
 		verifyFileContent(caPath, caByte)
 		Expect(os.Remove(caPath)).To(Succeed())
+
+		// Registry configuration file is not created for classic helm repositories
+		requestedChart.RepositoryURL = "https://charts.example.com"
+		credentialsPath, caPath, err = controllers.GetCredentialsAndCAFiles(context.TODO(), c,
+			randomString(), &requestedChart)
+		Expect(err).To(BeNil())
+		Expect(credentialsPath).To(BeEmpty())
+		Expect(caPath).ToNot(BeEmpty())
+		Expect(os.Remove(caPath)).To(Succeed())
+	})
+
+	It("getRepositoryCredentials returns username and password for classic helm repositories", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+		username, password, err := controllers.GetRepositoryCredentials(context.TODO(), c,
+			secret.Namespace, secret.Name, "https://charts.example.com")
+		Expect(err).To(BeNil())
+		Expect(username).To(Equal("user"))
+		Expect(password).To(Equal("pass"))
+
+		_, _, err = controllers.GetRepositoryCredentials(context.TODO(), c,
+			secret.Namespace, randomString(), "https://charts.example.com")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("setRepositoryCredentials pulls charts with per request credentials from classic helm repositories", func() {
+		requestedChart := &configv1beta1.HelmChart{
+			RepositoryName: "private",
+			RepositoryURL:  "https://charts.example.com",
+			ChartName:      "private/nginx",
+		}
+
+		// No credentials: chart is located via repositories file
+		chartPathOptions := &action.ChartPathOptions{}
+		chartName := controllers.SetRepositoryCredentials(chartPathOptions, requestedChart,
+			requestedChart.ChartName, requestedChart.RepositoryURL, controllers.NewRegistryClientOptions("", "", "", false))
+		Expect(chartName).To(Equal(requestedChart.ChartName))
+		Expect(chartPathOptions.RepoURL).To(BeEmpty())
+
+		// Credentials: chart is located directly in the repository using those
+		chartPathOptions = &action.ChartPathOptions{}
+		chartName = controllers.SetRepositoryCredentials(chartPathOptions, requestedChart,
+			requestedChart.ChartName, requestedChart.RepositoryURL,
+			controllers.NewRegistryClientOptions("user", "pass", "/tmp/ca.crt", true))
+		Expect(chartName).To(Equal("nginx"))
+		Expect(chartPathOptions.RepoURL).To(Equal(requestedChart.RepositoryURL))
+		Expect(chartPathOptions.Username).To(Equal("user"))
+		Expect(chartPathOptions.Password).To(Equal("pass"))
+		Expect(chartPathOptions.CaFile).To(Equal("/tmp/ca.crt"))
+		Expect(chartPathOptions.InsecureSkipTLSverify).To(BeTrue())
+
+		// OCI registries are not affected
+		chartPathOptions = &action.ChartPathOptions{}
+		chartName = controllers.SetRepositoryCredentials(chartPathOptions, requestedChart,
+			"oci://registry-1.docker.io/bitnamicharts/nginx", "oci://registry-1.docker.io/bitnamicharts",
+			controllers.NewRegistryClientOptions("user", "pass", "", false))
+		Expect(chartName).To(Equal("oci://registry-1.docker.io/bitnamicharts/nginx"))
+		Expect(chartPathOptions.RepoURL).To(BeEmpty())
+	})
+
+	It("getReleaseLabels records on the helm release whether CRDs must be deleted on uninstall", func() {
+		options := &configv1beta1.HelmOptions{
+			Labels: map[string]string{randomString(): randomString()},
+		}
+
+		labels := controllers.GetReleaseLabels(options, false)
+		Expect(labels).To(Equal(options.Labels))
+
+		// On upgrade the label is explicitly removed from the release
+		labels = controllers.GetReleaseLabels(options, true)
+		Expect(labels).To(HaveLen(2))
+		Expect(labels).To(HaveKeyWithValue("projectsveltos.io/delete-crds", "null"))
+
+		options.UninstallOptions.DeleteCRDs = true
+		labels = controllers.GetReleaseLabels(options, false)
+		Expect(labels).To(HaveLen(2))
+		Expect(labels).To(HaveKeyWithValue("projectsveltos.io/delete-crds", "true"))
+		// Options are not modified
+		Expect(options.Labels).To(HaveLen(1))
 	})
 })
 
@@ -47,10 +47,8 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
-	"github.com/projectsveltos/addon-controller/controllers/clustercache"
 	"github.com/projectsveltos/addon-controller/pkg/scope"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
-	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
 	"github.com/projectsveltos/libsveltos/lib/deployer"
 	"github.com/projectsveltos/libsveltos/lib/funcmap"
 	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
@@ -231,25 +229,14 @@ func undeployKustomizeRefs(ctx context.Context, c client.Client,
 		return err
 	}
 
-	cacheMgr := clustercache.GetManager()
-	remoteRestConfig, err := cacheMgr.GetKubernetesRestConfig(ctx, c, clusterNamespace, clusterName,
-		adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
-	if err != nil {
-		return err
-	}
-
-	remoteClient, err := clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName,
-		adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
-	if err != nil {
-		return err
-	}
-
 	// Undeploy from managed cluster
-	resourceReports, err = undeployStaleResources(ctx, false, remoteRestConfig, remoteClient, configv1beta1.FeatureKustomize,
-		clusterSummary, getDeployedGroupVersionKinds(clusterSummary, configv1beta1.FeatureKustomize),
-		map[string]configv1beta1.Resource{}, logger)
-	if err != nil {
-		return err
+	if isManagementClusterOnly(o) {
+		logger.V(logs.LogDebug).Info("managed cluster is being deleted. Skip undeploying from managed cluster")
+	} else {
+		resourceReports, err = undeployFromManagedCluster(ctx, c, clusterSummary, configv1beta1.FeatureKustomize, logger)
+		if err != nil {
+			return err
+		}
 	}
 
 	profileOwnerRef, err := configv1beta1.GetProfileOwnerReference(clusterSummary)
@@ -284,7 +271,7 @@ func undeployKustomizeRefs(ctx context.Context, c client.Client,
 	return nil
 }
 
-// resourcesHash returns the hash of all the ClusterSummary referenced KustomizationRefs.
+// kustomizationHash returns the hash of all the ClusterSummary referenced KustomizationRefs.
 func kustomizationHash(ctx context.Context, c client.Client, clusterSummaryScope *scope.ClusterSummaryScope,
 	logger logr.Logger) ([]byte, error) {
 
@@ -294,95 +281,100 @@ func kustomizationHash(ctx context.Context, c client.Client, clusterSummaryScope
 	}
 
 	h := sha256.New()
-	var config string
-	config += string(clusterProfileSpecHash)
+	h.Write(clusterProfileSpecHash)
+
+	if err := writeDeploymentPoliciesHash(ctx, c, h); err != nil {
+		return nil, err
+	}
 
 	clusterSummary := clusterSummaryScope.ClusterSummary
-	config += render.AsCode(clusterSummary.Spec.ClusterProfileSpec.KustomizationRefs)
+	fmt.Fprint(h, render.AsCode(clusterSummary.Spec.ClusterProfileSpec.KustomizationRefs))
 	for i := range clusterSummary.Spec.ClusterProfileSpec.KustomizationRefs {
 		kustomizationRef := &clusterSummaryScope.ClusterSummary.Spec.ClusterProfileSpec.KustomizationRefs[i]
 
-		result, err := getHashFromKustomizationRef(ctx, c, clusterSummary,
-			kustomizationRef, logger)
+		err = writeKustomizationRefHash(ctx, c, h, clusterSummary, kustomizationRef, logger)
 		if err != nil {
 			return nil, err
 		}
-		config += string(result)
 
-		valueFromHash, err := getKustomizeReferenceResourceHash(ctx, c, clusterSummary,
-			kustomizationRef, logger)
+		err = writeKustomizeReferenceResourceHash(ctx, c, h, clusterSummary, kustomizationRef, logger)
 		if err != nil {
 			logger.V(logs.LogInfo).Info(
 				fmt.Sprintf("failed to get hash from referenced ConfigMap/Secret in ValuesFrom %v", err))
 			return nil, err
 		}
-
-		config += valueFromHash
 	}
 
 	for i := range clusterSummary.Spec.ClusterProfileSpec.ValidateHealths {
-		h := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
-		if h.FeatureID == configv1beta1.FeatureKustomize {
-			config += render.AsCode(h)
+		vh := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
+		if vh.FeatureID == configv1beta1.FeatureKustomize {
+			fmt.Fprint(h, render.AsCode(vh))
 		}
 	}
 
-	h.Write([]byte(config))
 	return h.Sum(nil), nil
 }
 
-func getHashFromKustomizationRef(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
-	kustomizationRef *configv1beta1.KustomizationRef, logger logr.Logger) ([]byte, error) {
+// writeKustomizationRefHash writes the content of the resource referenced by a KustomizationRef into w.
+func writeKustomizationRefHash(ctx context.Context, c client.Client, w io.Writer,
+	clusterSummary *configv1beta1.ClusterSummary, kustomizationRef *configv1beta1.KustomizationRef,
+	logger logr.Logger) error {
 
-	var result string
 	namespace := libsveltostemplate.GetReferenceResourceNamespace(
 		clusterSummary.Namespace, kustomizationRef.Namespace)
 
 	name, err := libsveltostemplate.GetReferenceResourceName(clusterSummary.Spec.ClusterNamespace,
 		clusterSummary.Spec.ClusterName, string(clusterSummary.Spec.ClusterType), kustomizationRef.Name)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	apiVersion := getReferenceAPIVersion(kustomizationRef.Kind)
 	if kustomizationRef.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
 		configMap, err := getConfigMap(ctx, c, types.NamespacedName{Namespace: namespace, Name: name})
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				writeMissingReferenceHash(w, apiVersion, kustomizationRef.Kind, namespace, name)
+				return nil
+			}
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get ConfigMap %v", err))
-			return nil, err
-		}
-		if configMap == nil {
-			return nil, nil
+			return err
 		}
-		result += getConfigMapHash(configMap)
+		writeReferenceHashKey(w, apiVersion, kustomizationRef.Kind, namespace, name)
+		writeConfigMapHash(w, configMap)
 	} else if kustomizationRef.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
 		secret, err := getSecret(ctx, c, types.NamespacedName{Namespace: namespace, Name: name})
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				writeMissingReferenceHash(w, apiVersion, kustomizationRef.Kind, namespace, name)
+				return nil
+			}
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get Secret %v", err))
-			return nil, err
-		}
-		if secret == nil {
-			return nil, nil
+			return err
 		}
-		result += getSecretHash(secret)
+		writeReferenceHashKey(w, apiVersion, kustomizationRef.Kind, namespace, name)
+		writeSecretHash(w, secret)
 	} else {
 		source, err := getSource(ctx, c, namespace, name, kustomizationRef.Kind)
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get source %v", err))
-			return nil, err
+			return err
 		}
 		if source == nil {
-			return nil, nil
+			writeMissingReferenceHash(w, apiVersion, kustomizationRef.Kind, namespace, name)
+			return nil
 		}
+		writeReferenceHashKey(w, apiVersion, kustomizationRef.Kind, namespace, name)
 		s := source.(sourcev1.Source)
 		if s.GetArtifact() != nil {
-			result += s.GetArtifact().Revision
+			fmt.Fprint(w, s.GetArtifact().Revision)
 		}
 		if source.GetAnnotations() != nil {
-			result += getDataSectionHash(source.GetAnnotations())
+			writeDataSectionHash(w, source.GetAnnotations())
 		}
 	}
 
-	return []byte(result), nil
+	return nil
 }
 
 // instantiateKustomizeSubstituteValues gets all substitute values for a KustomizationRef and
@@ -447,10 +439,11 @@ func getKustomizeSubstituteValuesFrom(ctx context.Context, c client.Client, clus
 	return getValuesFrom(ctx, c, clusterSummary, kustomizationRef.ValuesFrom, true, logger)
 }
 
-func getKustomizeReferenceResourceHash(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
-	kustomizationRef *configv1beta1.KustomizationRef, logger logr.Logger) (string, error) {
+func writeKustomizeReferenceResourceHash(ctx context.Context, c client.Client, w io.Writer,
+	clusterSummary *configv1beta1.ClusterSummary, kustomizationRef *configv1beta1.KustomizationRef,
+	logger logr.Logger) error {
 
-	return getValuesFromResourceHash(ctx, c, clusterSummary, kustomizationRef.ValuesFrom, logger)
+	return writeValuesFromResourceHash(ctx, c, w, clusterSummary, kustomizationRef.ValuesFrom, logger)
 }
 
 func getKustomizationRefs(clusterSummary *configv1beta1.ClusterSummary) []configv1beta1.PolicyRef {
@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -330,6 +331,8 @@ var _ = Describe("Hash methods", func() {
 
 		config += render.AsCode(clusterSummary.Spec.ClusterProfileSpec.KustomizationRefs)
 		for i := 0; i < repoNum; i++ {
+			config += controllers.GetReferenceHashKey(sourcev1.GroupVersion.String(), sourcev1.GitRepositoryKind,
+				gitRepositories[i].Namespace, gitRepositories[i].Name)
 			config += gitRepositories[i].Status.Artifact.Revision
 		}
 
@@ -343,7 +346,7 @@ var _ = Describe("Hash methods", func() {
 		Expect(reflect.DeepEqual(hash, expectHash)).To(BeTrue())
 	})
 
-	It(`getKustomizeReferenceResourceHash returns the hash considering all referenced 
+	It(`writeKustomizeReferenceResourceHash writes the hash considering all referenced 
 	ConfigMap/Secret in the ValueFrom section`, func() {
 		namespace := randomString()
 		configMap := &corev1.ConfigMap{
@@ -369,7 +372,11 @@ var _ = Describe("Hash methods", func() {
 		}
 
 		var expectedHash string
+		expectedHash += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			configMap.Namespace, configMap.Name)
 		expectedHash += controllers.GetStringDataSectionHash(configMap.Data)
+		expectedHash += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.SecretReferencedResourceKind),
+			secret.Namespace, secret.Name)
 		expectedHash += controllers.GetByteDataSectionHash(secret.Data)
 
 		kustomizationRef := configv1beta1.KustomizationRef{
@@ -410,10 +417,11 @@ var _ = Describe("Hash methods", func() {
 		}
 		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
 
-		hash, err := controllers.GetKustomizeReferenceResourceHash(context.TODO(), c, clusterSummary,
+		var hash strings.Builder
+		err := controllers.WriteKustomizeReferenceResourceHash(context.TODO(), c, &hash, clusterSummary,
 			&kustomizationRef, textlogger.NewLogger(textlogger.NewConfig()))
 		Expect(err).To(BeNil())
-		Expect(expectedHash).To(Equal(hash))
+		Expect(expectedHash).To(Equal(hash.String()))
 	})
 
 	It("instantiateKustomizeSubstituteValues instantiates substitute values", func() {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"io"
 
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/gdexlab/go-render/render"
@@ -33,10 +34,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
-	"github.com/projectsveltos/addon-controller/controllers/clustercache"
 	"github.com/projectsveltos/addon-controller/pkg/scope"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
-	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
 	"github.com/projectsveltos/libsveltos/lib/deployer"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
 	libsveltostemplate "github.com/projectsveltos/libsveltos/lib/template"
@@ -275,19 +274,6 @@ func undeployResources(ctx context.Context, c client.Client,
 
 	logger.V(logs.LogDebug).Info("undeployResources")
 
-	remoteClient, err := clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName,
-		adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
-	if err != nil {
-		return err
-	}
-
-	cacheMgr := clustercache.GetManager()
-	remoteRestConfig, err := cacheMgr.GetKubernetesRestConfig(ctx, c, clusterNamespace, clusterName,
-		adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
-	if err != nil {
-		return err
-	}
-
 	var resourceReports []configv1beta1.ResourceReport
 
 	// Undeploy from management cluster
@@ -298,12 +284,13 @@ func undeployResources(ctx context.Context, c client.Client,
 	}
 
 	// Undeploy from managed cluster
-	resourceReports, err = undeployStaleResources(ctx, false, remoteRestConfig, remoteClient,
-		configv1beta1.FeatureResources, clusterSummary,
-		getDeployedGroupVersionKinds(clusterSummary, configv1beta1.FeatureResources),
-		map[string]configv1beta1.Resource{}, logger)
-	if err != nil {
-		return err
+	if isManagementClusterOnly(o) {
+		logger.V(logs.LogDebug).Info("managed cluster is being deleted. Skip undeploying from managed cluster")
+	} else {
+		resourceReports, err = undeployFromManagedCluster(ctx, c, clusterSummary, configv1beta1.FeatureResources, logger)
+		if err != nil {
+			return err
+		}
 	}
 
 	profileOwnerRef, err := configv1beta1.GetProfileOwnerReference(clusterSummary)
@@ -348,69 +335,93 @@ func resourcesHash(ctx context.Context, c client.Client, clusterSummaryScope *sc
 	}
 
 	h := sha256.New()
-	var config string
-	config += string(clusterProfileSpecHash)
+	h.Write(clusterProfileSpecHash)
+
+	if err := writeDeploymentPoliciesHash(ctx, c, h); err != nil {
+		return nil, err
+	}
 
 	clusterSummary := clusterSummaryScope.ClusterSummary
 	for i := range clusterSummary.Spec.ClusterProfileSpec.PolicyRefs {
 		reference := &clusterSummary.Spec.ClusterProfileSpec.PolicyRefs[i]
-		namespace := libsveltostemplate.GetReferenceResourceNamespace(
-			clusterSummaryScope.Namespace(), reference.Namespace)
-
-		name, err := libsveltostemplate.GetReferenceResourceName(clusterSummary.Spec.ClusterNamespace,
-			clusterSummary.Spec.ClusterName, string(clusterSummary.Spec.ClusterType), reference.Name)
-		if err != nil {
-			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name for %s %s/%s: %v",
-				reference.Kind, reference.Namespace, reference.Name, err))
+		if err := writePolicyRefHash(ctx, c, h, clusterSummaryScope, reference, logger); err != nil {
 			return nil, err
 		}
+	}
 
-		if reference.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-			configmap := &corev1.ConfigMap{}
-			err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, configmap)
-			if err == nil {
-				config += getConfigMapHash(configmap)
-			}
-		} else if reference.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
-			secret := &corev1.Secret{}
-			err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
-			if err == nil {
-				config += getSecretHash(secret)
-			}
-		} else {
-			var source client.Object
-			source, err = getSource(ctx, c, namespace, name, reference.Kind)
-			if err == nil && source == nil {
-				s := source.(sourcev1.Source)
-				if s.GetArtifact() != nil {
-					config += s.GetArtifact().Revision
-				}
+	for i := range clusterSummary.Spec.ClusterProfileSpec.ValidateHealths {
+		vh := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
+		if vh.FeatureID == configv1beta1.FeatureResources {
+			fmt.Fprint(h, render.AsCode(vh))
+		}
+	}
+
+	return h.Sum(nil), nil
+}
+
+// writePolicyRefHash writes the content of the resource referenced by a PolicyRef into w.
+func writePolicyRefHash(ctx context.Context, c client.Client, w io.Writer,
+	clusterSummaryScope *scope.ClusterSummaryScope, reference *configv1beta1.PolicyRef, logger logr.Logger) error {
+
+	clusterSummary := clusterSummaryScope.ClusterSummary
+	namespace := libsveltostemplate.GetReferenceResourceNamespace(
+		clusterSummaryScope.Namespace(), reference.Namespace)
+
+	name, err := getPolicyRefName(clusterSummary, reference)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name for %s %s/%s: %v",
+			reference.Kind, reference.Namespace, reference.Name, err))
+		return err
+	}
+
+	apiVersion := getReferenceAPIVersion(reference.Kind)
+	if reference.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
+		configmap := &corev1.ConfigMap{}
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, configmap)
+		if err == nil {
+			writeReferenceHashKey(w, apiVersion, reference.Kind, namespace, name)
+			writeConfigMapHash(w, configmap)
+		}
+	} else if reference.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
+		secret := &corev1.Secret{}
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+		if err == nil {
+			writeReferenceHashKey(w, apiVersion, reference.Kind, namespace, name)
+			writeSecretHash(w, secret)
+		}
+	} else {
+		var source client.Object
+		source, err = getSource(ctx, c, namespace, name, reference.Kind)
+		if err == nil && source == nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("%s %s/%s does not exist yet",
+				reference.Kind, reference.Namespace, name))
+			writeMissingReferenceHash(w, apiVersion, reference.Kind, namespace, name)
+			return nil
+		}
+		if err == nil {
+			writeReferenceHashKey(w, apiVersion, reference.Kind, namespace, name)
+			s := source.(sourcev1.Source)
+			if s.GetArtifact() != nil {
+				fmt.Fprint(w, s.GetArtifact().Revision)
 			}
 			if source.GetAnnotations() != nil {
-				config += getDataSectionHash(source.GetAnnotations())
+				writeDataSectionHash(w, source.GetAnnotations())
 			}
 		}
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				logger.V(logs.LogInfo).Info(fmt.Sprintf("%s %s/%s does not exist yet",
-					reference.Kind, reference.Namespace, name))
-				continue
-			}
-			logger.Error(err, fmt.Sprintf("failed to get %s %s/%s",
-				reference.Kind, reference.Namespace, name))
-			return nil, err
-		}
 	}
-
-	for i := range clusterSummary.Spec.ClusterProfileSpec.ValidateHealths {
-		h := &clusterSummary.Spec.ClusterProfileSpec.ValidateHealths[i]
-		if h.FeatureID == configv1beta1.FeatureResources {
-			config += render.AsCode(h)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("%s %s/%s does not exist yet",
+				reference.Kind, reference.Namespace, name))
+			writeMissingReferenceHash(w, apiVersion, reference.Kind, namespace, name)
+			return nil
 		}
+		logger.Error(err, fmt.Sprintf("failed to get %s %s/%s",
+			reference.Kind, reference.Namespace, name))
+		return err
 	}
 
-	h.Write([]byte(config))
-	return h.Sum(nil), nil
+	return nil
 }
 
 func getResourceRefs(clusterSummary *configv1beta1.ClusterSummary) []configv1beta1.PolicyRef {
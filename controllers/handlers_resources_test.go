@@ -332,6 +332,8 @@ var _ = Describe("Hash methods", func() {
 		configMap2 := createConfigMapWithPolicy(randomString(), randomString(), render.AsCode(clusterRole2))
 
 		namespace := randomString()
+		missingNamespace := randomString()
+		missingName := randomString()
 		clusterSummary := &configv1beta1.ClusterSummary{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: randomString(),
@@ -351,7 +353,7 @@ var _ = Describe("Hash methods", func() {
 							Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
 						},
 						{
-							Namespace: randomString(), Name: randomString(),
+							Namespace: missingNamespace, Name: missingName,
 							Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
 						},
 					},
@@ -393,8 +395,14 @@ var _ = Describe("Hash methods", func() {
 		tmpHash := h.Sum(nil)
 
 		config = string(tmpHash)
+		config += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			configMap1.Namespace, configMap1.Name)
 		config += controllers.GetStringDataSectionHash(configMap1.Data)
+		config += controllers.GetReferenceHashKey("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			configMap2.Namespace, configMap2.Name)
 		config += controllers.GetStringDataSectionHash(configMap2.Data)
+		config += controllers.GetMissingReferenceHash("v1", string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			missingNamespace, missingName)
 
 		h = sha256.New()
 		h.Write([]byte(config))
@@ -45,6 +45,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -55,6 +56,7 @@ import (
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/controllers/clustercache"
+	"github.com/projectsveltos/addon-controller/internal/features"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
 	"github.com/projectsveltos/libsveltos/lib/deployer"
@@ -67,11 +69,21 @@ import (
 const (
 	separator                = "---\n"
 	reasonLabel              = "projectsveltos.io/reason"
+	resourceDeletionTimeout  = time.Minute
 	clusterSummaryAnnotation = "projectsveltos.io/clustersummary"
 	subresourcesAnnotation   = "projectsveltos.io/subresources"
 	pathAnnotation           = "path"
+
+	// managementClusterOnlyOption is the undeploy handler option set when only the
+	// management cluster needs to be cleaned (managed cluster is being deleted)
+	managementClusterOnlyOption = "managementClusterOnly"
 )
 
+// isManagementClusterOnly returns true if undeploy must not reach the managed cluster
+func isManagementClusterOnly(o deployer.Options) bool {
+	return o.HandlerOptions != nil && o.HandlerOptions[managementClusterOnlyOption] == "true"
+}
+
 func getClusterSummaryAnnotationValue(clusterSummary *configv1beta1.ClusterSummary) string {
 	prefix := getPrefix(clusterSummary.Spec.ClusterType)
 	return fmt.Sprintf("%s-%s-%s", prefix, clusterSummary.Spec.ClusterNamespace,
@@ -97,7 +109,8 @@ func createNamespace(ctx context.Context, clusterClient client.Client,
 		if apierrors.IsNotFound(err) {
 			ns := &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: namespaceName,
+					Name:   namespaceName,
+					Labels: clusterSummary.Spec.ClusterProfileSpec.NamespaceLabels,
 				},
 			}
 			return clusterClient.Create(ctx, ns)
@@ -316,6 +329,180 @@ func updateResource(ctx context.Context, dr dynamic.ResourceInterface,
 	return updatedObject, applySubresources(ctx, dr, object, subresources, &options)
 }
 
+// isImmutableFieldError returns true if err is the API server rejecting an update because
+// an immutable field was changed
+func isImmutableFieldError(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+
+	return strings.Contains(err.Error(), "field is immutable")
+}
+
+// shouldRecreateOnImmutableChange returns true if resources deployed because of featureID must be
+// recreated when an update fails because of an immutable field change
+func shouldRecreateOnImmutableChange(clusterSummary *configv1beta1.ClusterSummary,
+	featureID configv1beta1.FeatureID) bool {
+
+	for _, f := range clusterSummary.Spec.ClusterProfileSpec.RecreateOnImmutableChange {
+		if f == featureID {
+			return true
+		}
+	}
+	return false
+}
+
+// recreateResource deletes object from the Cluster, waits for it to be gone and creates it again.
+// No action in DryRun mode.
+func recreateResource(ctx context.Context, dr dynamic.ResourceInterface,
+	clusterSummary *configv1beta1.ClusterSummary, object *unstructured.Unstructured, subresources []string,
+	logger logr.Logger) (*unstructured.Unstructured, error) {
+
+	if clusterSummary.Spec.ClusterProfileSpec.SyncMode == configv1beta1.SyncModeDryRun {
+		return object, nil
+	}
+
+	l := logger.WithValues("resourceNamespace", object.GetNamespace(), "resourceName", object.GetName(),
+		"resourceGVK", object.GetObjectKind().GroupVersionKind())
+	l.V(logs.LogInfo).Info("immutable field changed. Recreating resource")
+
+	propagationPolicy := metav1.DeletePropagationForeground
+	err := dr.Delete(ctx, object.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	err = waitForResourceDeletion(ctx, dr, object.GetName(), resourceDeletionTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// resourceVersion, used as optimistic lock while updating, cannot be set on creation
+	object.SetResourceVersion("")
+	return updateResource(ctx, dr, clusterSummary, object, subresources, logger)
+}
+
+// waitForResourceDeletion polls the Cluster till resource name is gone. An error is returned if
+// resource still exists after timeout.
+func waitForResourceDeletion(ctx context.Context, dr dynamic.ResourceInterface, name string,
+	timeout time.Duration) error {
+
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true,
+		func(ctx context.Context) (bool, error) {
+			_, err := dr.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				return false, err
+			}
+			return false, nil
+		})
+	if err != nil {
+		return fmt.Errorf("waiting for %s to be deleted: %w", name, err)
+	}
+	return nil
+}
+
+// validateResources runs a server-side dry-run apply for each resource. Resources are not
+// persisted. Returns an error listing every resource rejected by the API server (schema
+// validation, admission webhooks, ...).
+// Resources whose namespace or type does not exist yet (because created by other resources
+// being deployed) are not validated. Immutable field changes are not reported when such
+// resources are to be recreated (RecreateOnImmutableChange).
+func validateResources(ctx context.Context, destConfig *rest.Config, clusterSummary *configv1beta1.ClusterSummary,
+	featureID configv1beta1.FeatureID, resources []*unstructured.Unstructured, logger logr.Logger) error {
+
+	forceConflict := true
+	options := metav1.PatchOptions{
+		FieldManager: "application/apply-patch",
+		Force:        &forceConflict,
+		DryRun:       []string{metav1.DryRunAll},
+	}
+
+	var failures []configv1beta1.ResourceFailure
+	for i := range resources {
+		policy := resources[i]
+
+		dr, err := k8s_utils.GetDynamicResourceInterface(destConfig, policy.GroupVersionKind(), policy.GetNamespace())
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+
+		data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, policy)
+		if err != nil {
+			return err
+		}
+
+		_, err = dr.Patch(ctx, policy.GetName(), types.ApplyPatchType, data, options)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			// Resource will be recreated while deploying
+			if isImmutableFieldError(err) && shouldRecreateOnImmutableChange(clusterSummary, featureID) {
+				continue
+			}
+			if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) || apierrors.IsForbidden(err) {
+				failures = append(failures, getResourceFailure(policy, err.Error()))
+				continue
+			}
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		messages := make([]string, len(failures))
+		for i := range failures {
+			messages[i] = fmt.Sprintf("%s %s/%s: %s",
+				failures[i].Kind, failures[i].Namespace, failures[i].Name, failures[i].Message)
+		}
+		msg := fmt.Sprintf("resources failed server-side validation: %s", strings.Join(messages, "; "))
+		logger.V(logs.LogInfo).Info(msg)
+		return &ResourceDeploymentError{Message: msg, FailedResources: failures}
+	}
+
+	return nil
+}
+
+// ResourceDeploymentError is returned when one or more resources cannot be deployed.
+// It lists each failed resource along with the reason.
+type ResourceDeploymentError struct {
+	Message         string
+	FailedResources []configv1beta1.ResourceFailure
+	Err             error
+}
+
+func (e *ResourceDeploymentError) Error() string {
+	return e.Message
+}
+
+func (e *ResourceDeploymentError) Unwrap() error {
+	return e.Err
+}
+
+func getResourceFailure(policy *unstructured.Unstructured, message string) configv1beta1.ResourceFailure {
+	return configv1beta1.ResourceFailure{
+		APIVersion: policy.GetAPIVersion(),
+		Kind:       policy.GetKind(),
+		Namespace:  policy.GetNamespace(),
+		Name:       policy.GetName(),
+		Message:    message,
+	}
+}
+
+// getFailedResources returns the resources which failed to be deployed, if err reports any
+func getFailedResources(err error) []configv1beta1.ResourceFailure {
+	var deploymentError *ResourceDeploymentError
+	if errors.As(err, &deploymentError) {
+		return deploymentError.FailedResources
+	}
+	return nil
+}
+
 func instantiateTemplate(referencedObject client.Object, logger logr.Logger) bool {
 	annotations := referencedObject.GetAnnotations()
 	if annotations != nil {
@@ -343,7 +530,8 @@ func getSubresources(referencedObject client.Object) []string {
 
 // deployContent deploys policies contained in a ConfigMap/Secret.
 // data might have one or more keys. Each key might contain a single policy
-// or multiple policies separated by '---'
+// or multiple policies separated by '---'. If referencedObject is marked as Jsonnet
+// or CUE, data is evaluated as Jsonnet or CUE instead.
 // Returns an error if one occurred. Otherwise it returns a slice containing the name of
 // the policies deployed in the form of kind.group:namespace:name for namespaced policies
 // and kind.group::name for cluster wide policies.
@@ -353,8 +541,22 @@ func deployContent(ctx context.Context, deployingToMgmtCluster bool, destConfig
 ) (reports []configv1beta1.ResourceReport, err error) {
 
 	subresources := getSubresources(referencedObject)
-	instantiateTemplate := instantiateTemplate(referencedObject, logger)
-	resources, err := collectContent(ctx, clusterSummary, mgmtResources, data, instantiateTemplate, logger)
+	var resources []*unstructured.Unstructured
+	switch {
+	case isJsonnet(referencedObject):
+		if !features.Gates.Enabled(features.Jsonnet) {
+			return nil, &NonRetriableError{Message: fmt.Sprintf("%s feature gate is disabled", features.Jsonnet)}
+		}
+		resources, err = renderJsonnet(ctx, clusterSummary, data, logger)
+	case isCUE(referencedObject):
+		if !features.Gates.Enabled(features.CUE) {
+			return nil, &NonRetriableError{Message: fmt.Sprintf("%s feature gate is disabled", features.CUE)}
+		}
+		resources, err = renderCUE(ctx, clusterSummary, data, logger)
+	default:
+		instantiateTemplate := instantiateTemplate(referencedObject, logger)
+		resources, err = collectContent(ctx, clusterSummary, mgmtResources, data, instantiateTemplate, logger)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -447,8 +649,16 @@ func deployUnstructured(ctx context.Context, deployingToMgmtCluster bool, destCo
 		return nil, err
 	}
 
-	conflictErrorMsg := ""
-	reports = make([]configv1beta1.ResourceReport, 0)
+	// Nothing is deployed if any resource violates the admin defined deployment policies
+	err = validateDeploymentPolicies(ctx, referencedUnstructured)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admission webhooks might reject resources deployed right after them (for instance while the
+	// webhook server is not running yet). So webhook configurations are deployed last.
+	referencedUnstructured = moveWebhookConfigurationsLast(referencedUnstructured)
+
 	for i := range referencedUnstructured {
 		policy := referencedUnstructured[i]
 
@@ -460,14 +670,52 @@ func deployUnstructured(ctx context.Context, deployingToMgmtCluster bool, destCo
 		if !isResourceNamespaceValid(profile, policy, deployingToMgmtCluster) {
 			return nil, fmt.Errorf("profile can only deploy resource in same namespace in the management cluster")
 		}
+	}
 
-		logger.V(logs.LogDebug).Info(fmt.Sprintf("deploying resource %s %s/%s (deploy to management cluster: %v)",
-			policy.GetKind(), policy.GetNamespace(), policy.GetName(), deployingToMgmtCluster))
+	// applied is the number of resources, from the first one, successfully deployed. If a previous attempt
+	// to deploy these very same resources failed halfway, deployment resumes from where it stopped.
+	applied := 0
+	if clusterSummary.Spec.ClusterProfileSpec.SyncMode != configv1beta1.SyncModeDryRun {
+		checkpointKey := getCheckpointKey(clusterSummary, featureID, referencedObject, deployingToMgmtCluster)
+		var resourcesHash string
+		resourcesHash, err = getResourcesHash(referencedUnstructured)
+		if err != nil {
+			return nil, err
+		}
+		applied = getCheckpoint(checkpointKey, resourcesHash)
+		defer func() {
+			if err == nil {
+				removeCheckpoint(checkpointKey)
+			} else {
+				setCheckpoint(checkpointKey, resourcesHash, applied)
+			}
+		}()
+
+		// Nothing is applied if any resource is rejected by the API server
+		err = validateResources(ctx, destConfig, clusterSummary, featureID, referencedUnstructured[applied:], logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conflictErrorMsg := ""
+	reports = make([]configv1beta1.ResourceReport, 0)
+	for i := range referencedUnstructured {
+		policy := referencedUnstructured[i]
 
 		resource, policyHash := getResource(policy, hasIgnoreConfigurationDriftAnnotation(policy), referencedObject, profileTier, featureID, logger)
 
+		if i < applied {
+			reports = append(reports, configv1beta1.ResourceReport{Resource: *resource,
+				Action: string(configv1beta1.NoResourceAction), Message: "Object already deployed by a previous attempt."})
+			continue
+		}
+
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("deploying resource %s %s/%s (deploy to management cluster: %v)",
+			policy.GetKind(), policy.GetNamespace(), policy.GetName(), deployingToMgmtCluster))
+
 		// If policy is namespaced, create namespace if not already existing
-		err = createNamespace(ctx, destClient, clusterSummary, policy.GetNamespace())
+		err := createNamespace(ctx, destClient, clusterSummary, policy.GetNamespace())
 		if err != nil {
 			return nil, err
 		}
@@ -499,6 +747,30 @@ func deployUnstructured(ctx context.Context, deployingToMgmtCluster bool, destCo
 			return reports, err
 		}
 
+		if isUnmanagedResource(resourceInfo) {
+			adoptionPolicy := clusterSummary.Spec.ClusterProfileSpec.AdoptionPolicy
+			unmanagedResourceReport := generateUnmanagedResourceReport(resource, adoptionPolicy)
+			switch adoptionPolicy {
+			case configv1beta1.AdoptionPolicySkip:
+				logger.V(logs.LogDebug).Info(unmanagedResourceReport.Message)
+				if clusterSummary.Spec.ClusterProfileSpec.SyncMode == configv1beta1.SyncModeDryRun {
+					reports = append(reports, *unmanagedResourceReport)
+				}
+				continue
+			case configv1beta1.AdoptionPolicyFail:
+				logger.V(logs.LogDebug).Info(unmanagedResourceReport.Message)
+				if clusterSummary.Spec.ClusterProfileSpec.SyncMode == configv1beta1.SyncModeDryRun {
+					reports = append(reports, *unmanagedResourceReport)
+					continue
+				}
+				conflictErrorMsg += unmanagedResourceReport.Message
+				if clusterSummary.Spec.ClusterProfileSpec.ContinueOnConflict {
+					continue
+				}
+				return reports, deployer.NewConflictError(conflictErrorMsg)
+			}
+		}
+
 		addMetadata(policy, resourceInfo.GetResourceVersion(), profile,
 			clusterSummary.Spec.ClusterProfileSpec.ExtraLabels, clusterSummary.Spec.ClusterProfileSpec.ExtraAnnotations)
 
@@ -523,13 +795,48 @@ func deployUnstructured(ctx context.Context, deployingToMgmtCluster bool, destCo
 			}
 		}
 
-		policy, err = updateResource(ctx, dr, clusterSummary, policy, subresources, logger)
+		failure := getResourceFailure(policy, "")
+		var deployedPolicy *unstructured.Unstructured
+		var updateErr error
+		deployedPolicy, err = updateResource(ctx, dr, clusterSummary, policy, subresources, logger)
+		if err != nil && isImmutableFieldError(err) && shouldRecreateOnImmutableChange(clusterSummary, featureID) {
+			updateErr = err
+			deployedPolicy, err = recreateResource(ctx, dr, clusterSummary, policy, subresources, logger)
+		}
 		if err != nil {
-			return reports, err
+			failure.Message = err.Error()
+			return reports, &ResourceDeploymentError{Message: err.Error(), Err: err,
+				FailedResources: []configv1beta1.ResourceFailure{failure}}
 		}
+		policy = deployedPolicy
 
 		resource.LastAppliedTime = &metav1.Time{Time: time.Now()}
-		reports = append(reports, *generateResourceReport(policyHash, resourceInfo, policy, resource))
+		resourceReport := generateResourceReport(policyHash, resourceInfo, policy, resource)
+		if updateErr != nil {
+			resourceReport.Action = string(configv1beta1.RecreateResourceAction)
+			resourceReport.Message = fmt.Sprintf("resource recreated as update failed: %v", updateErr)
+		}
+		reports = append(reports, *resourceReport)
+
+		if clusterSummary.Spec.ClusterProfileSpec.ReloadConsumers &&
+			clusterSummary.Spec.ClusterProfileSpec.SyncMode != configv1beta1.SyncModeDryRun &&
+			resourceReport.Action == string(configv1beta1.UpdateResourceAction) {
+
+			err = reloadConsumers(ctx, destClient, policy, logger)
+			if err != nil {
+				// Resource is not considered deployed till its consumers are reloaded. Restoring the
+				// previous hash makes next attempt see it as changed and reload consumers again.
+				if restoreErr := restorePolicyHash(ctx, dr, policy, resourceInfo.Hash); restoreErr != nil {
+					logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to restore policy hash on %s %s/%s: %v",
+						policy.GetKind(), policy.GetNamespace(), policy.GetName(), restoreErr))
+				}
+				return reports, err
+			}
+		}
+
+		if applied == i {
+			applied++
+		}
 	}
 
 	if conflictErrorMsg != "" {
@@ -542,6 +849,31 @@ func deployUnstructured(ctx context.Context, deployingToMgmtCluster bool, destCo
 	return reports, nil
 }
 
+// isWebhookConfiguration returns true if policy is a ValidatingWebhookConfiguration or a
+// MutatingWebhookConfiguration
+func isWebhookConfiguration(policy *unstructured.Unstructured) bool {
+	gvk := policy.GroupVersionKind()
+	if gvk.Group != "admissionregistration.k8s.io" {
+		return false
+	}
+	return gvk.Kind == "ValidatingWebhookConfiguration" || gvk.Kind == "MutatingWebhookConfiguration"
+}
+
+// moveWebhookConfigurationsLast returns policies with all webhook configurations moved at the end.
+// Relative order of all other policies is preserved.
+func moveWebhookConfigurationsLast(policies []*unstructured.Unstructured) []*unstructured.Unstructured {
+	result := make([]*unstructured.Unstructured, 0, len(policies))
+	webhookConfigurations := make([]*unstructured.Unstructured, 0)
+	for i := range policies {
+		if isWebhookConfiguration(policies[i]) {
+			webhookConfigurations = append(webhookConfigurations, policies[i])
+		} else {
+			result = append(result, policies[i])
+		}
+	}
+	return append(result, webhookConfigurations...)
+}
+
 func addMetadata(policy *unstructured.Unstructured, resourceVersion string, profile client.Object,
 	extraLabels, extraAnnotations map[string]string) {
 
@@ -1066,6 +1398,31 @@ func appendPathAnnotations(object client.Object, reference *configv1beta1.Policy
 	object.SetAnnotations(annotations)
 }
 
+// getPolicyRefName returns the name of the resource referenced by a PolicyRef. Name, which can be
+// expressed as a template, is instantiated. For PolicyRefs pinned to a version of a ConfigMap/Secret,
+// the version is appended.
+func getPolicyRefName(clusterSummary *configv1beta1.ClusterSummary, reference *configv1beta1.PolicyRef,
+) (string, error) {
+
+	name, err := libsveltostemplate.GetReferenceResourceName(clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, string(clusterSummary.Spec.ClusterType), reference.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if reference.Version == "" {
+		return name, nil
+	}
+
+	if reference.Kind != string(libsveltosv1beta1.ConfigMapReferencedResourceKind) &&
+		reference.Kind != string(libsveltosv1beta1.SecretReferencedResourceKind) {
+
+		return name, nil
+	}
+
+	return fmt.Sprintf("%s-%s", name, reference.Version), nil
+}
+
 // collectReferencedObjects collects all referenced configMaps/secrets in control cluster
 // local contains all configMaps/Secrets whose content need to be deployed locally (in the management cluster)
 // remote contains all configMap/Secrets whose content need to be deployed remotely (in the managed cluster)
@@ -1082,8 +1439,7 @@ func collectReferencedObjects(ctx context.Context, controlClusterClient client.C
 		namespace := libsveltostemplate.GetReferenceResourceNamespace(
 			clusterSummary.Namespace, references[i].Namespace)
 
-		name, err := libsveltostemplate.GetReferenceResourceName(clusterSummary.Spec.ClusterNamespace,
-			clusterSummary.Spec.ClusterName, string(clusterSummary.Spec.ClusterType), references[i].Name)
+		name, err := getPolicyRefName(clusterSummary, reference)
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name for %s %s/%s: %v",
 				reference.Kind, reference.Namespace, reference.Name, err))
@@ -1105,7 +1461,7 @@ func collectReferencedObjects(ctx context.Context, controlClusterClient client.C
 				msg := fmt.Sprintf("Referenced resource: %s %s/%s does not exist",
 					reference.Kind, reference.Namespace, name)
 				logger.V(logs.LogInfo).Info(msg)
-				return nil, nil, &NonRetriableError{Message: msg}
+				return nil, nil, &NonRetriableError{Message: msg, Reason: referenceMissingFailureReason}
 			}
 			return nil, nil, err
 		}
@@ -1285,15 +1641,23 @@ func undeployStaleResources(ctx context.Context, isMgmtCluster bool,
 			Resource: mapping.Resource.Resource,
 		}
 
-		list, err := d.Resource(resourceId).List(ctx, listOptions)
+		// Resources are updated/deleted only once listing is over, as doing it while paging could make
+		// the continue token expire. Only stale resources are kept in memory.
+		staleResources := make([]unstructured.Unstructured, 0)
+		err = forEachUnstructured(ctx, d.Resource(resourceId), listOptions,
+			func(r *unstructured.Unstructured) error {
+				if isStaleResource(isMgmtCluster, clusterSummary, r, currentPolicies) {
+					staleResources = append(staleResources, *r)
+				}
+				return nil
+			})
 		if err != nil {
 			return nil, err
 		}
 
-		for j := range list.Items {
-			r := list.Items[j]
-			rr, err := undeployStaleResource(ctx, isMgmtCluster, remoteClient, profile, clusterSummary,
-				r, currentPolicies, logger)
+		for j := range staleResources {
+			rr, err := undeployStaleResource(ctx, isMgmtCluster, remoteClient, featureID, profile, clusterSummary,
+				staleResources[j], currentPolicies, logger)
 			if err != nil {
 				return nil, err
 			}
@@ -1308,28 +1672,14 @@ func undeployStaleResources(ctx context.Context, isMgmtCluster bool,
 }
 
 func undeployStaleResource(ctx context.Context, isMgmtCluster bool, remoteClient client.Client,
-	profile client.Object, clusterSummary *configv1beta1.ClusterSummary, r unstructured.Unstructured,
+	featureID configv1beta1.FeatureID, profile client.Object, clusterSummary *configv1beta1.ClusterSummary, r unstructured.Unstructured,
 	currentPolicies map[string]configv1beta1.Resource, logger logr.Logger) (*configv1beta1.ResourceReport, error) {
 
 	logger.V(logs.LogVerbose).Info(fmt.Sprintf("considering %s/%s", r.GetNamespace(), r.GetName()))
-	// Verify if this policy was deployed because of a projectsveltos (ReferenceLabelName
-	// is present as label in such a case).
-	if !hasLabel(&r, deployer.ReferenceNameLabel, "") {
+	if !isStaleResource(isMgmtCluster, clusterSummary, &r, currentPolicies) {
 		return nil, nil
 	}
 
-	if isMgmtCluster {
-		// When deploying resources in the management cluster, just setting ClusterProfile as OwnerReference is
-		// not enough. We also need to track which ClusterSummary is creating the resource. Otherwise while
-		// trying to clean stale resources those objects will be incorrectly removed.
-		// An extra annotation is added to indicate the clustersummary, so the managed cluster, this
-		// resource was created for. Check if annotation is present.
-		value := getClusterSummaryAnnotationValue(clusterSummary)
-		if !hasAnnotation(&r, clusterSummaryAnnotation, value) {
-			return nil, nil
-		}
-	}
-
 	var resourceReport *configv1beta1.ResourceReport = nil
 	// If in DryRun do not withdrawn any policy.
 	// If this ClusterSummary is the only OwnerReference and it is not deploying this policy anymore,
@@ -1356,7 +1706,7 @@ func undeployStaleResource(ctx context.Context, isMgmtCluster bool, remoteClient
 			return nil, nil
 		}
 
-		err := handleResourceDelete(ctx, remoteClient, &r, clusterSummary, logger)
+		err := handleResourceDelete(ctx, remoteClient, &r, featureID, clusterSummary, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -1365,8 +1715,34 @@ func undeployStaleResource(ctx context.Context, isMgmtCluster bool, remoteClient
 	return resourceReport, nil
 }
 
+// isStaleResource returns true if resource was deployed by Sveltos for this ClusterSummary and
+// it is not part of the currently deployed policies anymore
+func isStaleResource(isMgmtCluster bool, clusterSummary *configv1beta1.ClusterSummary, r *unstructured.Unstructured,
+	currentPolicies map[string]configv1beta1.Resource) bool {
+
+	// Verify if this policy was deployed because of a projectsveltos (ReferenceLabelName
+	// is present as label in such a case).
+	if !hasLabel(r, deployer.ReferenceNameLabel, "") {
+		return false
+	}
+
+	if isMgmtCluster {
+		// When deploying resources in the management cluster, just setting ClusterProfile as OwnerReference is
+		// not enough. We also need to track which ClusterSummary is creating the resource. Otherwise while
+		// trying to clean stale resources those objects will be incorrectly removed.
+		// An extra annotation is added to indicate the clustersummary, so the managed cluster, this
+		// resource was created for. Check if annotation is present.
+		value := getClusterSummaryAnnotationValue(clusterSummary)
+		if !hasAnnotation(r, clusterSummaryAnnotation, value) {
+			return false
+		}
+	}
+
+	return canDelete(r, currentPolicies)
+}
+
 func handleResourceDelete(ctx context.Context, remoteClient client.Client, policy client.Object,
-	clusterSummary *configv1beta1.ClusterSummary, logger logr.Logger) error {
+	featureID configv1beta1.FeatureID, clusterSummary *configv1beta1.ClusterSummary, logger logr.Logger) error {
 
 	// If mode is set to LeavePolicies, leave policies in the workload cluster.
 	// Remove all labels added by Sveltos.
@@ -1381,7 +1757,60 @@ func handleResourceDelete(ctx context.Context, remoteClient client.Client, polic
 
 	logger.V(logs.LogDebug).Info(fmt.Sprintf("removing resource %s %s/%s",
 		policy.GetObjectKind().GroupVersionKind().Kind, policy.GetNamespace(), policy.GetName()))
-	return remoteClient.Delete(ctx, policy)
+
+	deletionPolicy := getDeletionPolicy(clusterSummary, featureID)
+	if deletionPolicy == nil {
+		return remoteClient.Delete(ctx, policy)
+	}
+
+	deleteOptions := make([]client.DeleteOption, 0)
+	if deletionPolicy.PropagationPolicy != nil {
+		deleteOptions = append(deleteOptions, client.PropagationPolicy(*deletionPolicy.PropagationPolicy))
+	}
+	err := remoteClient.Delete(ctx, policy, deleteOptions...)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if deletionPolicy.WaitForDeletion {
+		return verifyResourceDeleted(ctx, remoteClient, policy)
+	}
+
+	return nil
+}
+
+// getDeletionPolicy returns the DeletionPolicy set for featureID. Nil if none is set.
+func getDeletionPolicy(clusterSummary *configv1beta1.ClusterSummary,
+	featureID configv1beta1.FeatureID) *configv1beta1.DeletionPolicy {
+
+	deletionPolicies := clusterSummary.Spec.ClusterProfileSpec.DeletionPolicies
+	for i := range deletionPolicies {
+		if deletionPolicies[i].FeatureID == featureID {
+			return &deletionPolicies[i]
+		}
+	}
+	return nil
+}
+
+// verifyResourceDeleted returns an error if policy still exists (for instance because its finalizers
+// have not run yet). Caller is expected to retry.
+func verifyResourceDeleted(ctx context.Context, remoteClient client.Client, policy client.Object) error {
+	currentPolicy := &unstructured.Unstructured{}
+	currentPolicy.SetGroupVersionKind(policy.GetObjectKind().GroupVersionKind())
+	err := remoteClient.Get(ctx, types.NamespacedName{Namespace: policy.GetNamespace(), Name: policy.GetName()},
+		currentPolicy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	return fmt.Errorf("waiting for %s %s/%s to be deleted", currentPolicy.GetKind(),
+		currentPolicy.GetNamespace(), currentPolicy.GetName())
 }
 
 // canDelete returns true if a policy can be deleted. For a policy to be deleted:
@@ -1659,6 +2088,40 @@ func generateConflictResourceReport(ctx context.Context, dr dynamic.ResourceInte
 	return conflictReport
 }
 
+// isUnmanagedResource returns true if resource already exists in the cluster but was not deployed
+// by Sveltos (it has neither Sveltos reference labels nor a Sveltos owner reference)
+func isUnmanagedResource(resourceInfo *deployer.ResourceInfo) bool {
+	if resourceInfo == nil || resourceInfo.CurrentResource == nil {
+		return false
+	}
+
+	currentResource := resourceInfo.CurrentResource
+	if _, ok := currentResource.GetLabels()[deployer.ReferenceKindLabel]; ok {
+		return false
+	}
+
+	return !k8s_utils.HasSveltosResourcesAsOwnerReference(currentResource)
+}
+
+// generateUnmanagedResourceReport returns the report for a resource which already exists in the
+// cluster, is not managed by Sveltos and, because of adoptionPolicy, is not deployed
+func generateUnmanagedResourceReport(resource *configv1beta1.Resource,
+	adoptionPolicy configv1beta1.AdoptionPolicy) *configv1beta1.ResourceReport {
+
+	report := &configv1beta1.ResourceReport{
+		Resource: *resource,
+		Action:   string(configv1beta1.NoResourceAction),
+		Message: fmt.Sprintf("%s %s/%s already exists and is not managed by Sveltos (adoptionPolicy: %s).\n",
+			resource.Kind, resource.Namespace, resource.Name, adoptionPolicy),
+	}
+
+	if adoptionPolicy == configv1beta1.AdoptionPolicyFail {
+		report.Action = string(configv1beta1.ConflictResourceAction)
+	}
+
+	return report
+}
+
 func updateDeployedGroupVersionKind(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
 	featureID configv1beta1.FeatureID, localResourceReports, remoteResourceReports []configv1beta1.ResourceReport,
 	logger logr.Logger) (*configv1beta1.ClusterSummary, error) {
@@ -1755,8 +2218,7 @@ func getRestConfig(ctx context.Context, c client.Client, clusterSummary *configv
 	clusterName := clusterSummary.Spec.ClusterName
 
 	adminNamespace, adminName := getClusterSummaryAdmin(clusterSummary)
-	logger = logger.WithValues("cluster", fmt.Sprintf("%s/%s", clusterNamespace, clusterName)).
-		WithValues("clusterSummary", clusterSummary.Name).WithValues("admin", fmt.Sprintf("%s/%s", adminNamespace, adminName))
+	logger = logger.WithValues("admin", fmt.Sprintf("%s/%s", adminNamespace, adminName))
 
 	logger.V(logs.LogDebug).Info("get remote restConfig")
 	cacheMgr := clustercache.GetManager()
@@ -1769,10 +2231,10 @@ func getRestConfig(ctx context.Context, c client.Client, clusterSummary *configv
 	return remoteRestConfig, logger, nil
 }
 
-func getValuesFromResourceHash(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
-	valuesFrom []configv1beta1.ValueFrom, logger logr.Logger) (string, error) {
+// writeValuesFromResourceHash writes the content of all ConfigMaps/Secrets referenced in valuesFrom into w.
+func writeValuesFromResourceHash(ctx context.Context, c client.Client, w io.Writer,
+	clusterSummary *configv1beta1.ClusterSummary, valuesFrom []configv1beta1.ValueFrom, logger logr.Logger) error {
 
-	var config string
 	for i := range valuesFrom {
 		namespace := libsveltostemplate.GetReferenceResourceNamespace(
 			clusterSummary.Namespace, valuesFrom[i].Namespace)
@@ -1782,27 +2244,34 @@ func getValuesFromResourceHash(ctx context.Context, c client.Client, clusterSumm
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name for %s %s/%s: %v",
 				valuesFrom[i].Kind, valuesFrom[i].Namespace, valuesFrom[i].Name, err))
-			return "", err
+			return err
 		}
 
+		apiVersion := getReferenceAPIVersion(valuesFrom[i].Kind)
 		if valuesFrom[i].Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
 			configMap, err := getConfigMap(ctx, c,
 				types.NamespacedName{Namespace: namespace, Name: name})
 			if err == nil {
-				config += getDataSectionHash(configMap.Data)
-				config += getDataSectionHash(configMap.BinaryData)
+				writeReferenceHashKey(w, apiVersion, valuesFrom[i].Kind, namespace, name)
+				writeDataSectionHash(w, configMap.Data)
+				writeDataSectionHash(w, configMap.BinaryData)
+			} else if apierrors.IsNotFound(err) {
+				writeMissingReferenceHash(w, apiVersion, valuesFrom[i].Kind, namespace, name)
 			}
 		} else if valuesFrom[i].Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
 			secret, err := getSecret(ctx, c,
 				types.NamespacedName{Namespace: namespace, Name: name})
 			if err == nil {
-				config += getDataSectionHash(secret.Data)
-				config += getDataSectionHash(secret.StringData)
+				writeReferenceHashKey(w, apiVersion, valuesFrom[i].Kind, namespace, name)
+				writeDataSectionHash(w, secret.Data)
+				writeDataSectionHash(w, secret.StringData)
+			} else if apierrors.IsNotFound(err) {
+				writeMissingReferenceHash(w, apiVersion, valuesFrom[i].Kind, namespace, name)
 			}
 		}
 	}
 
-	return config, nil
+	return nil
 }
 
 // getValuesFrom function retrieves key-value pairs from referenced ConfigMaps or Secrets.
@@ -1839,7 +2308,7 @@ func getValuesFrom(ctx context.Context, c client.Client, clusterSummary *configv
 					msg := fmt.Sprintf("Referenced resource: %s %s/%s does not exist",
 						libsveltosv1beta1.ConfigMapReferencedResourceKind, namespace, name)
 					logger.V(logs.LogInfo).Info(msg)
-					return nil, nil, &NonRetriableError{Message: msg}
+					return nil, nil, &NonRetriableError{Message: msg, Reason: referenceMissingFailureReason}
 				}
 				return nil, nil, fmt.Errorf("%s: %w", msg, err)
 			}
@@ -1870,7 +2339,7 @@ func getValuesFrom(ctx context.Context, c client.Client, clusterSummary *configv
 					msg := fmt.Sprintf("Referenced resource: %s %s/%s does not exist",
 						libsveltosv1beta1.SecretReferencedResourceKind, namespace, name)
 					logger.V(logs.LogInfo).Info(msg)
-					return nil, nil, &NonRetriableError{Message: msg}
+					return nil, nil, &NonRetriableError{Message: msg, Reason: referenceMissingFailureReason}
 				}
 				return nil, nil, fmt.Errorf("%s: %w", msg, err)
 			}
@@ -1935,26 +2404,25 @@ func initiatePatches(ctx context.Context, clusterSummary *configv1beta1.ClusterS
 
 func getClusterProfileSpecHash(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary) ([]byte, error) {
 	h := sha256.New()
-	var config string
 
 	clusterProfileSpec := clusterSummary.Spec.ClusterProfileSpec
 	// If SyncMode changes (from not ContinuousWithDriftDetection to ContinuousWithDriftDetection
 	// or viceversa) reconcile.
-	config += fmt.Sprintf("%v", clusterProfileSpec.SyncMode)
+	fmt.Fprintf(h, "%v", clusterProfileSpec.SyncMode)
 
 	// If Reloader changes, Reloader needs to be deployed or undeployed
 	// So consider it in the hash
-	config += fmt.Sprintf("%v", clusterProfileSpec.Reloader)
+	fmt.Fprintf(h, "%v", clusterProfileSpec.Reloader)
 
 	// If Tier changes, conflicts might be resolved differently
 	// So consider it in the hash
-	config += fmt.Sprintf("%d", clusterProfileSpec.Tier)
-	config += fmt.Sprintf("%t", clusterProfileSpec.ContinueOnConflict)
+	fmt.Fprintf(h, "%d", clusterProfileSpec.Tier)
+	fmt.Fprintf(h, "%t", clusterProfileSpec.ContinueOnConflict)
 
 	if clusterProfileSpec.SyncMode == configv1beta1.SyncModeContinuousWithDriftDetection {
 		// Use the version. This will cause drift-detection, Sveltos CRDs
 		// to be redeployed on upgrade
-		config += getVersion()
+		fmt.Fprint(h, getVersion())
 	}
 
 	mgmtResources, err := collectTemplateResourceRefs(ctx, clusterSummary)
@@ -1962,11 +2430,11 @@ func getClusterProfileSpecHash(ctx context.Context, clusterSummary *configv1beta
 		return nil, err
 	}
 	for i := range mgmtResources {
-		config += render.AsCode(mgmtResources[i])
+		fmt.Fprint(h, render.AsCode(mgmtResources[i]))
 	}
 
 	if clusterProfileSpec.Patches != nil {
-		config += render.AsCode(clusterProfileSpec.Patches)
+		fmt.Fprint(h, render.AsCode(clusterProfileSpec.Patches))
 	}
 
 	// If drift-detectionmanager configuration is in a ConfigMap. fetch ConfigMap and use its Data
@@ -1976,9 +2444,32 @@ func getClusterProfileSpecHash(ctx context.Context, clusterSummary *configv1beta
 		if err != nil {
 			return nil, err
 		}
-		config += render.AsCode(configMap.Data)
+		fmt.Fprint(h, render.AsCode(configMap.Data))
 	}
 
-	h.Write([]byte(config))
 	return h.Sum(nil), nil
 }
+
+// undeployFromManagedCluster removes from the managed cluster all resources deployed because of
+// this clusterSummary feature
+func undeployFromManagedCluster(ctx context.Context, c client.Client, clusterSummary *configv1beta1.ClusterSummary,
+	featureID configv1beta1.FeatureID, logger logr.Logger) ([]configv1beta1.ResourceReport, error) {
+
+	adminNamespace, adminName := getClusterSummaryAdmin(clusterSummary)
+
+	cacheMgr := clustercache.GetManager()
+	remoteRestConfig, err := cacheMgr.GetKubernetesRestConfig(ctx, c, clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteClient, err := clusterproxy.GetKubernetesClient(ctx, c, clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, adminNamespace, adminName, clusterSummary.Spec.ClusterType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return undeployStaleResources(ctx, false, remoteRestConfig, remoteClient, featureID, clusterSummary,
+		getDeployedGroupVersionKinds(clusterSummary, featureID), map[string]configv1beta1.Resource{}, logger)
+}
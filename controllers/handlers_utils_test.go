@@ -18,6 +18,7 @@ package controllers_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -32,8 +33,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -583,6 +586,21 @@ var _ = Describe("HandlersUtils", func() {
 		Expect(c.Get(context.TODO(), types.NamespacedName{Name: namespace}, currentNs)).To(Succeed())
 	})
 
+	It("createNamespace adds NamespaceLabels to created namespace", func() {
+		initObjects := []client.Object{}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).WithObjects(initObjects...).Build()
+
+		clusterSummary.Spec.ClusterProfileSpec.NamespaceLabels = map[string]string{
+			"admission.gatekeeper.sh/ignore": "true",
+		}
+		Expect(controllers.CreateNamespace(context.TODO(), c, clusterSummary, namespace)).To(BeNil())
+
+		currentNs := &corev1.Namespace{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: namespace}, currentNs)).To(Succeed())
+		Expect(currentNs.Labels).To(HaveKeyWithValue("admission.gatekeeper.sh/ignore", "true"))
+	})
+
 	It("createNamespace does not namespace in DryRun mode", func() {
 		initObjects := []client.Object{}
 
@@ -1316,6 +1334,62 @@ metadata:
 		Expect(u.GetNamespace()).To(Equal(""))
 	})
 
+	It("validateResources reports resources rejected by the API server without creating any", func() {
+		validName := randomString()
+		validService := fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: default
+spec:
+  ports:
+  - port: 80`, validName)
+
+		invalidName := randomString()
+		// Deployment with no containers fails validation
+		invalidDeployment := fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+  namespace: default
+spec:
+  selector:
+    matchLabels:
+      app: test
+  template:
+    metadata:
+      labels:
+        app: test
+    spec:
+      containers: []`, invalidName)
+
+		valid, err := k8s_utils.GetUnstructured([]byte(validService))
+		Expect(err).To(BeNil())
+		invalid, err := k8s_utils.GetUnstructured([]byte(invalidDeployment))
+		Expect(err).To(BeNil())
+
+		Expect(controllers.ValidateResources(context.TODO(), testEnv.Config, clusterSummary, configv1beta1.FeatureResources,
+			[]*unstructured.Unstructured{valid}, textlogger.NewLogger(textlogger.NewConfig()))).To(Succeed())
+
+		err = controllers.ValidateResources(context.TODO(), testEnv.Config, clusterSummary, configv1beta1.FeatureResources,
+			[]*unstructured.Unstructured{valid, invalid}, textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring(invalidName))
+		Expect(err.Error()).ToNot(ContainSubstring(validName))
+
+		var deploymentError *controllers.ResourceDeploymentError
+		Expect(errors.As(err, &deploymentError)).To(BeTrue())
+		Expect(len(deploymentError.FailedResources)).To(Equal(1))
+		Expect(deploymentError.FailedResources[0].Kind).To(Equal("Deployment"))
+		Expect(deploymentError.FailedResources[0].Namespace).To(Equal("default"))
+		Expect(deploymentError.FailedResources[0].Name).To(Equal(invalidName))
+
+		// Dry-run does not persist resources
+		currentService := &corev1.Service{}
+		err = testEnv.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: validName}, currentService)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
 	It("readFiles loads content of all files in a directory", func() {
 		dir, err := os.MkdirTemp("", "my-temp-dir")
 		Expect(err).To(BeNil())
@@ -1377,7 +1451,7 @@ metadata:
 		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: clusterSummary.Namespace, Name: clusterSummary.Name},
 			currentClusterSummary)).To(Succeed())
 
-		Expect(controllers.HandleResourceDelete(ctx, c, depl, currentClusterSummary,
+		Expect(controllers.HandleResourceDelete(ctx, c, depl, configv1beta1.FeatureResources, currentClusterSummary,
 			textlogger.NewLogger(textlogger.NewConfig()))).To(Succeed())
 
 		currentDepl := &appsv1.Deployment{}
@@ -1388,6 +1462,38 @@ metadata:
 		Expect(v).To(Equal(randomValue))
 	})
 
+	It("handleResourceDelete waits for resources to be gone when WaitForDeletion is set", func() {
+		depl := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  randomString(),
+				Name:       randomString(),
+				Finalizers: []string{randomString()},
+			},
+		}
+		Expect(addTypeInformationToObject(scheme, depl)).To(Succeed())
+		propagationPolicy := metav1.DeletePropagationForeground
+		clusterSummary.Spec.ClusterProfileSpec.DeletionPolicies = []configv1beta1.DeletionPolicy{
+			{FeatureID: configv1beta1.FeatureResources, PropagationPolicy: &propagationPolicy, WaitForDeletion: true},
+		}
+		initObjects := []client.Object{depl}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		// Finalizer is still set so Deployment is not gone yet
+		Expect(controllers.HandleResourceDelete(ctx, c, depl, configv1beta1.FeatureResources, clusterSummary,
+			logger)).ToNot(Succeed())
+
+		currentDepl := &appsv1.Deployment{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: depl.Namespace, Name: depl.Name}, currentDepl)).To(Succeed())
+		Expect(currentDepl.DeletionTimestamp).ToNot(BeNil())
+
+		currentDepl.Finalizers = nil
+		Expect(c.Update(context.TODO(), currentDepl)).To(Succeed())
+
+		Expect(controllers.HandleResourceDelete(ctx, c, depl, configv1beta1.FeatureResources, clusterSummary,
+			logger)).To(Succeed())
+	})
+
 	It("collectContent collect contents with no error even when there are section with just comments", func() {
 		content := `# This file is generated from the individual YAML files by generate-provisioner-deployment.sh. Do not
 # edit this file directly but instead edit the source files and re-render.
@@ -1559,6 +1665,176 @@ status:
 		Expect(serviceOut.Spec.Selector).To(Not(BeNil()))
 		Expect(serviceOut.Spec.Selector[key]).To(Equal(value))
 	})
+
+	It("isManagementClusterOnly returns true only when managementClusterOnly option is set", func() {
+		Expect(controllers.IsManagementClusterOnly(deployer.Options{})).To(BeFalse())
+		Expect(controllers.IsManagementClusterOnly(deployer.Options{HandlerOptions: map[string]string{}})).To(BeFalse())
+		Expect(controllers.IsManagementClusterOnly(deployer.Options{
+			HandlerOptions: map[string]string{"managementClusterOnly": "true"}})).To(BeTrue())
+	})
+
+	It("isUnmanagedResource returns true only for existing resources not deployed by Sveltos", func() {
+		Expect(controllers.IsUnmanagedResource(nil)).To(BeFalse())
+
+		currentResource := &unstructured.Unstructured{}
+		currentResource.SetKind("ConfigMap")
+		currentResource.SetNamespace(randomString())
+		currentResource.SetName(randomString())
+		Expect(controllers.IsUnmanagedResource(&deployer.ResourceInfo{CurrentResource: currentResource})).To(BeTrue())
+
+		currentResource.SetLabels(map[string]string{deployer.ReferenceKindLabel: string(libsveltosv1beta1.ConfigMapReferencedResourceKind)})
+		Expect(controllers.IsUnmanagedResource(&deployer.ResourceInfo{CurrentResource: currentResource})).To(BeFalse())
+
+		currentResource.SetLabels(nil)
+		currentResource.SetOwnerReferences([]metav1.OwnerReference{
+			{APIVersion: configv1beta1.GroupVersion.String(), Kind: configv1beta1.ClusterProfileKind, Name: randomString()},
+		})
+		Expect(controllers.IsUnmanagedResource(&deployer.ResourceInfo{CurrentResource: currentResource})).To(BeFalse())
+	})
+
+	It("generateUnmanagedResourceReport reports a conflict only when adoption policy is Fail", func() {
+		resource := &configv1beta1.Resource{Kind: "ConfigMap", Namespace: randomString(), Name: randomString()}
+
+		report := controllers.GenerateUnmanagedReport(resource, configv1beta1.AdoptionPolicyFail)
+		Expect(report.Action).To(Equal(string(configv1beta1.ConflictResourceAction)))
+		Expect(report.Message).To(ContainSubstring(resource.Name))
+
+		report = controllers.GenerateUnmanagedReport(resource, configv1beta1.AdoptionPolicySkip)
+		Expect(report.Action).To(Equal(string(configv1beta1.NoResourceAction)))
+		Expect(report.Resource).To(Equal(*resource))
+	})
+
+	It("moveWebhookConfigurationsLast deploys webhook configurations after all other resources", func() {
+		newPolicy := func(apiVersion, kind string) *unstructured.Unstructured {
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion(apiVersion)
+			u.SetKind(kind)
+			u.SetName(randomString())
+			return u
+		}
+
+		validating := newPolicy("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration")
+		mutating := newPolicy("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration")
+		deployment := newPolicy("apps/v1", "Deployment")
+		service := newPolicy("v1", "Service")
+
+		result := controllers.MoveWebhooksLast(
+			[]*unstructured.Unstructured{validating, deployment, mutating, service})
+		Expect(result).To(Equal([]*unstructured.Unstructured{deployment, service, validating, mutating}))
+	})
+
+	It("isImmutableFieldError returns true only when an immutable field was changed", func() {
+		gk := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+		name := randomString()
+
+		err := apierrors.NewInvalid(gk, name, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "selector"), nil, "field is immutable"),
+		})
+		Expect(controllers.IsImmutableFieldError(err)).To(BeTrue())
+
+		err = apierrors.NewInvalid(gk, name, field.ErrorList{
+			field.Required(field.NewPath("spec", "template"), ""),
+		})
+		Expect(controllers.IsImmutableFieldError(err)).To(BeFalse())
+
+		Expect(controllers.IsImmutableFieldError(apierrors.NewNotFound(schema.GroupResource{}, name))).To(BeFalse())
+	})
+
+	It("shouldRecreateOnImmutableChange returns true only for listed features", func() {
+		clusterSummary := &configv1beta1.ClusterSummary{}
+		Expect(controllers.ShouldRecreateOnImmutable(clusterSummary, configv1beta1.FeatureResources)).To(BeFalse())
+
+		clusterSummary.Spec.ClusterProfileSpec.RecreateOnImmutableChange = []configv1beta1.FeatureID{
+			configv1beta1.FeatureKustomize,
+		}
+		Expect(controllers.ShouldRecreateOnImmutable(clusterSummary, configv1beta1.FeatureResources)).To(BeFalse())
+		Expect(controllers.ShouldRecreateOnImmutable(clusterSummary, configv1beta1.FeatureKustomize)).To(BeTrue())
+	})
+
+	It("deployContent recreates resources when an immutable field changes and RecreateOnImmutableChange is set", func() {
+		immutableConfigMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: %s
+immutable: true
+data:
+  key: %s`
+
+		configMapName := randomString()
+		content := fmt.Sprintf(immutableConfigMap, configMapName, namespace, randomString())
+		secret := createSecretWithPolicy(namespace, randomString(), content)
+		Expect(testEnv.Client.Create(context.TODO(), secret)).To(Succeed())
+		Expect(waitForObject(ctx, testEnv.Client, secret)).To(Succeed())
+		Expect(addTypeInformationToObject(testEnv.Scheme(), clusterSummary)).To(Succeed())
+
+		clusterSummary.Spec.ClusterProfileSpec.SyncMode = configv1beta1.SyncModeContinuous
+		resourceReports, err := controllers.DeployContent(context.TODO(), false,
+			testEnv.Config, testEnv.Client, secret, map[string]string{"policy": content}, clusterSummary, nil,
+			textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).To(BeNil())
+		validateResourceReports(resourceReports, 1, 0, 0, 0)
+
+		value := randomString()
+		content = fmt.Sprintf(immutableConfigMap, configMapName, namespace, value)
+
+		// Without RecreateOnImmutableChange server-side validation rejects the change
+		_, err = controllers.DeployContent(context.TODO(), false,
+			testEnv.Config, testEnv.Client, secret, map[string]string{"policy": content}, clusterSummary, nil,
+			textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).ToNot(BeNil())
+
+		clusterSummary.Spec.ClusterProfileSpec.RecreateOnImmutableChange = []configv1beta1.FeatureID{
+			configv1beta1.FeatureResources,
+		}
+		resourceReports, err = controllers.DeployContent(context.TODO(), false,
+			testEnv.Config, testEnv.Client, secret, map[string]string{"policy": content}, clusterSummary, nil,
+			textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).To(BeNil())
+		Expect(resourceReports).To(HaveLen(1))
+		Expect(resourceReports[0].Action).To(Equal(string(configv1beta1.RecreateResourceAction)))
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Eventually(func() bool {
+			err = testEnv.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: configMapName},
+				currentConfigMap)
+			return err == nil && currentConfigMap.Data["key"] == value
+		}, timeout, pollingInterval).Should(BeTrue())
+	})
+
+	It("getPolicyRefName appends version only for pinned ConfigMap/Secret references", func() {
+		clusterSummary := &configv1beta1.ClusterSummary{
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+			},
+		}
+
+		reference := &configv1beta1.PolicyRef{
+			Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			Name: "nginx",
+		}
+		name, err := controllers.GetPolicyRefName(clusterSummary, reference)
+		Expect(err).To(BeNil())
+		Expect(name).To(Equal("nginx"))
+
+		reference.Version = "v2"
+		name, err = controllers.GetPolicyRefName(clusterSummary, reference)
+		Expect(err).To(BeNil())
+		Expect(name).To(Equal("nginx-v2"))
+
+		reference.Name = "{{ .Cluster.metadata.name }}"
+		name, err = controllers.GetPolicyRefName(clusterSummary, reference)
+		Expect(err).To(BeNil())
+		Expect(name).To(Equal(clusterSummary.Spec.ClusterName + "-v2"))
+
+		reference.Kind = "GitRepository"
+		reference.Name = "flux-system"
+		name, err = controllers.GetPolicyRefName(clusterSummary, reference)
+		Expect(err).To(BeNil())
+		Expect(name).To(Equal("flux-system"))
+	})
 })
 
 // validateResourceReports validates that number of resourceResources with certain actions
@@ -0,0 +1,110 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/deployer"
+)
+
+var (
+	handlersMux sync.Mutex
+	// key: deployer request key; value: time handler started processing the request
+	inFlightHandlers = map[string]time.Time{}
+	// draining is set once the controller is shutting down. No new request is processed then.
+	draining bool
+)
+
+const (
+	drainPollInterval = time.Second
+)
+
+// trackHandler records that a deployer worker started processing a request.
+// Returned function must be invoked once request processing is done.
+// An error is returned, and request must not be processed, if controller is shutting down.
+func trackHandler(clusterNamespace, clusterName, applicant, featureID string,
+	clusterType libsveltosv1beta1.ClusterType, cleanup bool) (func(), error) {
+
+	key := deployer.GetKey(clusterNamespace, clusterName, applicant, featureID, clusterType, cleanup)
+
+	handlersMux.Lock()
+	defer handlersMux.Unlock()
+
+	if draining {
+		return nil, fmt.Errorf("controller is shutting down")
+	}
+	inFlightHandlers[key] = time.Now()
+
+	return func() {
+		handlersMux.Lock()
+		delete(inFlightHandlers, key)
+		handlersMux.Unlock()
+	}, nil
+}
+
+// DrainDeployer stops deployer workers from processing any new request and waits, up to
+// gracePeriod, for in-flight requests to complete. It returns the keys of requests still
+// in flight.
+// Unfinished requests (in flight or still queued) are deliberately not persisted. Deployer
+// results only live in memory, so features of those requests are never marked as provisioned
+// and the ClusterSummary reconciliation queues them again once the controller (or the next
+// leader) starts. Persisting them would only duplicate that.
+func DrainDeployer(gracePeriod time.Duration) []string {
+	handlersMux.Lock()
+	draining = true
+	handlersMux.Unlock()
+
+	deadline := time.Now().Add(gracePeriod)
+	for {
+		handlersMux.Lock()
+		inFlight := make([]string, 0, len(inFlightHandlers))
+		for key := range inFlightHandlers {
+			inFlight = append(inFlight, key)
+		}
+		handlersMux.Unlock()
+
+		if len(inFlight) == 0 || !time.Now().Before(deadline) {
+			sort.Strings(inFlight)
+			return inFlight
+		}
+		time.Sleep(drainPollInterval)
+	}
+}
+
+// DeployerHealthCheck returns a healthz.Checker reporting an error if a deployer worker has been
+// processing the same request for longer than maxDuration. That indicates workers might be wedged.
+func DeployerHealthCheck(maxDuration time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		handlersMux.Lock()
+		defer handlersMux.Unlock()
+
+		for key, start := range inFlightHandlers {
+			if elapsed := time.Since(start); elapsed > maxDuration {
+				return fmt.Errorf("deployer request %s in progress for %s", key, elapsed.Round(time.Second))
+			}
+		}
+		return nil
+	}
+}
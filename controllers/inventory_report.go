@@ -0,0 +1,217 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// InventoryPath is the path, on the diagnostics server, serving the add-on
+	// inventory across all managed clusters.
+	// Query parameters featureID, chartName, clusterNamespace and clusterName
+	// can be used to filter the inventory.
+	InventoryPath = "/inventory"
+)
+
+// HelmReleaseInventory reports a Helm release managed in a cluster
+type HelmReleaseInventory struct {
+	ReleaseNamespace string `json:"releaseNamespace"`
+	ReleaseName      string `json:"releaseName"`
+	ChartName        string `json:"chartName"`
+	ChartVersion     string `json:"chartVersion"`
+	Status           string `json:"status"`
+}
+
+// FeatureInventory reports a feature deployed by a profile in a cluster
+type FeatureInventory struct {
+	ProfileKind      string `json:"profileKind"`
+	ProfileNamespace string `json:"profileNamespace,omitempty"`
+	ProfileName      string `json:"profileName"`
+
+	ClusterNamespace string `json:"clusterNamespace"`
+	ClusterName      string `json:"clusterName"`
+	ClusterType      string `json:"clusterType"`
+
+	FeatureID string `json:"featureID"`
+
+	// Hash is the hash of the feature configuration currently deployed
+	Hash string `json:"hash,omitempty"`
+
+	Status          string       `json:"status,omitempty"`
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// HelmReleases is set only for the Helm feature
+	HelmReleases []HelmReleaseInventory `json:"helmReleases,omitempty"`
+}
+
+// InventoryFilter restricts the inventory. Empty fields match everything.
+type InventoryFilter struct {
+	FeatureID        string
+	ChartName        string
+	ClusterNamespace string
+	ClusterName      string
+}
+
+// getInventory returns, per cluster and profile, the features deployed along with their hash
+// and, for Helm, release versions. Inventory is built from ClusterSummary spec and status.
+func getInventory(ctx context.Context, c client.Client, filter *InventoryFilter) ([]FeatureInventory, error) {
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	listOptions := []client.ListOption{}
+	if filter.ClusterNamespace != "" {
+		listOptions = append(listOptions, client.InNamespace(filter.ClusterNamespace))
+	}
+	if err := c.List(ctx, clusterSummaries, listOptions...); err != nil {
+		return nil, err
+	}
+
+	inventory := make([]FeatureInventory, 0)
+	for i := range clusterSummaries.Items {
+		cs := &clusterSummaries.Items[i]
+		if filter.ClusterName != "" && cs.Spec.ClusterName != filter.ClusterName {
+			continue
+		}
+
+		kind := configv1beta1.ClusterProfileKind
+		name, ok := cs.Labels[ClusterProfileLabelName]
+		namespace := ""
+		if !ok {
+			kind = configv1beta1.ProfileKind
+			name, ok = cs.Labels[ProfileLabelName]
+			if !ok {
+				continue
+			}
+			namespace = cs.Namespace
+		}
+
+		for j := range cs.Status.FeatureSummaries {
+			fs := &cs.Status.FeatureSummaries[j]
+			if filter.FeatureID != "" && string(fs.FeatureID) != filter.FeatureID {
+				continue
+			}
+
+			featureInventory := FeatureInventory{
+				ProfileKind:      kind,
+				ProfileNamespace: namespace,
+				ProfileName:      name,
+				ClusterNamespace: cs.Spec.ClusterNamespace,
+				ClusterName:      cs.Spec.ClusterName,
+				ClusterType:      string(cs.Spec.ClusterType),
+				FeatureID:        string(fs.FeatureID),
+				Hash:             hex.EncodeToString(fs.Hash),
+				Status:           string(fs.Status),
+				LastAppliedTime:  fs.LastAppliedTime,
+			}
+			if fs.FeatureID == configv1beta1.FeatureHelm {
+				featureInventory.HelmReleases = getHelmReleasesInventory(cs, filter.ChartName)
+				if filter.ChartName != "" && len(featureInventory.HelmReleases) == 0 {
+					continue
+				}
+			} else if filter.ChartName != "" {
+				continue
+			}
+
+			inventory = append(inventory, featureInventory)
+		}
+	}
+
+	sort.Slice(inventory, func(i, j int) bool {
+		return getFeatureInventoryKey(&inventory[i]) < getFeatureInventoryKey(&inventory[j])
+	})
+
+	return inventory, nil
+}
+
+// getHelmReleasesInventory returns the Helm releases managed by ClusterSummary. If chartName is set,
+// only releases of that chart are returned.
+func getHelmReleasesInventory(clusterSummary *configv1beta1.ClusterSummary, chartName string,
+) []HelmReleaseInventory {
+
+	releases := make([]HelmReleaseInventory, 0)
+	for i := range clusterSummary.Spec.ClusterProfileSpec.HelmCharts {
+		chart := &clusterSummary.Spec.ClusterProfileSpec.HelmCharts[i]
+		if chartName != "" && chart.ChartName != chartName {
+			continue
+		}
+
+		release := HelmReleaseInventory{
+			ReleaseNamespace: chart.ReleaseNamespace,
+			ReleaseName:      chart.ReleaseName,
+			ChartName:        chart.ChartName,
+			ChartVersion:     chart.ChartVersion,
+		}
+		for j := range clusterSummary.Status.HelmReleaseSummaries {
+			summary := &clusterSummary.Status.HelmReleaseSummaries[j]
+			if summary.ReleaseNamespace == chart.ReleaseNamespace && summary.ReleaseName == chart.ReleaseName {
+				release.Status = string(summary.Status)
+				break
+			}
+		}
+		releases = append(releases, release)
+	}
+
+	return releases
+}
+
+func getFeatureInventoryKey(featureInventory *FeatureInventory) string {
+	return fmt.Sprintf("%s/%s:%s:%s/%s:%s", featureInventory.ClusterNamespace, featureInventory.ClusterName,
+		featureInventory.ProfileKind, featureInventory.ProfileNamespace, featureInventory.ProfileName,
+		featureInventory.FeatureID)
+}
+
+// NewInventoryHandler returns an http.Handler serving, in JSON, the add-on inventory
+// across all managed clusters
+func NewInventoryHandler(c client.Client, logger logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := &InventoryFilter{
+			FeatureID:        query.Get("featureID"),
+			ChartName:        query.Get("chartName"),
+			ClusterNamespace: query.Get("clusterNamespace"),
+			ClusterName:      query.Get("clusterName"),
+		}
+
+		inventory, err := getInventory(r.Context(), c, filter)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to build inventory: %v", err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(inventory); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to write inventory: %v", err))
+		}
+	})
+}
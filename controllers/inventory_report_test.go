@@ -0,0 +1,128 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Inventory", func() {
+	var clusterSummary *configv1beta1.ClusterSummary
+	var hash []byte
+
+	BeforeEach(func() {
+		hash = []byte(randomString())
+		clusterSummary = &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      randomString(),
+				Namespace: randomString(),
+				Labels: map[string]string{
+					controllers.ClusterProfileLabelName: randomString(),
+				},
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+				ClusterProfileSpec: configv1beta1.Spec{
+					HelmCharts: []configv1beta1.HelmChart{
+						{
+							RepositoryURL:    randomString(),
+							RepositoryName:   randomString(),
+							ChartName:        "kyverno/kyverno",
+							ChartVersion:     "v3.2.5",
+							ReleaseName:      "kyverno",
+							ReleaseNamespace: "kyverno",
+						},
+					},
+				},
+			},
+			Status: configv1beta1.ClusterSummaryStatus{
+				FeatureSummaries: []configv1beta1.FeatureSummary{
+					{FeatureID: configv1beta1.FeatureHelm, Status: configv1beta1.FeatureStatusProvisioned, Hash: hash},
+					{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusProvisioned},
+				},
+				HelmReleaseSummaries: []configv1beta1.HelmChartSummary{
+					{ReleaseName: "kyverno", ReleaseNamespace: "kyverno", Status: configv1beta1.HelmChartStatusManaging},
+				},
+			},
+		}
+	})
+
+	It("getInventory reports features and helm releases deployed per cluster", func() {
+		initObjects := []client.Object{clusterSummary}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).Build()
+
+		inventory, err := controllers.GetInventory(context.TODO(), c, &controllers.InventoryFilter{})
+		Expect(err).To(BeNil())
+		Expect(len(inventory)).To(Equal(2))
+
+		inventory, err = controllers.GetInventory(context.TODO(), c,
+			&controllers.InventoryFilter{FeatureID: string(configv1beta1.FeatureHelm)})
+		Expect(err).To(BeNil())
+		Expect(len(inventory)).To(Equal(1))
+		Expect(inventory[0].ClusterName).To(Equal(clusterSummary.Spec.ClusterName))
+		Expect(inventory[0].ProfileKind).To(Equal(configv1beta1.ClusterProfileKind))
+		Expect(inventory[0].Hash).To(Equal(hex.EncodeToString(hash)))
+		Expect(len(inventory[0].HelmReleases)).To(Equal(1))
+		Expect(inventory[0].HelmReleases[0].ChartVersion).To(Equal("v3.2.5"))
+		Expect(inventory[0].HelmReleases[0].Status).To(Equal(string(configv1beta1.HelmChartStatusManaging)))
+
+		inventory, err = controllers.GetInventory(context.TODO(), c,
+			&controllers.InventoryFilter{ChartName: randomString()})
+		Expect(err).To(BeNil())
+		Expect(inventory).To(BeEmpty())
+	})
+
+	It("NewInventoryHandler serves the inventory in JSON", func() {
+		initObjects := []client.Object{clusterSummary}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).Build()
+
+		recorder := httptest.NewRecorder()
+		handler := controllers.NewInventoryHandler(c, logr.Discard())
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+			controllers.InventoryPath+"?chartName=kyverno/kyverno", http.NoBody))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		inventory := make([]controllers.FeatureInventory, 0)
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &inventory)).To(Succeed())
+		Expect(len(inventory)).To(Equal(1))
+		Expect(inventory[0].FeatureID).To(Equal(string(configv1beta1.FeatureHelm)))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, controllers.InventoryPath, http.NoBody))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
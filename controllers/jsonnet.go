@@ -0,0 +1,143 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-jsonnet"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// jsonnetAnnotation, when set on a referenced ConfigMap/Secret (or Flux Source),
+	// indicates its content is Jsonnet that must be evaluated before deployment
+	jsonnetAnnotation = "projectsveltos.io/jsonnet"
+
+	// jsonnetClusterExtVar is the external variable containing the cluster information
+	// (kind, namespace, name, labels and annotations).
+	// It can be accessed in Jsonnet with std.extVar('cluster')
+	jsonnetClusterExtVar = "cluster"
+
+	jsonnetExtension = ".jsonnet"
+)
+
+func isJsonnet(referencedObject client.Object) bool {
+	annotations := referencedObject.GetAnnotations()
+	if annotations != nil {
+		if _, ok := annotations[jsonnetAnnotation]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderJsonnet evaluates every .jsonnet entry in data. Any other entry (.libsonnet
+// and .json ones) is made available to those via import, so a library bundle can be
+// vendored next to the Jsonnet using it.
+// Each .jsonnet entry must evaluate to a resource, an array of resources or a
+// List with items. Evaluated resources are returned.
+func renderJsonnet(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
+	data map[string]string, logger logr.Logger) ([]*unstructured.Unstructured, error) {
+
+	cluster, err := getTemplateResourceCluster(ctx, clusterSummary)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterJSON, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries := make(map[string]jsonnet.Contents, len(data))
+	entries := make([]string, 0, len(data))
+	for k := range data {
+		libraries[k] = jsonnet.MakeContents(data[k])
+		if filepath.Ext(k) == jsonnetExtension {
+			entries = append(entries, k)
+		}
+	}
+	// Evaluate in a consistent order
+	sort.Strings(entries)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{Data: libraries})
+	vm.ExtCode(jsonnetClusterExtVar, string(clusterJSON))
+
+	resources := make([]*unstructured.Unstructured, 0)
+	for i := range entries {
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("evaluating jsonnet %s", entries[i]))
+		output, err := vm.EvaluateAnonymousSnippet(entries[i], data[entries[i]])
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to evaluate jsonnet %s: %v", entries[i], err))
+			return nil, err
+		}
+
+		current, err := getResourcesFromJSON(output)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get resources from jsonnet %s: %v", entries[i], err))
+			return nil, err
+		}
+		resources = append(resources, current...)
+	}
+
+	return resources, nil
+}
+
+// getResourcesFromJSON converts the JSON output of a Jsonnet/CUE evaluation to resources
+func getResourcesFromJSON(output string) ([]*unstructured.Unstructured, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return nil, err
+	}
+
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		u := &unstructured.Unstructured{Object: v}
+		if !u.IsList() {
+			return []*unstructured.Unstructured{u}, nil
+		}
+		items, _, _ = unstructured.NestedSlice(v, "items")
+	default:
+		return nil, fmt.Errorf("expected an object or an array, got %T", value)
+	}
+
+	resources := make([]*unstructured.Unstructured, 0, len(items))
+	for i := range items {
+		object, ok := items[i].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("array element %d is not an object", i)
+		}
+		resources = append(resources, &unstructured.Unstructured{Object: object})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Jsonnet", func() {
+	It("renderJsonnet evaluates jsonnet entries using vendored libraries and cluster information", func() {
+		clusterSummary := &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+			},
+		}
+
+		data := map[string]string{
+			"lib.libsonnet": `{
+  configMap(name, namespace):: {
+    apiVersion: 'v1',
+    kind: 'ConfigMap',
+    metadata: { name: name, namespace: namespace },
+  },
+}`,
+			"main.jsonnet": `local lib = import 'lib.libsonnet';
+local cluster = std.extVar('cluster');
+[
+  lib.configMap(cluster.metadata.name, 'default'),
+  lib.configMap('common', cluster.metadata.namespace),
+]`,
+		}
+
+		resources, err := controllers.RenderJsonnet(context.TODO(), clusterSummary, data, logr.Discard())
+		Expect(err).To(BeNil())
+		Expect(len(resources)).To(Equal(2))
+		Expect(resources[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(resources[0].GetName()).To(Equal(clusterSummary.Spec.ClusterName))
+		Expect(resources[0].GetNamespace()).To(Equal("default"))
+		Expect(resources[1].GetName()).To(Equal("common"))
+		Expect(resources[1].GetNamespace()).To(Equal(clusterSummary.Spec.ClusterNamespace))
+	})
+
+	It("getResourcesFromJSON accepts a single resource or a List", func() {
+		resources, err := controllers.GetResourcesFromJSON(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "foo"}}`)
+		Expect(err).To(BeNil())
+		Expect(len(resources)).To(Equal(1))
+		Expect(resources[0].GetName()).To(Equal("foo"))
+
+		resources, err = controllers.GetResourcesFromJSON(`{"apiVersion": "v1", "kind": "List", "items": [
+			{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "foo"}},
+			{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "bar"}}]}`)
+		Expect(err).To(BeNil())
+		Expect(len(resources)).To(Equal(2))
+		Expect(resources[1].GetName()).To(Equal("bar"))
+
+		_, err = controllers.GetResourcesFromJSON(`"foo"`)
+		Expect(err).ToNot(BeNil())
+	})
+})
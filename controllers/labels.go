@@ -32,6 +32,10 @@ const (
 	// ProfileLabelName is added to all ClusterSummary instances created
 	// by a Profile instance
 	ProfileLabelName = "projectsveltos.io/profile-name"
+
+	// PolicyValidationLabelName, when set to "true" on a ConfigMap, opts the ConfigMap in
+	// for content validation by the policy validating webhook
+	PolicyValidationLabelName = "projectsveltos.io/validate-policy"
 )
 
 // addLabel adds label to an object
@@ -29,6 +29,8 @@ var (
 	managementClusterClient client.Client
 	managementClusterConfig *rest.Config
 	driftdetectionConfigMap string
+
+	deploymentPoliciesConfigMap string
 )
 
 func SetManagementClusterAccess(c client.Client, config *rest.Config) {
@@ -40,6 +42,10 @@ func SetDriftdetectionConfigMap(name string) {
 	driftdetectionConfigMap = name
 }
 
+func SetDeploymentPoliciesConfigMap(name string) {
+	deploymentPoliciesConfigMap = name
+}
+
 func getManagementClusterConfig() *rest.Config {
 	return managementClusterConfig
 }
@@ -52,6 +58,10 @@ func getDriftDetectionConfigMap() string {
 	return driftdetectionConfigMap
 }
 
+func getDeploymentPoliciesConfigMap() string {
+	return deploymentPoliciesConfigMap
+}
+
 func collectDriftDetectionConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error) {
 	c := getManagementClusterClient()
 	configMap := &corev1.ConfigMap{}
@@ -64,12 +64,23 @@ var (
 		},
 		[]string{"cluster_type", "cluster_namespace", "cluster_name", "feature"},
 	)
+
+	featureNotConvergedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "projectsveltos",
+			Name:      "feature_not_provisioned_since_timestamp_seconds",
+			Help: "Unix time since when a feature has been in Provisioning or Failed state. " +
+				"Series is removed once feature is provisioned",
+		},
+		[]string{"cluster_type", "cluster_namespace", "cluster_name", "clustersummary", "feature"},
+	)
 )
 
 //nolint:gochecknoinits // forced pattern, can't workaround
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(programResourceDurationHistogram, programChartDurationHistogram, reconciliationCounter, driftCounter)
+	metrics.Registry.MustRegister(programResourceDurationHistogram, programChartDurationHistogram, reconciliationCounter, driftCounter,
+		featureNotConvergedGauge)
 }
 
 func newResourceHistogram(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
@@ -192,3 +203,15 @@ func trackDrifts(clusterNamespace, clusterName, featureID, clusterType string, l
 	logger.V(logs.LogVerbose).Info(fmt.Sprintf("Tracking drifts for %s %s/%s %s",
 		clusterType, clusterNamespace, clusterName, featureID))
 }
+
+func trackFeatureNotConverged(clusterSummary *configv1beta1.ClusterSummary, featureID configv1beta1.FeatureID,
+	since time.Time) {
+
+	featureNotConvergedGauge.WithLabelValues(string(clusterSummary.Spec.ClusterType), clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Name, string(featureID)).Set(float64(since.Unix()))
+}
+
+func deleteFeatureNotConvergedMetric(clusterSummary *configv1beta1.ClusterSummary, featureID configv1beta1.FeatureID) {
+	featureNotConvergedGauge.DeleteLabelValues(string(clusterSummary.Spec.ClusterType), clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Name, string(featureID))
+}
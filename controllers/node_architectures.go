@@ -0,0 +1,129 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// supportedArchitectures contains the node architectures Sveltos component images
+// (drift-detection-manager) are published for
+var supportedArchitectures = map[string]bool{
+	"amd64": true,
+	"arm64": true,
+}
+
+// getNodeArchitectures returns the architectures of the nodes in the cluster. Only node metadata
+// is listed, one page at a time, and the kubernetes.io/arch label is used.
+func getNodeArchitectures(ctx context.Context, restConfig *rest.Config) ([]string, error) {
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	architectures := map[string]bool{}
+	options := metav1.ListOptions{Limit: listPageSize}
+	for {
+		nodes, err := metadataClient.Resource(corev1.SchemeGroupVersion.WithResource("nodes")).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+
+		addArchitectures(architectures, nodes.Items)
+
+		options.Continue = nodes.GetContinue()
+		if options.Continue == "" {
+			break
+		}
+	}
+
+	result := make([]string, 0, len(architectures))
+	for arch := range architectures {
+		result = append(result, arch)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// addArchitectures adds to architectures the kubernetes.io/arch label value of each node.
+// Nodes without such label are ignored.
+func addArchitectures(architectures map[string]bool, nodes []metav1.PartialObjectMetadata) {
+	for i := range nodes {
+		if arch := nodes[i].Labels[corev1.LabelArchStable]; arch != "" {
+			architectures[arch] = true
+		}
+	}
+}
+
+// getArchitecturePatches returns the patches needed for drift-detection-manager to be scheduled
+// only on nodes with a supported architecture. No patch is returned when all nodes have a supported
+// architecture. An error is returned when no node does.
+func getArchitecturePatches(architectures []string) ([]libsveltosv1beta1.Patch, error) {
+	supported := make([]string, 0, len(architectures))
+	for i := range architectures {
+		if supportedArchitectures[architectures[i]] {
+			supported = append(supported, architectures[i])
+		}
+	}
+
+	if len(supported) == len(architectures) {
+		return nil, nil
+	}
+
+	if len(supported) == 0 {
+		return nil, fmt.Errorf("drift-detection-manager cannot run on any node. Node architectures: %s",
+			strings.Join(architectures, ", "))
+	}
+
+	patch := fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: drift-detection-manager
+spec:
+  template:
+    spec:
+      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: %s
+                operator: In
+                values: [%s]`, corev1.LabelArchStable, strings.Join(supported, ", "))
+
+	return []libsveltosv1beta1.Patch{
+		{
+			Target: &libsveltosv1beta1.PatchSelector{
+				Group:   "apps",
+				Version: "v1",
+				Kind:    "Deployment",
+				Name:    "drift-detection-manager",
+			},
+			Patch: patch,
+		},
+	}, nil
+}
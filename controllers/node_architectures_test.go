@@ -0,0 +1,70 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectsveltos/addon-controller/controllers"
+)
+
+var _ = Describe("Node architectures", func() {
+	It("addArchitectures collects node architectures from kubernetes.io/arch label", func() {
+		nodes := []metav1.PartialObjectMetadata{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   randomString(),
+					Labels: map[string]string{corev1.LabelArchStable: "arm64"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: randomString()},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   randomString(),
+					Labels: map[string]string{corev1.LabelArchStable: "amd64"},
+				},
+			},
+		}
+
+		architectures := map[string]bool{}
+		controllers.AddArchitectures(architectures, nodes[:2])
+		controllers.AddArchitectures(architectures, nodes[2:])
+		Expect(architectures).To(Equal(map[string]bool{"amd64": true, "arm64": true}))
+	})
+
+	It("getArchitecturePatches restricts drift-detection-manager to supported architectures", func() {
+		patches, err := controllers.GetArchitecturePatches([]string{"amd64", "arm64"})
+		Expect(err).To(BeNil())
+		Expect(patches).To(BeNil())
+
+		patches, err = controllers.GetArchitecturePatches([]string{"arm64", "s390x"})
+		Expect(err).To(BeNil())
+		Expect(len(patches)).To(Equal(1))
+		Expect(patches[0].Target.Kind).To(Equal("Deployment"))
+		Expect(patches[0].Patch).To(ContainSubstring(corev1.LabelArchStable))
+		Expect(patches[0].Patch).To(ContainSubstring("values: [arm64]"))
+
+		_, err = controllers.GetArchitecturePatches([]string{"s390x"})
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+//nolint:lll // marker
+//+kubebuilder:webhook:path=/validate--v1-configmap,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=configmaps,verbs=create;update,versions=v1,name=vconfigmap.projectsveltos.io,admissionReviewVersions=v1
+
+// PolicyConfigMapValidator validates the content of ConfigMaps opted in for validation
+// via the PolicyValidationLabelName label. Each ConfigMap Data entry must contain valid
+// Kubernetes resources. Templates are not validated as those can only be parsed once instantiated.
+type PolicyConfigMapValidator struct {
+	Logger logr.Logger
+}
+
+var _ admission.CustomValidator = &PolicyConfigMapValidator{}
+
+// SetupWebhookWithManager registers the validating webhook for ConfigMaps
+func (v *PolicyConfigMapValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.ConfigMap{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates the ConfigMap content on creation
+func (v *PolicyConfigMapValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate validates the ConfigMap content on update
+func (v *PolicyConfigMapValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete always allows deletion
+func (v *PolicyConfigMapValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PolicyConfigMapValidator) validate(obj runtime.Object) error {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return fmt.Errorf("expected a ConfigMap but got a %T", obj)
+	}
+
+	if configMap.Labels[PolicyValidationLabelName] != "true" {
+		return nil
+	}
+
+	if instantiateTemplate(configMap, v.Logger) || isJsonnet(configMap) || isCUE(configMap) {
+		return nil
+	}
+
+	for k := range configMap.Data {
+		if _, err := getUnstructured([]byte(configMap.Data[k]), logr.Discard()); err != nil {
+			v.Logger.V(logs.LogDebug).Info(fmt.Sprintf("ConfigMap %s/%s key %s contains invalid resources: %v",
+				configMap.Namespace, configMap.Name, k, err))
+			return fmt.Errorf("key %s does not contain valid Kubernetes resources: %w", k, err)
+		}
+	}
+
+	return nil
+}
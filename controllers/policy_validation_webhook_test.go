@@ -0,0 +1,84 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/textlogger"
+
+	"github.com/projectsveltos/addon-controller/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+const (
+	validPolicy = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: validated`
+
+	invalidPolicy = `kind: [`
+)
+
+var _ = Describe("Policy validation webhook", func() {
+	var validator *controllers.PolicyConfigMapValidator
+
+	BeforeEach(func() {
+		validator = &controllers.PolicyConfigMapValidator{Logger: textlogger.NewLogger(textlogger.NewConfig())}
+	})
+
+	It("ValidateCreate rejects labeled ConfigMaps with malformed content", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+				Labels:    map[string]string{controllers.PolicyValidationLabelName: "true"},
+			},
+			Data: map[string]string{"valid": validPolicy, "invalid": invalidPolicy},
+		}
+
+		_, err := validator.ValidateCreate(context.TODO(), configMap)
+		Expect(err).ToNot(BeNil())
+
+		delete(configMap.Data, "invalid")
+		_, err = validator.ValidateCreate(context.TODO(), configMap)
+		Expect(err).To(BeNil())
+	})
+
+	It("ValidateUpdate ignores ConfigMaps not opted in and templates", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+			Data: map[string]string{"invalid": invalidPolicy},
+		}
+
+		_, err := validator.ValidateUpdate(context.TODO(), configMap, configMap)
+		Expect(err).To(BeNil())
+
+		configMap.Labels = map[string]string{controllers.PolicyValidationLabelName: "true"}
+		configMap.Annotations = map[string]string{libsveltosv1beta1.PolicyTemplateAnnotation: "ok"}
+		_, err = validator.ValidateUpdate(context.TODO(), configMap, configMap)
+		Expect(err).To(BeNil())
+	})
+})
@@ -434,18 +434,25 @@ func updateClusterSummary(ctx context.Context, c client.Client, profileScope *sc
 		return err
 	}
 
+	// Annotation with deployed features hash is managed by ClusterSummary controller
+	annotations, sameAnnotations := preserveDeployedFeaturesAnnotation(clusterSummary.Annotations,
+		profileScope.Profile.GetAnnotations())
+	// After a clusterctl move, ClusterProfile/Profile UID changes. Owner needs to be updated.
+	isOwner := hasOwnerWithUID(clusterSummary, profileScope.Profile.GetUID())
+
 	if reflect.DeepEqual(profileScope.GetSpec(), clusterSummary.Spec.ClusterProfileSpec) &&
-		reflect.DeepEqual(profileScope.Profile.GetAnnotations(), clusterSummary.Annotations) {
+		sameAnnotations && isOwner {
 		// Nothing has changed
 		return nil
 	}
 
-	clusterSummary.Annotations = profileScope.Profile.GetAnnotations()
+	clusterSummary.OwnerReferences = util.EnsureOwnerRef(clusterSummary.OwnerReferences,
+		getProfileOwnerReference(profileScope))
 	clusterSummary.Spec.ClusterProfileSpec = *profileScope.GetSpec()
 	clusterSummary.Spec.ClusterType = clusterproxy.GetClusterType(cluster)
 	addClusterSummaryLabels(clusterSummary, profileScope, cluster)
 	// Copy annotation. Paused annotation might be set on ClusterProfile.
-	clusterSummary.Annotations = profileScope.Profile.GetAnnotations()
+	clusterSummary.Annotations = annotations
 	return c.Update(ctx, clusterSummary)
 }
 
@@ -477,6 +484,16 @@ func addClusterSummaryLabels(clusterSummary *configv1beta1.ClusterSummary, profi
 	}
 }
 
+// getProfileOwnerReference returns the OwnerReference for ClusterProfile/Profile
+func getProfileOwnerReference(profileScope *scope.ProfileScope) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: configv1beta1.GroupVersion.String(),
+		Kind:       profileScope.Profile.GetObjectKind().GroupVersionKind().Kind,
+		Name:       profileScope.Profile.GetName(),
+		UID:        profileScope.Profile.GetUID(),
+	}
+}
+
 // createClusterSummary creates ClusterSummary given a ClusterProfile and a matching Sveltos/Cluster
 func createClusterSummary(ctx context.Context, c client.Client, profileScope *scope.ProfileScope,
 	cluster *corev1.ObjectReference) error {
@@ -489,12 +506,7 @@ func createClusterSummary(ctx context.Context, c client.Client, profileScope *sc
 			Name:      clusterSummaryName,
 			Namespace: cluster.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: configv1beta1.GroupVersion.String(),
-					Kind:       profileScope.Profile.GetObjectKind().GroupVersionKind().Kind,
-					Name:       profileScope.Profile.GetName(),
-					UID:        profileScope.Profile.GetUID(),
-				},
+				getProfileOwnerReference(profileScope),
 			},
 			Annotations: profileScope.Profile.GetAnnotations(),
 		},
@@ -522,12 +534,7 @@ func updateClusterSummaries(ctx context.Context, c client.Client, profileScope *
 	currentHash := getProfileSpecHash(profileScope)
 
 	// Remove Status.UpdatedClusters if hash is different
-	if !reflect.DeepEqual(profileScope.GetStatus().UpdatedClusters.Hash, currentHash) {
-		profileScope.GetStatus().UpdatedClusters = configv1beta1.Clusters{
-			Hash:     currentHash,
-			Clusters: []corev1.ObjectReference{},
-		}
-	}
+	profileScope.StartRollout(currentHash)
 
 	// Remove clusters non matching anynmore from UpdatedClusters and UpdatingClusters
 	reviseUpdatedAndUpdatingClusters(profileScope)
@@ -595,14 +602,8 @@ func updateClusterSummaries(ctx context.Context, c client.Client, profileScope *
 			return err
 		}
 
-		if !updatingClusters.Has(&cluster) {
-			updatingClusters.Insert(&cluster)
-			profileScope.GetStatus().UpdatingClusters.Clusters =
-				append(profileScope.GetStatus().UpdatingClusters.Clusters,
-					cluster)
-		}
-
-		profileScope.GetStatus().UpdatingClusters.Hash = currentHash
+		updatingClusters.Insert(&cluster)
+		profileScope.AddUpdatingCluster(&cluster, currentHash)
 	}
 
 	if skippedUpdate {
@@ -611,8 +612,7 @@ func updateClusterSummaries(ctx context.Context, c client.Client, profileScope *
 	}
 
 	// If all ClusterSummaries have been updated, reset Updated and Updating
-	profileScope.GetStatus().UpdatedClusters = configv1beta1.Clusters{}
-	profileScope.GetStatus().UpdatingClusters = configv1beta1.Clusters{}
+	profileScope.CompleteRollout()
 
 	return nil
 }
@@ -864,35 +864,7 @@ func getProfileSpecHash(profileScope *scope.ProfileScope) []byte {
 // - UpdatedClusters represents list of matching clusters already updated since last ClusterProfile/Profile change
 // - UpdatingClusters represents list of matching clusters being updated since last ClusterProfile/Profile change
 func reviseUpdatedAndUpdatingClusters(profileScope *scope.ProfileScope) {
-	matchingCluster := libsveltosset.Set{}
-	for i := range profileScope.GetStatus().MatchingClusterRefs {
-		cluster := profileScope.GetStatus().MatchingClusterRefs[i]
-		matchingCluster.Insert(&cluster)
-	}
-
-	updatedClusters := &libsveltosset.Set{}
-	currentUpdatedClusters := make([]corev1.ObjectReference, 0, len(profileScope.GetStatus().UpdatedClusters.Clusters))
-	for i := range profileScope.GetStatus().UpdatedClusters.Clusters {
-		cluster := &profileScope.GetStatus().UpdatedClusters.Clusters[i]
-		if matchingCluster.Has(cluster) {
-			currentUpdatedClusters = append(currentUpdatedClusters, *cluster)
-			updatedClusters.Insert(cluster)
-		}
-	}
-
-	profileScope.GetStatus().UpdatedClusters.Clusters = currentUpdatedClusters
-
-	updatingClusters := &libsveltosset.Set{}
-	currentUpdatingClusters := make([]corev1.ObjectReference, 0, len(profileScope.GetStatus().UpdatingClusters.Clusters))
-	for i := range profileScope.GetStatus().UpdatingClusters.Clusters {
-		cluster := &profileScope.GetStatus().UpdatingClusters.Clusters[i]
-		if matchingCluster.Has(cluster) {
-			currentUpdatingClusters = append(currentUpdatingClusters, *cluster)
-			updatingClusters.Insert(cluster)
-		}
-	}
-
-	profileScope.GetStatus().UpdatingClusters.Clusters = currentUpdatingClusters
+	profileScope.RemoveNonMatchingClusters()
 }
 
 func getMaxUpdate(profileScope *scope.ProfileScope) int32 {
@@ -1059,6 +1031,7 @@ func reconcileNormalCommon(ctx context.Context, c client.Client, profileScope *s
 		return err
 	}
 
+	profileScope.SetObservedGeneration()
 	return nil
 }
 
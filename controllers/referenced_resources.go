@@ -0,0 +1,320 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/scope"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// isProtectedKind returns true if referenced resources of this Kind are protected from deletion
+func isProtectedKind(kind string) bool {
+	return kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) ||
+		kind == string(libsveltosv1beta1.SecretReferencedResourceKind)
+}
+
+// getReferencedObject returns an empty ConfigMap/Secret for the given reference
+func getReferencedObject(ref *corev1.ObjectReference) client.Object {
+	if ref.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
+		return &corev1.Secret{}
+	}
+	return &corev1.ConfigMap{}
+}
+
+const (
+	// referencedResourceIndex indexes ClusterSummaries by the ConfigMaps/Secrets they reference
+	referencedResourceIndex = "referencedResources"
+)
+
+// getReferencedResourceIndexKey returns the referencedResourceIndex key for a ConfigMap/Secret.
+// Only core/v1 kinds are protected, so apiVersion is not taken from the reference (objects
+// returned by List carry no TypeMeta).
+func getReferencedResourceIndexKey(kind, namespace, name string) string {
+	return getEntryKey(corev1.SchemeGroupVersion.String(), kind, namespace, name)
+}
+
+// indexReferencedResources returns, for a ClusterSummary, the index keys of all ConfigMaps/Secrets
+// it is referencing
+func (r *ClusterSummaryReconciler) indexReferencedResources(o client.Object) []string {
+	clusterSummary, ok := o.(*configv1beta1.ClusterSummary)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterSummary but got a %T", o))
+	}
+
+	references, err := r.getCurrentReferences(&scope.ClusterSummaryScope{ClusterSummary: clusterSummary})
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0)
+	for _, ref := range references.Items() {
+		if isProtectedKind(ref.Kind) {
+			keys = append(keys, getReferencedResourceIndexKey(ref.Kind, ref.Namespace, ref.Name))
+		}
+	}
+	return keys
+}
+
+// protectReferencedResources adds the ReferencedResourceFinalizer to all ConfigMaps/Secrets
+// currently referenced by the ClusterSummary. Then ConfigMaps/Secrets carrying the finalizer but
+// not referenced by any ClusterSummary anymore are released.
+// Nothing is done when protection is disabled (releaseAllReferencedResources takes care, at
+// startup, of ConfigMaps/Secrets protected while it was enabled).
+func (r *ClusterSummaryReconciler) protectReferencedResources(ctx context.Context,
+	clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
+
+	if !r.ProtectReferencedResources {
+		return nil
+	}
+
+	references, err := r.getCurrentReferences(clusterSummaryScope)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range references.Items() {
+		if !isProtectedKind(ref.Kind) {
+			continue
+		}
+		tmpRef := ref
+		if err := r.addReferencedResourceFinalizer(ctx, &tmpRef, logger); err != nil {
+			return err
+		}
+	}
+
+	return r.releaseUnreferencedResources(ctx, nil, logger)
+}
+
+// releaseReferencedResources removes the ReferencedResourceFinalizer from all ConfigMaps/Secrets
+// not referenced by any ClusterSummary other than the one being deleted.
+func (r *ClusterSummaryReconciler) releaseReferencedResources(ctx context.Context,
+	clusterSummary *configv1beta1.ClusterSummary, logger logr.Logger) error {
+
+	if !r.ProtectReferencedResources {
+		return nil
+	}
+
+	return r.releaseUnreferencedResources(ctx, clusterSummary, logger)
+}
+
+// releaseUnreferencedResources lists ConfigMaps/Secrets carrying the ReferencedResourceLabel
+// and removes the ReferencedResourceFinalizer from the ones not referenced by any ClusterSummary
+// (excluded and ClusterSummaries marked for deletion are ignored).
+func (r *ClusterSummaryReconciler) releaseUnreferencedResources(ctx context.Context,
+	excluded *configv1beta1.ClusterSummary, logger logr.Logger) error {
+
+	listOptions := []client.ListOption{
+		client.HasLabels{configv1beta1.ReferencedResourceLabel},
+		client.UnsafeDisableDeepCopy,
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, configMaps, listOptions...); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		if err := r.releaseIfUnreferenced(ctx, &configMaps.Items[i],
+			string(libsveltosv1beta1.ConfigMapReferencedResourceKind), excluded, logger); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secrets, listOptions...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if err := r.releaseIfUnreferenced(ctx, &secrets.Items[i],
+			string(libsveltosv1beta1.SecretReferencedResourceKind), excluded, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseAllReferencedResources runs once at startup. All ConfigMaps/Secrets are listed (this
+// also covers the ones protected before ReferencedResourceLabel was introduced):
+// - when protection is disabled, ReferencedResourceFinalizer and ReferencedResourceLabel are removed;
+// - when protection is enabled, ReferencedResourceLabel is added where missing, so following
+// releases, which list by label, consider those as well.
+func (r *ClusterSummaryReconciler) releaseAllReferencedResources(ctx context.Context, logger logr.Logger) error {
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, configMaps, client.UnsafeDisableDeepCopy); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		if err := r.sweepReferencedResource(ctx, &configMaps.Items[i],
+			string(libsveltosv1beta1.ConfigMapReferencedResourceKind), logger); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secrets, client.UnsafeDisableDeepCopy); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if err := r.sweepReferencedResource(ctx, &secrets.Items[i],
+			string(libsveltosv1beta1.SecretReferencedResourceKind), logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sweepReferencedResource is invoked by releaseAllReferencedResources for each ConfigMap/Secret.
+// obj comes from a list done with UnsafeDisableDeepCopy so it is copied before being modified.
+func (r *ClusterSummaryReconciler) sweepReferencedResource(ctx context.Context, obj client.Object,
+	kind string, logger logr.Logger) error {
+
+	if !controllerutil.ContainsFinalizer(obj, configv1beta1.ReferencedResourceFinalizer) {
+		return nil
+	}
+
+	if !r.ProtectReferencedResources {
+		return r.removeReferencedResourceFinalizer(ctx, obj, kind, logger)
+	}
+
+	if _, ok := obj.GetLabels()[configv1beta1.ReferencedResourceLabel]; ok {
+		return nil
+	}
+
+	current := obj.DeepCopyObject().(client.Object)
+	patch := client.MergeFrom(obj)
+	addReferencedResourceLabel(current)
+	err := r.Client.Patch(ctx, current, patch)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// releaseIfUnreferenced removes the ReferencedResourceFinalizer from obj if no ClusterSummary,
+// other than the excluded one, is referencing it.
+// obj comes from a list done with UnsafeDisableDeepCopy so it is copied before being modified.
+func (r *ClusterSummaryReconciler) releaseIfUnreferenced(ctx context.Context, obj client.Object, kind string,
+	excluded *configv1beta1.ClusterSummary, logger logr.Logger) error {
+
+	ref := &corev1.ObjectReference{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	referenced, err := r.isResourceReferenced(ctx, ref, excluded)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return nil
+	}
+
+	return r.removeReferencedResourceFinalizer(ctx, obj, kind, logger)
+}
+
+// removeReferencedResourceFinalizer removes ReferencedResourceFinalizer and ReferencedResourceLabel
+// from obj. obj is not modified.
+func (r *ClusterSummaryReconciler) removeReferencedResourceFinalizer(ctx context.Context, obj client.Object,
+	kind string, logger logr.Logger) error {
+
+	logger.V(logs.LogDebug).Info(fmt.Sprintf("%s %s/%s is not protected anymore",
+		kind, obj.GetNamespace(), obj.GetName()))
+	current := obj.DeepCopyObject().(client.Object)
+	patch := client.MergeFrom(obj)
+	controllerutil.RemoveFinalizer(current, configv1beta1.ReferencedResourceFinalizer)
+	labels := current.GetLabels()
+	delete(labels, configv1beta1.ReferencedResourceLabel)
+	current.SetLabels(labels)
+	err := r.Client.Patch(ctx, current, patch)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func addReferencedResourceLabel(obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[configv1beta1.ReferencedResourceLabel] = "true"
+	obj.SetLabels(labels)
+}
+
+func (r *ClusterSummaryReconciler) addReferencedResourceFinalizer(ctx context.Context,
+	ref *corev1.ObjectReference, logger logr.Logger) error {
+
+	obj := getReferencedObject(ref)
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("%s %s/%s is marked for deletion but still referenced",
+			ref.Kind, ref.Namespace, ref.Name))
+		return nil
+	}
+
+	_, hasLabel := obj.GetLabels()[configv1beta1.ReferencedResourceLabel]
+	if hasLabel && controllerutil.ContainsFinalizer(obj, configv1beta1.ReferencedResourceFinalizer) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	controllerutil.AddFinalizer(obj, configv1beta1.ReferencedResourceFinalizer)
+	addReferencedResourceLabel(obj)
+	return r.Client.Patch(ctx, obj, patch)
+}
+
+// isResourceReferenced returns true if any ClusterSummary, other than the excluded one and
+// the ones marked for deletion, is referencing the resource
+func (r *ClusterSummaryReconciler) isResourceReferenced(ctx context.Context, ref *corev1.ObjectReference,
+	excluded *configv1beta1.ClusterSummary) (bool, error) {
+
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	err := r.Client.List(ctx, clusterSummaries,
+		client.MatchingFields{referencedResourceIndex: getReferencedResourceIndexKey(ref.Kind, ref.Namespace, ref.Name)})
+	if err != nil {
+		return false, err
+	}
+
+	for i := range clusterSummaries.Items {
+		cs := &clusterSummaries.Items[i]
+		if excluded != nil && cs.Namespace == excluded.Namespace && cs.Name == excluded.Name {
+			continue
+		}
+		if !cs.DeletionTimestamp.IsZero() {
+			continue
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/scope"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// validateReferences verifies every resource referenced by the ClusterSummary exists and,
+// for ConfigMaps/Secrets referenced in PolicyRefs, that their content can be parsed.
+// ClusterSummary Status.InvalidReferences and ReferencesValid condition are updated accordingly.
+func (r *ClusterSummaryReconciler) validateReferences(ctx context.Context,
+	clusterSummaryScope *scope.ClusterSummaryScope, logger logr.Logger) error {
+
+	references, err := r.getCurrentReferences(clusterSummaryScope)
+	if err != nil {
+		return err
+	}
+
+	policyRefs, err := r.getPolicyRefReferences(clusterSummaryScope)
+	if err != nil {
+		return err
+	}
+
+	var invalidReferences []configv1beta1.InvalidReference
+	for _, ref := range references.Items() {
+		tmpRef := ref
+		invalid, err := r.validateReference(ctx, &tmpRef, policyRefs.Has(&tmpRef), logger)
+		if err != nil {
+			return err
+		}
+		if invalid != nil {
+			invalidReferences = append(invalidReferences, *invalid)
+		}
+	}
+
+	// References come from a set, whose order is random. Sorting them keeps Status and condition
+	// message stable across reconciliations.
+	sort.Slice(invalidReferences, func(i, j int) bool {
+		if invalidReferences[i].Kind != invalidReferences[j].Kind {
+			return invalidReferences[i].Kind < invalidReferences[j].Kind
+		}
+		if invalidReferences[i].Namespace != invalidReferences[j].Namespace {
+			return invalidReferences[i].Namespace < invalidReferences[j].Namespace
+		}
+		return invalidReferences[i].Name < invalidReferences[j].Name
+	})
+
+	setReferencesValidCondition(clusterSummaryScope, invalidReferences)
+	return nil
+}
+
+// validateReference returns a non nil InvalidReference if referenced resource is missing or,
+// when parse is set, if its content cannot be parsed
+func (r *ClusterSummaryReconciler) validateReference(ctx context.Context, ref *corev1.ObjectReference,
+	parse bool, logger logr.Logger) (*configv1beta1.InvalidReference, error) {
+
+	newInvalidReference := func(reason configv1beta1.ReferenceFailureReason, msg string) *configv1beta1.InvalidReference {
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("referenced %s %s/%s is not valid: %s",
+			ref.Kind, ref.Namespace, ref.Name, msg))
+		return &configv1beta1.InvalidReference{
+			Kind:      ref.Kind,
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+			Reason:    reason,
+			Message:   msg,
+		}
+	}
+
+	namespacedName := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+
+	var object client.Object
+	var data map[string]string
+	switch ref.Kind {
+	case string(libsveltosv1beta1.ConfigMapReferencedResourceKind):
+		configMap, err := getConfigMap(ctx, r.Client, namespacedName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return newInvalidReference(configv1beta1.ReferenceNotFound, "resource does not exist"), nil
+			}
+			return nil, err
+		}
+		object = configMap
+		data = configMap.Data
+	case string(libsveltosv1beta1.SecretReferencedResourceKind):
+		secret, err := getSecret(ctx, r.Client, namespacedName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return newInvalidReference(configv1beta1.ReferenceNotFound, "resource does not exist"), nil
+			}
+			if errors.Is(err, libsveltosv1beta1.ErrSecretTypeNotSupported) {
+				return newInvalidReference(configv1beta1.ReferenceNotSupported, err.Error()), nil
+			}
+			return nil, err
+		}
+		object = secret
+		data = make(map[string]string, len(secret.Data))
+		for k := range secret.Data {
+			data[k] = string(secret.Data[k])
+		}
+	default:
+		source, err := getSource(ctx, r.Client, ref.Namespace, ref.Name, ref.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			return newInvalidReference(configv1beta1.ReferenceNotFound, "resource does not exist"), nil
+		}
+		return nil, nil
+	}
+
+	// Templates, Jsonnet and CUE can only be parsed once instantiated
+	if !parse || instantiateTemplate(object, logr.Discard()) || isJsonnet(object) || isCUE(object) {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, err := getUnstructured([]byte(data[k]), logr.Discard()); err != nil {
+			return newInvalidReference(configv1beta1.ReferenceUnparsable,
+				fmt.Sprintf("key %s: %v", k, err)), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// setReferencesValidCondition sets ClusterSummary ReferencesValid condition and Status.InvalidReferences
+func setReferencesValidCondition(clusterSummaryScope *scope.ClusterSummaryScope,
+	invalidReferences []configv1beta1.InvalidReference) {
+
+	clusterSummaryScope.ClusterSummary.Status.InvalidReferences = invalidReferences
+
+	if len(invalidReferences) == 0 {
+		clusterSummaryScope.SetCondition(configv1beta1.ReferencesValidCondition, metav1.ConditionTrue,
+			configv1beta1.ReferencesResolvedReason, "all referenced resources exist and are valid")
+		return
+	}
+
+	invalid := make([]string, len(invalidReferences))
+	for i := range invalidReferences {
+		invalid[i] = fmt.Sprintf("%s %s/%s (%s)", invalidReferences[i].Kind, invalidReferences[i].Namespace,
+			invalidReferences[i].Name, invalidReferences[i].Reason)
+	}
+
+	clusterSummaryScope.SetCondition(configv1beta1.ReferencesValidCondition, metav1.ConditionFalse,
+		configv1beta1.InvalidReferencesReason, fmt.Sprintf("invalid references: %s", strings.Join(invalid, ", ")))
+}
@@ -19,23 +19,35 @@ package controllers
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2/textlogger"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	"github.com/projectsveltos/libsveltos/lib/deployer"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
 )
 
+const (
+	// reloadedAtAnnotation is set on the pod template of workloads consuming a ConfigMap/Secret
+	// updated by Sveltos, to trigger a rolling upgrade
+	reloadedAtAnnotation = "projectsveltos.io/reloadedAt"
+)
+
 // isReloaderInstalled returns true if Reloader CRD is installed, false otherwise
 func isReloaderInstalled(ctx context.Context, c client.Client) (bool, error) {
 	clusterCRD := &apiextensionsv1.CustomResourceDefinition{}
@@ -260,3 +272,205 @@ func convertHelmResourcesToObjectReference(helmResources []libsveltosv1beta1.Hel
 
 	return resources
 }
+
+// isReloadableConfig returns true if resource is a ConfigMap or a Secret
+func isReloadableConfig(resource *unstructured.Unstructured) bool {
+	if resource.GroupVersionKind().Group != "" {
+		return false
+	}
+	return resource.GetKind() == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) ||
+		resource.GetKind() == string(libsveltosv1beta1.SecretReferencedResourceKind)
+}
+
+// podSpecConsumes returns true if podSpec references the ConfigMap/Secret with given kind and name
+// via volumes, env, envFrom or imagePullSecrets
+func podSpecConsumes(podSpec *corev1.PodSpec, kind, name string) bool {
+	isSecret := kind == string(libsveltosv1beta1.SecretReferencedResourceKind)
+
+	if isSecret {
+		for i := range podSpec.ImagePullSecrets {
+			if podSpec.ImagePullSecrets[i].Name == name {
+				return true
+			}
+		}
+	}
+
+	for i := range podSpec.Volumes {
+		if volumeConsumes(&podSpec.Volumes[i], isSecret, name) {
+			return true
+		}
+	}
+
+	containers := append([]corev1.Container{}, podSpec.InitContainers...)
+	containers = append(containers, podSpec.Containers...)
+	for i := range containers {
+		if containerConsumes(&containers[i], isSecret, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func volumeConsumes(volume *corev1.Volume, isSecret bool, name string) bool {
+	if isSecret && volume.Secret != nil && volume.Secret.SecretName == name {
+		return true
+	}
+	if !isSecret && volume.ConfigMap != nil && volume.ConfigMap.Name == name {
+		return true
+	}
+
+	if volume.Projected == nil {
+		return false
+	}
+	for i := range volume.Projected.Sources {
+		source := &volume.Projected.Sources[i]
+		if isSecret && source.Secret != nil && source.Secret.Name == name {
+			return true
+		}
+		if !isSecret && source.ConfigMap != nil && source.ConfigMap.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containerConsumes(container *corev1.Container, isSecret bool, name string) bool {
+	for i := range container.EnvFrom {
+		envFrom := &container.EnvFrom[i]
+		if isSecret && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name {
+			return true
+		}
+		if !isSecret && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name {
+			return true
+		}
+	}
+
+	for i := range container.Env {
+		valueFrom := container.Env[i].ValueFrom
+		if valueFrom == nil {
+			continue
+		}
+		if isSecret && valueFrom.SecretKeyRef != nil && valueFrom.SecretKeyRef.Name == name {
+			return true
+		}
+		if !isSecret && valueFrom.ConfigMapKeyRef != nil && valueFrom.ConfigMapKeyRef.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reloadConsumers triggers a rolling upgrade of all Deployment/StatefulSet/DaemonSet instances
+// in the namespace of the ConfigMap/Secret resource, consuming it.
+func reloadConsumers(ctx context.Context, c client.Client, resource *unstructured.Unstructured,
+	logger logr.Logger) error {
+
+	if !isReloadableConfig(resource) || resource.GetNamespace() == "" {
+		return nil
+	}
+
+	reloadedAt := time.Now().UTC().Format(time.RFC3339)
+	workloads, err := getConsumingWorkloads(ctx, c, resource.GetKind(), resource.GetNamespace(), resource.GetName())
+	if err != nil {
+		return err
+	}
+
+	for i := range workloads {
+		workload := workloads[i]
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("reloading workload %s/%s as %s %s changed",
+			workload.GetNamespace(), workload.GetName(), resource.GetKind(), resource.GetName()))
+
+		original := workload.DeepCopyObject().(client.Object)
+		template := getPodTemplate(workload)
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[reloadedAtAnnotation] = reloadedAt
+
+		err = c.Patch(ctx, workload, client.MergeFrom(original))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restorePolicyHash sets the policy hash annotation of the deployed resource back to hash.
+// It is used when consumers of an updated ConfigMap/Secret could not be reloaded.
+func restorePolicyHash(ctx context.Context, dr dynamic.ResourceInterface, resource *unstructured.Unstructured,
+	hash string) error {
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				deployer.PolicyHash: hash,
+			},
+		},
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	_, err = dr.Patch(ctx, resource.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+// getConsumingWorkloads returns all Deployment/StatefulSet/DaemonSet instances in namespace
+// consuming the ConfigMap/Secret with given kind and name
+func getConsumingWorkloads(ctx context.Context, c client.Client, kind, namespace, name string,
+) ([]client.Object, error) {
+
+	workloads := make([]client.Object, 0)
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		if podSpecConsumes(&deployments.Items[i].Spec.Template.Spec, kind, name) {
+			workloads = append(workloads, &deployments.Items[i])
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		if podSpecConsumes(&statefulSets.Items[i].Spec.Template.Spec, kind, name) {
+			workloads = append(workloads, &statefulSets.Items[i])
+		}
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, daemonSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		if podSpecConsumes(&daemonSets.Items[i].Spec.Template.Spec, kind, name) {
+			workloads = append(workloads, &daemonSets.Items[i])
+		}
+	}
+
+	return workloads, nil
+}
+
+// getPodTemplate returns the pod template of a Deployment/StatefulSet/DaemonSet
+func getPodTemplate(workload client.Object) *corev1.PodTemplateSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template
+	default:
+		return nil
+	}
+}
@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -35,6 +36,7 @@ import (
 	"github.com/projectsveltos/addon-controller/controllers"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	libsveltoscrd "github.com/projectsveltos/libsveltos/lib/crd"
+	"github.com/projectsveltos/libsveltos/lib/deployer"
 	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
 )
 
@@ -334,4 +336,125 @@ var _ = Describe("Reloader utils", func() {
 			}
 		}
 	})
+
+	It("podSpecConsumes returns true only when ConfigMap/Secret is referenced", func() {
+		configMapName := randomString()
+		secretName := randomString()
+		pullSecretName := randomString()
+
+		podSpec := &corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: pullSecretName}},
+			Volumes: []corev1.Volume{
+				{
+					Name: randomString(),
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+						},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name: randomString(),
+					Env: []corev1.EnvVar{
+						{
+							Name: randomString(),
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+									Key:                  randomString(),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(controllers.PodSpecConsumes(podSpec, "ConfigMap", configMapName)).To(BeTrue())
+		Expect(controllers.PodSpecConsumes(podSpec, "Secret", secretName)).To(BeTrue())
+		Expect(controllers.PodSpecConsumes(podSpec, "Secret", pullSecretName)).To(BeTrue())
+		Expect(controllers.PodSpecConsumes(podSpec, "ConfigMap", pullSecretName)).To(BeFalse())
+		Expect(controllers.PodSpecConsumes(podSpec, "Secret", configMapName)).To(BeFalse())
+		Expect(controllers.PodSpecConsumes(podSpec, "Secret", randomString())).To(BeFalse())
+	})
+
+	It("reloadConsumers annotates pod template of workloads consuming the Secret", func() {
+		namespace := randomString()
+		secretName := randomString()
+
+		consumer := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: randomString()},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						ImagePullSecrets: []corev1.LocalObjectReference{{Name: secretName}},
+					},
+				},
+			},
+		}
+		other := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: randomString()},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(consumer, other).Build()
+
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetNamespace(namespace)
+		secret.SetName(secretName)
+
+		Expect(controllers.ReloadConsumers(context.TODO(), c, secret,
+			textlogger.NewLogger(textlogger.NewConfig()))).To(Succeed())
+
+		currentConsumer := &appsv1.Deployment{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: consumer.Name},
+			currentConsumer)).To(Succeed())
+		Expect(currentConsumer.Spec.Template.Annotations).To(HaveKey("projectsveltos.io/reloadedAt"))
+
+		currentOther := &appsv1.DaemonSet{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: other.Name},
+			currentOther)).To(Succeed())
+		Expect(currentOther.Spec.Template.Annotations).ToNot(HaveKey("projectsveltos.io/reloadedAt"))
+	})
+
+	It("restorePolicyHash sets the policy hash annotation back on the deployed resource", func() {
+		namespace := randomString()
+		Expect(testEnv.Create(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        randomString(),
+				Annotations: map[string]string{deployer.PolicyHash: randomString()},
+			},
+			Data: map[string]string{randomString(): randomString()},
+		}
+		Expect(testEnv.Create(context.TODO(), configMap)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, configMap)).To(Succeed())
+
+		u, err := k8s_utils.GetUnstructured([]byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: %s
+  name: %s`, namespace, configMap.Name)))
+		Expect(err).To(BeNil())
+
+		dr, err := k8s_utils.GetDynamicResourceInterface(testEnv.Config, u.GroupVersionKind(), u.GetNamespace())
+		Expect(err).To(BeNil())
+
+		previousHash := randomString()
+		Expect(controllers.RestorePolicyHash(context.TODO(), dr, u, previousHash)).To(Succeed())
+
+		Eventually(func() bool {
+			currentConfigMap := &corev1.ConfigMap{}
+			err := testEnv.Get(context.TODO(),
+				types.NamespacedName{Namespace: namespace, Name: configMap.Name}, currentConfigMap)
+			return err == nil && currentConfigMap.Annotations[deployer.PolicyHash] == previousHash &&
+				reflect.DeepEqual(currentConfigMap.Data, configMap.Data)
+		}, timeout, pollingInterval).Should(BeTrue())
+	})
 })
@@ -33,10 +33,8 @@ import (
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/controllers/clustercache"
-	driftdetection "github.com/projectsveltos/addon-controller/pkg/drift-detection"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
-	"github.com/projectsveltos/libsveltos/lib/crd"
 	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
 	"github.com/projectsveltos/libsveltos/lib/logsettings"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
@@ -149,7 +147,7 @@ func deployDriftDetectionCRDs(ctx context.Context, remoteRestConfig *rest.Config
 func deployDebuggingConfigurationCRD(ctx context.Context, remoteRestConfig *rest.Config,
 	logger logr.Logger) error {
 
-	u, err := k8s_utils.GetUnstructured(crd.GetDebuggingConfigurationCRDYAML())
+	u, err := getDebuggingConfigurationCRD()
 	if err != nil {
 		logger.V(logs.LogInfo).Info(
 			fmt.Sprintf("failed to get DebuggingConfiguration CRD unstructured: %v", err))
@@ -178,7 +176,7 @@ func deployDebuggingConfigurationCRD(ctx context.Context, remoteRestConfig *rest
 func deployResourceSummaryCRD(ctx context.Context, remoteRestConfig *rest.Config,
 	logger logr.Logger) error {
 
-	rsCRD, err := k8s_utils.GetUnstructured(crd.GetResourceSummaryCRDYAML())
+	rsCRD, err := getResourceSummaryCRD()
 	if err != nil {
 		logger.V(logs.LogInfo).Info(
 			fmt.Sprintf("failed to get ResourceSummary CRD unstructured: %v", err))
@@ -203,21 +201,23 @@ func deployResourceSummaryCRD(ctx context.Context, remoteRestConfig *rest.Config
 	return nil
 }
 
-func prepareDriftDetectionManagerYAML(driftDetectionManagerYAML, clusterNamespace, clusterName, mode string,
-	clusterType libsveltosv1beta1.ClusterType) string {
+// getDriftDetectionManagerReplacer returns the replacer used to instantiate drift-detection-manager
+// resources for a given cluster.
+func getDriftDetectionManagerReplacer(clusterNamespace, clusterName, mode, name string,
+	clusterType libsveltosv1beta1.ClusterType) *strings.Replacer {
 
-	if mode != "do-not-send-updates" {
-		driftDetectionManagerYAML = strings.ReplaceAll(driftDetectionManagerYAML, "do-not-send-updates", "send-updates")
+	oldnew := []string{
+		"cluster-namespace=", fmt.Sprintf("cluster-namespace=%s", clusterNamespace),
+		"cluster-name=", fmt.Sprintf("cluster-name=%s", clusterName),
+		"cluster-type=", fmt.Sprintf("cluster-type=%s", clusterType),
+		"$NAME", name,
 	}
 
-	driftDetectionManagerYAML =
-		strings.ReplaceAll(driftDetectionManagerYAML, "cluster-namespace=", fmt.Sprintf("cluster-namespace=%s", clusterNamespace))
-	driftDetectionManagerYAML =
-		strings.ReplaceAll(driftDetectionManagerYAML, "cluster-name=", fmt.Sprintf("cluster-name=%s", clusterName))
-	driftDetectionManagerYAML =
-		strings.ReplaceAll(driftDetectionManagerYAML, "cluster-type=", fmt.Sprintf("cluster-type=%s", clusterType))
+	if mode != "do-not-send-updates" {
+		oldnew = append(oldnew, "do-not-send-updates", "send-updates")
+	}
 
-	return driftDetectionManagerYAML
+	return strings.NewReplacer(oldnew...)
 }
 
 // deployDriftDetectionManager deploys drift-detection-manager in the managed cluster
@@ -226,12 +226,31 @@ func deployDriftDetectionManager(ctx context.Context, remoteRestConfig *rest.Con
 	patches []libsveltosv1beta1.Patch, logger logr.Logger) error {
 
 	logger.V(logs.LogDebug).Info("deploy drift-detection-manager in managed cluster")
-	driftDetectionManagerYAML := string(driftdetection.GetDriftDetectionManagerYAML())
 
-	driftDetectionManagerYAML = prepareDriftDetectionManagerYAML(driftDetectionManagerYAML, clusterNamespace,
-		clusterName, mode, clusterType)
+	// Restrict drift-detection-manager to nodes it has an image for. Patches from the
+	// drift-detection ConfigMap are applied afterwards, so they can still override this.
+	// If node architectures cannot be listed, drift-detection-manager is deployed as is.
+	architectures, err := getNodeArchitectures(ctx, remoteRestConfig)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get node architectures: %v", err))
+	} else {
+		var archPatches []libsveltosv1beta1.Patch
+		archPatches, err = getArchitecturePatches(architectures)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(err.Error())
+			return err
+		}
+		patches = append(archPatches, patches...)
+	}
 
-	return deployDriftDetectionManagerResources(ctx, remoteRestConfig, driftDetectionManagerYAML, nil, patches, logger)
+	replacer := getDriftDetectionManagerReplacer(clusterNamespace, clusterName, mode, "", clusterType)
+	driftDetectionManagerObjects, err := getDriftDetectionManagerObjects(replacer)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse drift detection manager yaml: %v", err))
+		return err
+	}
+
+	return deployDriftDetectionManagerResources(ctx, remoteRestConfig, driftDetectionManagerObjects, nil, patches, logger)
 }
 
 // deployDriftDetectionManagerInManagementCluster deploys drift-detection-manager in the management cluster
@@ -243,10 +262,6 @@ func deployDriftDetectionManagerInManagementCluster(ctx context.Context, restCon
 	patches []libsveltosv1beta1.Patch, logger logr.Logger) error {
 
 	logger.V(logs.LogDebug).Info("deploy drift-detection-manager in management cluster")
-	driftDetectionManagerYAML := string(driftdetection.GetDriftDetectionManagerInMgmtClusterYAML())
-
-	driftDetectionManagerYAML = prepareDriftDetectionManagerYAML(driftDetectionManagerYAML, clusterNamespace,
-		clusterName, mode, clusterType)
 
 	// Following labels are added on the objects representing the drift-detection-manager
 	// for this cluster.
@@ -259,24 +274,22 @@ func deployDriftDetectionManagerInManagementCluster(ctx context.Context, restCon
 		return err
 	}
 
-	driftDetectionManagerYAML = strings.ReplaceAll(driftDetectionManagerYAML, "$NAME", name)
-	return deployDriftDetectionManagerResources(ctx, restConfig, driftDetectionManagerYAML, lbls, patches, logger)
+	replacer := getDriftDetectionManagerReplacer(clusterNamespace, clusterName, mode, name, clusterType)
+	driftDetectionManagerObjects, err := getDriftDetectionManagerInMgmtClusterObjects(replacer)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse drift detection manager yaml: %v", err))
+		return err
+	}
+
+	return deployDriftDetectionManagerResources(ctx, restConfig, driftDetectionManagerObjects, lbls, patches, logger)
 }
 
 func deployDriftDetectionManagerResources(ctx context.Context, restConfig *rest.Config,
-	driftDetectionManagerYAML string, lbls map[string]string, patches []libsveltosv1beta1.Patch,
-	logger logr.Logger) error {
+	driftDetectionManagerObjects []*unstructured.Unstructured, lbls map[string]string,
+	patches []libsveltosv1beta1.Patch, logger logr.Logger) error {
 
-	elements, err := customSplit(driftDetectionManagerYAML)
-	if err != nil {
-		return err
-	}
-	for i := range elements {
-		policy, err := k8s_utils.GetUnstructured([]byte(elements[i]))
-		if err != nil {
-			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse drift detection manager yaml: %v", err))
-			return err
-		}
+	for i := range driftDetectionManagerObjects {
+		policy := driftDetectionManagerObjects[i]
 
 		if lbls != nil {
 			// Add extra labels
@@ -290,21 +303,18 @@ func deployDriftDetectionManagerResources(ctx context.Context, restConfig *rest.
 			policy.SetLabels(currentLabels)
 		}
 
-		var referencedUnstructured []*unstructured.Unstructured
+		referencedUnstructured := []*unstructured.Unstructured{policy}
 		if len(patches) > 0 {
 			p := &patcher.CustomPatchPostRenderer{Patches: patches}
-			referencedUnstructured, err = p.RunUnstructured(
-				[]*unstructured.Unstructured{policy},
-			)
+			var err error
+			referencedUnstructured, err = p.RunUnstructured(referencedUnstructured)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to patch drift-detection-manager: %v", err))
 				return err
 			}
-		} else {
-			referencedUnstructured = append(referencedUnstructured, policy)
 		}
 
-		err = deployDriftDetectionManagerPatchedResources(ctx, restConfig, referencedUnstructured, logger)
+		err := deployDriftDetectionManagerPatchedResources(ctx, restConfig, referencedUnstructured, logger)
 		if err != nil {
 			return err
 		}
@@ -562,11 +572,6 @@ func removeDriftDetectionManagerFromManagementCluster(ctx context.Context,
 	clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
 	logger logr.Logger) error {
 
-	// Get YAML containing drift-detection-manager resources
-	driftDetectionManagerYAML := string(driftdetection.GetDriftDetectionManagerInMgmtClusterYAML())
-	driftDetectionManagerYAML = prepareDriftDetectionManagerYAML(driftDetectionManagerYAML, clusterNamespace,
-		clusterName, "", clusterType)
-
 	// Addon-controller deploys drift-detection-manager resources for each cluster matching at least
 	// one ClusterProfile with SyncMode set to ContinuousWithDriftDetection.
 	lbls := getDriftDetectionManagerLabels(clusterNamespace, clusterName, clusterType)
@@ -577,20 +582,17 @@ func removeDriftDetectionManagerFromManagementCluster(ctx context.Context,
 		return err
 	}
 
-	driftDetectionManagerYAML = strings.ReplaceAll(driftDetectionManagerYAML, "$NAME", name)
-
 	restConfig := getManagementClusterConfig()
 
-	elements, err := customSplit(driftDetectionManagerYAML)
+	// Get drift-detection-manager resources
+	replacer := getDriftDetectionManagerReplacer(clusterNamespace, clusterName, "", name, clusterType)
+	driftDetectionManagerObjects, err := getDriftDetectionManagerInMgmtClusterObjects(replacer)
 	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse drift detection manager yaml: %v", err))
 		return err
 	}
-	for i := range elements {
-		policy, err := k8s_utils.GetUnstructured([]byte(elements[i]))
-		if err != nil {
-			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse drift detection manager yaml: %v", err))
-			return err
-		}
+	for i := range driftDetectionManagerObjects {
+		policy := driftDetectionManagerObjects[i]
 
 		dr, err := k8s_utils.GetDynamicResourceInterface(restConfig, policy.GroupVersionKind(), policy.GetNamespace())
 		if err != nil {
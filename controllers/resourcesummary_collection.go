@@ -112,30 +112,37 @@ func collectResourceSummariesFromCluster(ctx context.Context, c client.Client,
 	}
 
 	logger.V(logs.LogVerbose).Info("collecting ResourceSummaries from cluster")
-	rsList := libsveltosv1beta1.ResourceSummaryList{}
-	err = remoteClient.List(ctx, &rsList)
-	if err != nil {
-		return err
-	}
-
 	l := logger.WithValues("cluster", fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name))
 
-	for i := range rsList.Items {
-		rs := &rsList.Items[i]
-		if !rs.DeletionTimestamp.IsZero() {
-			// ignore deleted ClassifierReport
-			continue
+	// ResourceSummaries are listed one page at a time to keep memory bounded
+	continueToken := ""
+	for {
+		rsList := libsveltosv1beta1.ResourceSummaryList{}
+		err = remoteClient.List(ctx, &rsList, client.Limit(listPageSize), client.Continue(continueToken))
+		if err != nil {
+			return err
 		}
-		if rs.Status.ResourcesChanged || rs.Status.HelmResourcesChanged || rs.Status.KustomizeResourcesChanged {
-			// process resourceSummary
-			err = processResourceSummary(ctx, c, remoteClient, rs, l)
-			if err != nil {
-				return err
+
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if !rs.DeletionTimestamp.IsZero() {
+				// ignore deleted ClassifierReport
+				continue
+			}
+			if rs.Status.ResourcesChanged || rs.Status.HelmResourcesChanged || rs.Status.KustomizeResourcesChanged {
+				// process resourceSummary
+				err = processResourceSummary(ctx, c, remoteClient, rs, l)
+				if err != nil {
+					return err
+				}
 			}
 		}
-	}
 
-	return nil
+		continueToken = rsList.Continue
+		if continueToken == "" {
+			return nil
+		}
+	}
 }
 
 // isResourceSummaryInstalled returns true if ResourceSummary CRD is installed, false otherwise
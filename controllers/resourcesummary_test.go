@@ -29,6 +29,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -186,6 +187,82 @@ var _ = Describe("ResourceSummary Deployer", func() {
 		}, timeout, pollingInterval).Should(BeTrue())
 	})
 
+	It("getDriftDetectionManagerInMgmtClusterObjects returns instantiated copies of drift-detection-manager resources", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		name := randomString()
+
+		replacer := controllers.GetDriftDetectionManagerReplacer(clusterNamespace, clusterName, "", name,
+			libsveltosv1beta1.ClusterTypeCapi)
+		objects, err := controllers.GetDriftDetectionManagerInMgmtClusterObjects(replacer)
+		Expect(err).To(BeNil())
+		Expect(len(objects)).ToNot(BeZero())
+
+		found := false
+		for i := range objects {
+			if objects[i].GetKind() != "Deployment" {
+				continue
+			}
+			found = true
+			Expect(objects[i].GetName()).To(Equal(name))
+
+			depl := &appsv1.Deployment{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objects[i].Object, depl)).To(Succeed())
+			Expect(depl.Spec.Template.Spec.Containers[0].Args).To(ContainElement(
+				fmt.Sprintf("--cluster-namespace=%s", clusterNamespace)))
+			Expect(depl.Spec.Template.Spec.Containers[0].Args).To(ContainElement(
+				fmt.Sprintf("--cluster-name=%s", clusterName)))
+			Expect(depl.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--run-mode=send-updates"))
+
+			// Modifying returned objects must not affect cached ones
+			objects[i].SetName(randomString())
+		}
+		Expect(found).To(BeTrue())
+
+		objects, err = controllers.GetDriftDetectionManagerInMgmtClusterObjects(replacer)
+		Expect(err).To(BeNil())
+		for i := range objects {
+			if objects[i].GetKind() == "Deployment" {
+				Expect(objects[i].GetName()).To(Equal(name))
+			}
+		}
+	})
+
+	It("replaceImageRegistry replaces image registry", func() {
+		registry := "registry.example.com:5000"
+		Expect(controllers.ReplaceImageRegistry("docker.io/projectsveltos/drift-detection-manager:main", registry)).
+			To(Equal(registry + "/projectsveltos/drift-detection-manager:main"))
+		Expect(controllers.ReplaceImageRegistry("projectsveltos/drift-detection-manager:main", registry)).
+			To(Equal(registry + "/projectsveltos/drift-detection-manager:main"))
+		Expect(controllers.ReplaceImageRegistry("localhost/drift-detection-manager:main", registry)).
+			To(Equal(registry + "/drift-detection-manager:main"))
+	})
+
+	It("getDriftDetectionManagerInMgmtClusterObjects applies image registry override", func() {
+		registry := "registry.example.com"
+		controllers.SetImageRegistryOverride(registry + "/")
+		defer controllers.SetImageRegistryOverride("")
+
+		replacer := controllers.GetDriftDetectionManagerReplacer(randomString(), randomString(), "", randomString(),
+			libsveltosv1beta1.ClusterTypeCapi)
+		objects, err := controllers.GetDriftDetectionManagerInMgmtClusterObjects(replacer)
+		Expect(err).To(BeNil())
+
+		found := false
+		for i := range objects {
+			if objects[i].GetKind() != "Deployment" {
+				continue
+			}
+			found = true
+			depl := &appsv1.Deployment{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objects[i].Object, depl)).To(Succeed())
+			for j := range depl.Spec.Template.Spec.Containers {
+				Expect(depl.Spec.Template.Spec.Containers[j].Image).To(HavePrefix(registry + "/projectsveltos/"))
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
 	It("transformDriftExclusionsToPatches transforms DriftExclusions to Patches", func() {
 		driftExclusions := []configv1beta1.DriftExclusion{
 			{
@@ -283,8 +283,19 @@ func instantiateTemplateValues(ctx context.Context, config *rest.Config, c clien
 		return u
 	}
 
+	var tmpl *template.Template
+	var libraryConflicts map[string]error
+	funcMap[includeTemplateFunc] = func(name string, data any) (string, error) {
+		return executeLibraryTemplate(tmpl, libraryConflicts, name, data)
+	}
+
 	templateName := getTemplateName(clusterNamespace, clusterName, requestorName)
-	tmpl, err := template.New(templateName).Option("missingkey=error").Funcs(funcMap).Parse(values)
+	tmpl, err = template.New(templateName).Option("missingkey=error").Funcs(funcMap).Parse(values)
+	if err != nil {
+		return "", err
+	}
+
+	libraryConflicts, err = addLibraryTemplates(ctx, c, tmpl, values)
 	if err != nil {
 		return "", err
 	}
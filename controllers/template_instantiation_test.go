@@ -33,6 +33,7 @@ import (
 	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/controllers"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
@@ -95,6 +96,33 @@ var _ = Describe("Template instantiation", func() {
 		Expect(result).To(ContainSubstring(fmt.Sprintf("%s-test", cluster.Name)))
 	})
 
+	It("instantiateTemplateValues includes TemplateLibrary templates", func() {
+		library := &configv1beta1.TemplateLibrary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.TemplateLibrarySpec{
+				Templates: []configv1beta1.LibraryTemplate{
+					{Name: "cluster-label", Version: "v1", Template: `cluster: {{ .Cluster.metadata.name }}`},
+					{Name: "cluster-label", Version: "v2", Template: `cluster-name: {{ .Cluster.metadata.name }}`},
+				},
+			},
+		}
+		Expect(testEnv.Client.Create(context.TODO(), library)).To(Succeed())
+		Expect(waitForObject(ctx, testEnv.Client, library)).To(Succeed())
+
+		values := `labels:
+  {{ includeTemplate "cluster-label@v2" . }}`
+
+		result, err := controllers.InstantiateTemplateValues(context.TODO(), testEnv.Config, testEnv.GetClient(),
+			libsveltosv1beta1.ClusterTypeCapi, cluster.Namespace, cluster.Name, randomString(), values,
+			nil, textlogger.NewLogger(textlogger.NewConfig()))
+		Expect(err).To(BeNil())
+		Expect(result).To(ContainSubstring(fmt.Sprintf("cluster-name: %s", cluster.Name)))
+
+		Expect(testEnv.Client.Delete(context.TODO(), library)).To(Succeed())
+	})
+
 	It("instantiateTemplateValues with getField", func() {
 		values := `{{ $replicasValue := getField "Deployment" "spec.replicas" }}
 {{ toYaml $replicasValue }}`
@@ -0,0 +1,128 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+)
+
+// Templates defined in TemplateLibrary instances can be included by any template
+// with includeTemplate "<name>@<version>" <data>
+// Library templates are versioned and a published version cannot be modified (this is enforced
+// by the TemplateLibrary CRD). Updating a fragment means publishing a new version and changing the
+// templates including it, which in turn causes the ClusterProfiles/Profiles referencing those to
+// be redeployed.
+
+const (
+	includeTemplateFunc = "includeTemplate"
+)
+
+// getLibraryTemplateName returns the name a library template is referenced with
+func getLibraryTemplateName(libraryTemplate *configv1beta1.LibraryTemplate) string {
+	return fmt.Sprintf("%s@%s", libraryTemplate.Name, libraryTemplate.Version)
+}
+
+// getLibraryTemplates returns all templates defined in any TemplateLibrary, keyed by
+// name@version. A name@version defined, with different content, in more than one TemplateLibrary
+// is ambiguous: it is not returned in templates but in conflicts, along with the reason.
+// Other templates are not affected.
+func getLibraryTemplates(ctx context.Context, c client.Client,
+) (templates map[string]string, conflicts map[string]error, err error) {
+
+	libraries := &configv1beta1.TemplateLibraryList{}
+	if err := c.List(ctx, libraries); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(libraries.Items, func(i, j int) bool {
+		return libraries.Items[i].Name < libraries.Items[j].Name
+	})
+
+	templates = make(map[string]string)
+	conflicts = make(map[string]error)
+	definedIn := make(map[string]string)
+	for i := range libraries.Items {
+		library := &libraries.Items[i]
+		for j := range library.Spec.Templates {
+			name := getLibraryTemplateName(&library.Spec.Templates[j])
+			if previous, ok := definedIn[name]; ok {
+				if _, ok := conflicts[name]; !ok && templates[name] != library.Spec.Templates[j].Template {
+					conflicts[name] = fmt.Errorf("template %s is defined differently in TemplateLibrary %s and %s",
+						name, previous, library.Name)
+					delete(templates, name)
+				}
+				continue
+			}
+			definedIn[name] = library.Name
+			templates[name] = library.Spec.Templates[j].Template
+		}
+	}
+
+	return templates, conflicts, nil
+}
+
+// addLibraryTemplates adds, as associated templates of tmpl, all library templates and returns
+// the ambiguous ones (see getLibraryTemplates).
+// Library is loaded only if values includes any library template.
+func addLibraryTemplates(ctx context.Context, c client.Client, tmpl *template.Template, values string,
+) (map[string]error, error) {
+
+	if !strings.Contains(values, includeTemplateFunc) {
+		return nil, nil
+	}
+
+	templates, conflicts, err := getLibraryTemplates(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range templates {
+		if _, err := tmpl.New(name).Parse(templates[name]); err != nil {
+			return nil, fmt.Errorf("failed to parse library template %s: %w", name, err)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// executeLibraryTemplate renders the library template, associated to tmpl, referenced by name@version.
+// Including an ambiguous template fails.
+func executeLibraryTemplate(tmpl *template.Template, conflicts map[string]error, name string, data any,
+) (string, error) {
+
+	if err, ok := conflicts[name]; ok {
+		return "", err
+	}
+	if tmpl.Lookup(name) == nil {
+		return "", fmt.Errorf("library template %s not found", name)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buffer, name, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
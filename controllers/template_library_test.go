@@ -0,0 +1,110 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/controllers"
+)
+
+var _ = Describe("Template library", func() {
+	getTemplateLibrary := func(templates ...configv1beta1.LibraryTemplate) *configv1beta1.TemplateLibrary {
+		return &configv1beta1.TemplateLibrary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.TemplateLibrarySpec{
+				Templates: templates,
+			},
+		}
+	}
+
+	It("getLibraryTemplates returns templates keyed by name and version", func() {
+		library1 := getTemplateLibrary(
+			configv1beta1.LibraryTemplate{Name: "labels", Version: "v1", Template: "v1"},
+			configv1beta1.LibraryTemplate{Name: "labels", Version: "v2", Template: "v2"},
+		)
+		library2 := getTemplateLibrary(
+			configv1beta1.LibraryTemplate{Name: "sidecar", Version: "v1", Template: "sidecar"},
+		)
+
+		initObjects := []client.Object{library1, library2}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		templates, conflicts, err := controllers.GetLibraryTemplates(context.TODO(), c)
+		Expect(err).To(BeNil())
+		Expect(conflicts).To(BeEmpty())
+		Expect(templates).To(HaveLen(3))
+		Expect(templates).To(HaveKeyWithValue("labels@v1", "v1"))
+		Expect(templates).To(HaveKeyWithValue("labels@v2", "v2"))
+		Expect(templates).To(HaveKeyWithValue("sidecar@v1", "sidecar"))
+	})
+
+	It("getLibraryTemplates reports template versions defined differently only for those", func() {
+		template := randomString()
+		library1 := getTemplateLibrary(
+			configv1beta1.LibraryTemplate{Name: "labels", Version: "v1", Template: randomString()},
+			configv1beta1.LibraryTemplate{Name: "sidecar", Version: "v1", Template: template},
+		)
+		library2 := getTemplateLibrary(
+			configv1beta1.LibraryTemplate{Name: "labels", Version: "v1", Template: randomString()},
+			configv1beta1.LibraryTemplate{Name: "sidecar", Version: "v1", Template: template},
+			configv1beta1.LibraryTemplate{Name: "labels", Version: "v2", Template: "v2"},
+		)
+
+		initObjects := []client.Object{library1, library2}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		templates, conflicts, err := controllers.GetLibraryTemplates(context.TODO(), c)
+		Expect(err).To(BeNil())
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts).To(HaveKey("labels@v1"))
+		// Same content defined twice is not a conflict
+		Expect(templates).To(HaveLen(2))
+		Expect(templates).To(HaveKeyWithValue("sidecar@v1", template))
+		Expect(templates).To(HaveKeyWithValue("labels@v2", "v2"))
+	})
+
+	It("executeLibraryTemplate renders associated template", func() {
+		tmpl, err := template.New(randomString()).Parse(`{{ template "labels@v1" . }}`)
+		Expect(err).To(BeNil())
+		_, err = tmpl.New("labels@v1").Parse(`name: {{ .name }}`)
+		Expect(err).To(BeNil())
+
+		result, err := controllers.ExecuteLibraryTemplate(tmpl, nil, "labels@v1", map[string]string{"name": "test"})
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal("name: test"))
+
+		_, err = controllers.ExecuteLibraryTemplate(tmpl, nil, "labels@v2", nil)
+		Expect(err).ToNot(BeNil())
+
+		conflicts := map[string]error{"labels@v1": fmt.Errorf("defined differently")}
+		_, err = controllers.ExecuteLibraryTemplate(tmpl, conflicts, "labels@v1", map[string]string{"name": "test"})
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -27,12 +27,38 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
 	"github.com/projectsveltos/libsveltos/lib/funcmap"
 	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
 )
 
+// getTemplateResourceCluster returns the cluster information available to instantiate
+// TemplateResourceRef namespace and name: cluster namespace, name and type, along with
+// cluster labels and annotations.
+func getTemplateResourceCluster(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
+) (map[string]interface{}, error) {
+
+	u := &unstructured.Unstructured{}
+	u.SetNamespace(clusterSummary.Spec.ClusterNamespace)
+	u.SetName(clusterSummary.Spec.ClusterName)
+	u.SetKind(string(clusterSummary.Spec.ClusterType))
+
+	cluster, err := clusterproxy.GetCluster(ctx, getManagementClusterClient(), clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Spec.ClusterType)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return u.UnstructuredContent(), nil
+		}
+		return nil, err
+	}
+
+	u.SetLabels(cluster.GetLabels())
+	u.SetAnnotations(cluster.GetAnnotations())
+	return u.UnstructuredContent(), nil
+}
+
 // The TemplateResource namespace can be specified or it will inherit the cluster namespace
-func getTemplateResourceNamespace(clusterSummary *configv1beta1.ClusterSummary,
+func getTemplateResourceNamespace(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
 	ref *configv1beta1.TemplateResourceRef) (string, error) {
 
 	namespace := ref.Resource.Namespace
@@ -51,12 +77,10 @@ func getTemplateResourceNamespace(clusterSummary *configv1beta1.ClusterSummary,
 
 	var buffer bytes.Buffer
 
-	// Cluster namespace and name can be used to instantiate the name of the resource that
-	// needs to be fetched from the management cluster. Defined an unstructured with namespace and name set
-	u := &unstructured.Unstructured{}
-	u.SetNamespace(clusterSummary.Spec.ClusterNamespace)
-	u.SetName(clusterSummary.Spec.ClusterName)
-	u.SetKind(string(clusterSummary.Spec.ClusterType))
+	cluster, err := getTemplateResourceCluster(ctx, clusterSummary)
+	if err != nil {
+		return "", err
+	}
 
 	if err := tmpl.Execute(&buffer,
 		struct {
@@ -64,7 +88,7 @@ func getTemplateResourceNamespace(clusterSummary *configv1beta1.ClusterSummary,
 			// deprecated. This used to be original format which was different than rest of templating
 			ClusterNamespace, ClusterName string
 		}{
-			Cluster:          u.UnstructuredContent(),
+			Cluster:          cluster,
 			ClusterNamespace: clusterSummary.Spec.ClusterNamespace,
 			ClusterName:      clusterSummary.Spec.ClusterName}); err != nil {
 		return "", fmt.Errorf("error executing template: %w", err)
@@ -77,7 +101,7 @@ func getTemplateResourceNamespace(clusterSummary *configv1beta1.ClusterSummary,
 // clusterNamespace => .Cluster.metadata.namespace
 // clusterName => .Cluster.metadata.name
 // clusterType => .Cluster.kind
-func getTemplateResourceName(clusterSummary *configv1beta1.ClusterSummary,
+func getTemplateResourceName(ctx context.Context, clusterSummary *configv1beta1.ClusterSummary,
 	ref *configv1beta1.TemplateResourceRef) (string, error) {
 
 	// Accept name that are templates
@@ -90,12 +114,10 @@ func getTemplateResourceName(clusterSummary *configv1beta1.ClusterSummary,
 
 	var buffer bytes.Buffer
 
-	// Cluster namespace and name can be used to instantiate the name of the resource that
-	// needs to be fetched from the management cluster. Defined an unstructured with namespace and name set
-	u := &unstructured.Unstructured{}
-	u.SetNamespace(clusterSummary.Spec.ClusterNamespace)
-	u.SetName(clusterSummary.Spec.ClusterName)
-	u.SetKind(string(clusterSummary.Spec.ClusterType))
+	cluster, err := getTemplateResourceCluster(ctx, clusterSummary)
+	if err != nil {
+		return "", err
+	}
 
 	if err := tmpl.Execute(&buffer,
 		struct {
@@ -103,7 +125,7 @@ func getTemplateResourceName(clusterSummary *configv1beta1.ClusterSummary,
 			// deprecated. This used to be original format which was different than rest of templating
 			ClusterNamespace, ClusterName string
 		}{
-			Cluster:          u.UnstructuredContent(),
+			Cluster:          cluster,
 			ClusterNamespace: clusterSummary.Spec.ClusterNamespace,
 			ClusterName:      clusterSummary.Spec.ClusterName}); err != nil {
 		return "", fmt.Errorf("error executing template: %w", err)
@@ -126,11 +148,11 @@ func collectTemplateResourceRefs(ctx context.Context, clusterSummary *configv1be
 	for i := range clusterSummary.Spec.ClusterProfileSpec.TemplateResourceRefs {
 		ref := clusterSummary.Spec.ClusterProfileSpec.TemplateResourceRefs[i]
 		var err error
-		ref.Resource.Namespace, err = getTemplateResourceNamespace(clusterSummary, &ref)
+		ref.Resource.Namespace, err = getTemplateResourceNamespace(ctx, clusterSummary, &ref)
 		if err != nil {
 			return nil, err
 		}
-		ref.Resource.Name, err = getTemplateResourceName(clusterSummary, &ref)
+		ref.Resource.Name, err = getTemplateResourceName(ctx, clusterSummary, &ref)
 		if err != nil {
 			return nil, err
 		}
@@ -17,6 +17,8 @@ limitations under the License.
 package controllers_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -70,7 +72,7 @@ var _ = Describe("TemplateResourceDef utils ", func() {
 			},
 		}
 
-		value, err := controllers.GetTemplateResourceName(clusterSummary, ref)
+		value, err := controllers.GetTemplateResourceName(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(cluster.Namespace + "-" + cluster.Name))
 	})
@@ -94,7 +96,7 @@ var _ = Describe("TemplateResourceDef utils ", func() {
 			},
 		}
 
-		value, err := controllers.GetTemplateResourceName(clusterSummary, ref)
+		value, err := controllers.GetTemplateResourceName(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(cluster.Namespace + "-" + cluster.Name))
 	})
@@ -118,12 +120,12 @@ var _ = Describe("TemplateResourceDef utils ", func() {
 			},
 		}
 
-		value, err := controllers.GetTemplateResourceNamespace(clusterSummary, ref)
+		value, err := controllers.GetTemplateResourceNamespace(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(cluster.Namespace))
 
 		ref.Resource.Namespace = randomString()
-		value, err = controllers.GetTemplateResourceNamespace(clusterSummary, ref)
+		value, err = controllers.GetTemplateResourceNamespace(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(ref.Resource.Namespace))
 	})
@@ -148,14 +150,50 @@ var _ = Describe("TemplateResourceDef utils ", func() {
 			},
 		}
 
-		value, err := controllers.GetTemplateResourceNamespace(clusterSummary, ref)
+		value, err := controllers.GetTemplateResourceNamespace(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(cluster.Namespace + "-foo"))
 
 		ref.Resource.Namespace = randomString()
-		value, err = controllers.GetTemplateResourceNamespace(clusterSummary, ref)
+		value, err = controllers.GetTemplateResourceNamespace(context.TODO(), clusterSummary, ref)
 		Expect(err).To(BeNil())
 		Expect(value).To(Equal(ref.Resource.Namespace))
 	})
 
+	It("GetTemplateResourceName can use cluster labels", func() {
+		env := randomString()
+		cluster.Labels = map[string]string{"env": env}
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), ns)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, ns)).To(Succeed())
+		Expect(testEnv.Create(context.TODO(), cluster)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, cluster)).To(Succeed())
+
+		ref := &configv1beta1.TemplateResourceRef{
+			Resource: corev1.ObjectReference{
+				Name: `kyverno-{{ index .Cluster.metadata.labels "env" }}`,
+			},
+			Identifier: randomString(),
+		}
+
+		clusterSummary := &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: cluster.Namespace,
+				ClusterName:      cluster.Name,
+				ClusterType:      libsveltosv1beta1.ClusterTypeCapi,
+			},
+		}
+
+		value, err := controllers.GetTemplateResourceName(context.TODO(), clusterSummary, ref)
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal("kyverno-" + env))
+	})
 })
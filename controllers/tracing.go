@@ -0,0 +1,86 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+const (
+	tracerName = "github.com/projectsveltos/addon-controller"
+
+	clusterNamespaceAttribute = attribute.Key("sveltos.cluster.namespace")
+	clusterNameAttribute      = attribute.Key("sveltos.cluster.name")
+	clusterTypeAttribute      = attribute.Key("sveltos.cluster.type")
+	clusterSummaryAttribute   = attribute.Key("sveltos.clustersummary")
+	featureAttribute          = attribute.Key("sveltos.feature")
+	hashAttribute             = attribute.Key("sveltos.hash")
+)
+
+// When tracing is not enabled, global TracerProvider is a no-op one and so are all spans.
+func getTracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// startFeatureSpan starts a span for an operation on a feature in a given cluster.
+func startFeatureSpan(ctx context.Context, spanName, clusterNamespace, clusterName, applicant, featureID string,
+	clusterType libsveltosv1beta1.ClusterType) (context.Context, trace.Span) {
+
+	return getTracer().Start(ctx, spanName,
+		trace.WithAttributes(
+			clusterNamespaceAttribute.String(clusterNamespace),
+			clusterNameAttribute.String(clusterName),
+			clusterTypeAttribute.String(string(clusterType)),
+			clusterSummaryAttribute.String(applicant),
+			featureAttribute.String(featureID),
+		))
+}
+
+// setHashAttribute adds hash to span attributes
+func setHashAttribute(span trace.Span, hash []byte) {
+	span.SetAttributes(hashAttribute.String(fmt.Sprintf("%x", hash)))
+}
+
+// endSpan records err, if any, and ends span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// injectTraceContext stores trace context of ctx in the deployer handler options. Deployer
+// processes requests in its own workers, this allows spans created there to be part of
+// the same trace as the reconciliation which queued the request.
+func injectTraceContext(ctx context.Context, handlerOptions map[string]string) {
+	propagation.TraceContext{}.Inject(ctx, propagation.MapCarrier(handlerOptions))
+}
+
+// extractTraceContext returns a context containing trace context stored in the deployer handler options.
+func extractTraceContext(ctx context.Context, handlerOptions map[string]string) context.Context {
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier(handlerOptions))
+}
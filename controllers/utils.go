@@ -18,7 +18,10 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
@@ -30,12 +33,15 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/discovery"
 	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
@@ -56,6 +62,13 @@ import (
 const (
 	nameSeparator = "--"
 	clusterKind   = "Cluster"
+
+	// listPageSize is the maximum number of items requested per List call when listing
+	// resources directly from an API server (not from the manager cache).
+	listPageSize = 500
+
+	// profileClusterIndex indexes ClusterSummaries by the ClusterProfile/Profile owning them and their cluster
+	profileClusterIndex = "profileCluster"
 )
 
 var (
@@ -64,8 +77,16 @@ var (
 
 type NonRetriableError struct {
 	Message string
+	// Reason, when set, is reported as FeatureSummary FailureReason
+	Reason string
 }
 
+const (
+	// referenceMissingFailureReason is the FeatureSummary FailureReason set when
+	// a feature failed because a referenced resource does not exist
+	referenceMissingFailureReason = "ReferenceMissing"
+)
+
 func (r *NonRetriableError) Error() string {
 	return r.Message
 }
@@ -107,6 +128,11 @@ func getPrefix(clusterType libsveltosv1beta1.ClusterType) string {
 
 // GetClusterSummaryName returns the ClusterSummary name given a ClusterProfile/Profile kind/name and
 // cluster type/Name.
+// ClusterSummary name is used as label value (libsveltosv1beta1.ClusterSummaryNameLabel), so names
+// longer than validation.LabelValueMaxLength are truncated and suffixed with a short hash of the
+// full name. Two different ClusterProfile/Profile and cluster pairs never end up with same name.
+// ClusterSummary lookups by ClusterProfile/Profile and cluster must not rely on the name
+// (see getClusterSummary and getClusterSummaryForProfileAndCluster).
 func GetClusterSummaryName(profileKind, profileName, clusterName string, isSveltosCluster bool) string {
 	clusterType := libsveltosv1beta1.ClusterTypeCapi
 	if isSveltosCluster {
@@ -115,10 +141,57 @@ func GetClusterSummaryName(profileKind, profileName, clusterName string, isSvelt
 	prefix := getPrefix(clusterType)
 	if profileKind == configv1beta1.ClusterProfileKind {
 		// For backward compatibility (code before addition of Profiles) do not change this
-		return fmt.Sprintf("%s-%s-%s", profileName, prefix, clusterName)
+		return shortenName(fmt.Sprintf("%s-%s-%s", profileName, prefix, clusterName))
+	}
+
+	return shortenName(fmt.Sprintf("p--%s-%s-%s", profileName, prefix, clusterName))
+}
+
+// shortenName returns name unchanged if not longer than validation.LabelValueMaxLength.
+// Otherwise name is truncated and a deterministic short hash of the full name is appended.
+// Trailing non alphanumeric characters of the truncated name are removed, so the result is
+// still a valid name and label value.
+func shortenName(name string) string {
+	if len(name) <= validation.LabelValueMaxLength {
+		return name
+	}
+
+	const hashLength = 10
+	h := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(h[:])[:hashLength]
+	truncated := strings.TrimRightFunc(name[:validation.LabelValueMaxLength-hashLength-1], func(r rune) bool {
+		return !isAlphanumeric(r)
+	})
+	return fmt.Sprintf("%s-%s", truncated, hash)
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// getProfileClusterIndexKey returns the profileClusterIndex key for a ClusterProfile/Profile and cluster pair
+func getProfileClusterIndexKey(profileKind, profileName, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType) string {
+
+	return fmt.Sprintf("%s:%s:%s:%s/%s", profileKind, profileName, clusterType, clusterNamespace, clusterName)
+}
+
+// indexClusterSummaryByProfileAndCluster returns the profileClusterIndex key for a ClusterSummary.
+// Key is built from the owner ClusterProfile/Profile and the cluster, so it does not depend on
+// the ClusterSummary name nor on label values.
+func indexClusterSummaryByProfileAndCluster(o client.Object) []string {
+	clusterSummary, ok := o.(*configv1beta1.ClusterSummary)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterSummary but got a %T", o))
 	}
 
-	return fmt.Sprintf("p--%s-%s-%s", profileName, prefix, clusterName)
+	profileRef, err := configv1beta1.GetProfileOwnerReference(clusterSummary)
+	if err != nil {
+		return nil
+	}
+
+	return []string{getProfileClusterIndexKey(profileRef.Kind, profileRef.Name, clusterSummary.Spec.ClusterNamespace,
+		clusterSummary.Spec.ClusterName, clusterSummary.Spec.ClusterType)}
 }
 
 // getClusterSummary returns the ClusterSummary instance created by a specific
@@ -141,6 +214,30 @@ func getClusterSummary(ctx context.Context, c client.Client,
 		},
 	}
 
+	return getSingleClusterSummary(ctx, c, profileKind, profileName, clusterNamespace, clusterName, listOptions)
+}
+
+// getClusterSummaryForProfileAndCluster returns the ClusterSummary instance created by a specific
+// ClusterProfile/Profile for a specific Cluster using the profileClusterIndex.
+// Client must be backed by a cache with such index registered.
+func getClusterSummaryForProfileAndCluster(ctx context.Context, c client.Client,
+	profileKind, profileName string, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType) (*configv1beta1.ClusterSummary, error) {
+
+	listOptions := []client.ListOption{
+		client.InNamespace(clusterNamespace),
+		client.MatchingFields{
+			profileClusterIndex: getProfileClusterIndexKey(profileKind, profileName, clusterNamespace, clusterName,
+				clusterType),
+		},
+	}
+
+	return getSingleClusterSummary(ctx, c, profileKind, profileName, clusterNamespace, clusterName, listOptions)
+}
+
+func getSingleClusterSummary(ctx context.Context, c client.Client, profileKind, profileName string,
+	clusterNamespace, clusterName string, listOptions []client.ListOption) (*configv1beta1.ClusterSummary, error) {
+
 	clusterSummaryList := &configv1beta1.ClusterSummaryList{}
 	if err := c.List(ctx, clusterSummaryList, listOptions...); err != nil {
 		return nil, err
@@ -176,11 +273,14 @@ func getClusterConfiguration(ctx context.Context, c client.Client,
 	return clusterConfiguration, nil
 }
 
-func getEntryKey(resourceKind, resourceNamespace, resourceName string) string {
+// getEntryKey returns a key identifying a resource. apiVersion is part of the key so
+// same-kind resources from different groups, or resources of different kinds sharing
+// a name, never collide. ':' cannot be part of kind, namespace or name.
+func getEntryKey(resourceAPIVersion, resourceKind, resourceNamespace, resourceName string) string {
 	if resourceNamespace != "" {
-		return fmt.Sprintf("%s-%s-%s", resourceKind, resourceNamespace, resourceName)
+		return fmt.Sprintf("%s:%s:%s/%s", resourceAPIVersion, resourceKind, resourceNamespace, resourceName)
 	}
-	return fmt.Sprintf("%s-%s", resourceKind, resourceName)
+	return fmt.Sprintf("%s:%s:%s", resourceAPIVersion, resourceKind, resourceName)
 }
 
 func getClusterReportName(profileKind, profileName, clusterName string, clusterType libsveltosv1beta1.ClusterType) string {
@@ -318,6 +418,31 @@ func isNamespaced(r *unstructured.Unstructured, config *rest.Config) (bool, erro
 	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
 }
 
+// forEachUnstructured lists, one page at a time, all resources matching options and invokes
+// fn for each of them. This keeps memory bounded when listing large number of resources.
+func forEachUnstructured(ctx context.Context, dr dynamic.ResourceInterface, options metav1.ListOptions,
+	fn func(u *unstructured.Unstructured) error) error {
+
+	options.Limit = listPageSize
+	for {
+		list, err := dr.List(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			if err := fn(&list.Items[i]); err != nil {
+				return err
+			}
+		}
+
+		options.Continue = list.GetContinue()
+		if options.Continue == "" {
+			return nil
+		}
+	}
+}
+
 // removeDuplicates removes duplicates entries in the references slice
 func removeDuplicates(references []corev1.ObjectReference) []corev1.ObjectReference {
 	set := libsveltosset.Set{}
@@ -328,33 +453,57 @@ func removeDuplicates(references []corev1.ObjectReference) []corev1.ObjectRefere
 	return set.Items()
 }
 
-func getConfigMapHash(configmap *corev1.ConfigMap) string {
-	var config string
-	if configmap.Annotations != nil {
-		config += getDataSectionHash(configmap.Annotations)
+// getReferenceAPIVersion returns the apiVersion of a referenced resource given its kind.
+func getReferenceAPIVersion(kind string) string {
+	switch kind {
+	case string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+		string(libsveltosv1beta1.SecretReferencedResourceKind):
+		return corev1.SchemeGroupVersion.String()
+	case sourcev1.GitRepositoryKind:
+		return sourcev1.GroupVersion.String()
+	case sourcev1b2.OCIRepositoryKind, sourcev1b2.BucketKind:
+		return sourcev1b2.GroupVersion.String()
+	default:
+		return ""
 	}
+}
 
-	config += getDataSectionHash(configmap.Data)
-	config += getDataSectionHash(configmap.BinaryData)
+// writeReferenceHashKey writes a string identifying a referenced resource into w. It is included
+// in hashes so that referencing a different resource with identical content is still detected.
+func writeReferenceHashKey(w io.Writer, apiVersion, kind, namespace, name string) {
+	fmt.Fprintf(w, "%s:%s:%s/%s;", apiVersion, kind, namespace, name)
+}
 
-	return config
+// writeMissingReferenceHash writes the hash contribution of a referenced resource which does
+// not exist. Having a distinct state guarantees hash changes once the resource is created.
+func writeMissingReferenceHash(w io.Writer, apiVersion, kind, namespace, name string) {
+	writeReferenceHashKey(w, apiVersion, kind, namespace, name)
+	fmt.Fprint(w, "<missing>;")
 }
 
-func getSecretHash(secret *corev1.Secret) string {
-	var config string
-	if secret.Annotations != nil {
-		config += getDataSectionHash(secret.Annotations)
+func writeConfigMapHash(w io.Writer, configmap *corev1.ConfigMap) {
+	if configmap.Annotations != nil {
+		writeDataSectionHash(w, configmap.Annotations)
 	}
 
-	config += getDataSectionHash(secret.Data)
-	config += getDataSectionHash(secret.StringData)
+	writeDataSectionHash(w, configmap.Data)
+	writeDataSectionHash(w, configmap.BinaryData)
+}
+
+func writeSecretHash(w io.Writer, secret *corev1.Secret) {
+	if secret.Annotations != nil {
+		writeDataSectionHash(w, secret.Annotations)
+	}
 
-	return config
+	writeDataSectionHash(w, secret.Data)
+	writeDataSectionHash(w, secret.StringData)
 }
 
-// getDataSectionHash sorts map and return the hash
-func getDataSectionHash[T any](data map[string]T) string {
-	var keys []string
+// writeDataSectionHash sorts map and writes its content into w, which is usually a hash.Hash.
+// Both keys and values are considered, so renaming a key changes the hash. Content is written
+// incrementally so large ConfigMaps/Secrets are never copied into a single in-memory string.
+func writeDataSectionHash[T any](w io.Writer, data map[string]T) {
+	keys := make([]string, 0, len(data))
 	for k := range data {
 		keys = append(keys, k)
 	}
@@ -362,12 +511,16 @@ func getDataSectionHash[T any](data map[string]T) string {
 	// Sort keys (ascending order)
 	sort.Strings(keys)
 
-	var config string
 	for i := range keys {
-		config += render.AsCode(data[keys[i]])
+		switch v := any(data[keys[i]]).(type) {
+		case string:
+			fmt.Fprintf(w, "%s=%d:%s;", keys[i], len(v), v)
+		case []byte:
+			fmt.Fprintf(w, "%s=%d:%s;", keys[i], len(v), v)
+		default:
+			fmt.Fprintf(w, "%s=%s;", keys[i], render.AsCode(v))
+		}
 	}
-
-	return config
 }
 
 // stringifyMap converts a map[string]string to a string representation
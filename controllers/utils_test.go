@@ -19,6 +19,7 @@ package controllers_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -29,6 +30,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -247,6 +249,22 @@ var _ = Describe("getClusterProfileOwner ", func() {
 		Expect(result).To(ContainElement(ref3))
 	})
 
+	It("getEntryKey includes apiVersion and kind", func() {
+		namespace := randomString()
+		name := randomString()
+
+		configMapKey := controllers.GetEntryKey("v1", "ConfigMap", namespace, name)
+		secretKey := controllers.GetEntryKey("v1", "Secret", namespace, name)
+		Expect(configMapKey).ToNot(Equal(secretKey))
+
+		v1Key := controllers.GetEntryKey("source.toolkit.fluxcd.io/v1", sourcev1.GitRepositoryKind, namespace, name)
+		v1beta2Key := controllers.GetEntryKey("source.toolkit.fluxcd.io/v1beta2", sourcev1.GitRepositoryKind,
+			namespace, name)
+		Expect(v1Key).ToNot(Equal(v1beta2Key))
+
+		Expect(controllers.GetEntryKey("v1", "Namespace", "", name)).To(Equal("v1:Namespace:" + name))
+	})
+
 	It("getClusterProfileOwner returns nil when ClusterProfile does not exist", func() {
 		Expect(addTypeInformationToObject(testEnv.Scheme(), clusterProfile)).To(Succeed())
 
@@ -303,6 +321,37 @@ var _ = Describe("getClusterProfileOwner ", func() {
 		Expect(currentClusterSummary.Name).To(Equal(clusterSummary.Name))
 	})
 
+	It("GetClusterSummaryName returns unique names not exceeding maximum length", func() {
+		profileName := randomString()
+		clusterName := randomString()
+		Expect(controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, profileName, clusterName, false)).To(
+			Equal(fmt.Sprintf("%s-capi-%s", profileName, clusterName)))
+
+		longProfileName := strings.Repeat("a", 250)
+		name := controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, longProfileName, clusterName, false)
+		Expect(len(name)).To(Equal(validation.LabelValueMaxLength))
+		Expect(validation.IsValidLabelValue(name)).To(BeEmpty())
+		Expect(controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, longProfileName, clusterName, false)).To(
+			Equal(name))
+		Expect(controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, longProfileName, randomString(), false)).ToNot(
+			Equal(name))
+		Expect(controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, longProfileName, clusterName, true)).ToNot(
+			Equal(name))
+
+		// Names longer than label value limit are shortened as well
+		profileName = strings.Repeat("b", 50)
+		name = controllers.GetClusterSummaryName(configv1beta1.ProfileKind, profileName, clusterName, false)
+		Expect(len(name)).To(BeNumerically("<=", validation.LabelValueMaxLength))
+		Expect(validation.IsValidLabelValue(name)).To(BeEmpty())
+
+		// Trailing non alphanumeric characters are removed before appending the hash
+		profileName = strings.Repeat("c", 51)
+		name = controllers.GetClusterSummaryName(configv1beta1.ClusterProfileKind, profileName, clusterName, false)
+		Expect(name).To(HavePrefix(profileName + "-"))
+		Expect(name).ToNot(ContainSubstring("--"))
+		Expect(validation.IsValidLabelValue(name)).To(BeEmpty())
+	})
+
 	It("isNamespaced returns true for namespaced resources", func() {
 		clusterRole, err := k8s_utils.GetUnstructured([]byte(fmt.Sprintf(viewClusterRole, randomString())))
 		Expect(err).To(BeNil())
@@ -68,36 +68,36 @@ func validateHealthPolicy(ctx context.Context, remoteConfig *rest.Config, check
 	l := logger.WithValues("validation", check.Name)
 	l.V(logs.LogDebug).Info("running health validation")
 
-	list, err := fetchResources(ctx, remoteConfig, check)
-	if err != nil {
-		return err
-	}
-
-	if list == nil || len(list.Items) == 0 {
-		return fmt.Errorf("did not fetch any resource")
-	}
-
-	for i := range list.Items {
-		l = l.WithValues("resource", fmt.Sprintf("%s/%s", list.Items[i].GetNamespace(), list.Items[i].GetName()))
-		l.V(logs.LogDebug).Info("examing resource's health")
-		var healthy bool
-		var msg string
-		healthy, msg, err = isHealthy(&list.Items[i], check.Script, logger)
+	found := false
+	err := fetchResources(ctx, remoteConfig, check, func(resource *unstructured.Unstructured) error {
+		found = true
+		resourceLogger := l.WithValues("resource", fmt.Sprintf("%s/%s", resource.GetNamespace(), resource.GetName()))
+		resourceLogger.V(logs.LogDebug).Info("examing resource's health")
+		healthy, msg, err := isHealthy(resource, check.Script, logger)
 		if err != nil {
 			return err
 		}
 		if !healthy {
-			l.V(logs.LogInfo).Info("resource is not healthy")
+			resourceLogger.V(logs.LogInfo).Info("resource is not healthy")
 			return fmt.Errorf("%s", msg)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("did not fetch any resource")
 	}
 
 	return nil
 }
 
-// fetchResources fetches resources from the managed cluster
+// fetchResources fetches, one page at a time, resources from the managed cluster and invokes fn
+// for each of them
 func fetchResources(ctx context.Context, remoteConfig *rest.Config, check *configv1beta1.ValidateHealth,
-) (*unstructured.UnstructuredList, error) {
+	fn func(resource *unstructured.Unstructured) error) error {
 
 	gvk := schema.GroupVersionKind{
 		Group:   check.Group,
@@ -108,7 +108,7 @@ func fetchResources(ctx context.Context, remoteConfig *rest.Config, check *confi
 	dc := discovery.NewDiscoveryClientForConfigOrDie(remoteConfig)
 	groupResources, err := restmapper.GetAPIGroupResources(dc)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
 
@@ -117,9 +117,9 @@ func fetchResources(ctx context.Context, remoteConfig *rest.Config, check *confi
 	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		if meta.IsNoMatchError(err) {
-			return nil, nil
+			return nil
 		}
-		return nil, err
+		return err
 	}
 
 	resourceId := schema.GroupVersionResource{
@@ -154,12 +154,7 @@ func fetchResources(ctx context.Context, remoteConfig *rest.Config, check *confi
 		options.FieldSelector += fmt.Sprintf("metadata.namespace=%s", check.Namespace)
 	}
 
-	list, err := d.Resource(resourceId).List(ctx, options)
-	if err != nil {
-		return nil, err
-	}
-
-	return list, nil
+	return forEachUnstructured(ctx, d.Resource(resourceId), options, fn)
 }
 
 // isHealthy verifies whether resource is healthy according to Lua script
@@ -106,11 +106,16 @@ var _ = Describe("Lua Health Policies", func() {
 			},
 		}
 
-		result, err := controllers.FetchResources(context.TODO(), testEnv.Config, check)
+		result := make([]unstructured.Unstructured, 0)
+		err := controllers.FetchResources(context.TODO(), testEnv.Config, check,
+			func(resource *unstructured.Unstructured) error {
+				result = append(result, *resource)
+				return nil
+			})
 		Expect(err).To(BeNil())
-		Expect(len(result.Items)).To(Equal(1))
-		Expect(result.Items[0].GetNamespace()).To(Equal(namespace))
-		Expect(result.Items[0].GetName()).To(Equal(pod1.Name))
+		Expect(len(result)).To(Equal(1))
+		Expect(result[0].GetNamespace()).To(Equal(namespace))
+		Expect(result[0].GetName()).To(Equal(pod1.Name))
 	})
 
 	It("Verify all lua policies", func() {
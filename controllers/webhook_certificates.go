@@ -0,0 +1,227 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update
+
+const (
+	webhookCertName = "tls.crt"
+	webhookKeyName  = "tls.key"
+
+	webhookCAValidity   = 10 * 365 * 24 * time.Hour
+	webhookCertValidity = 365 * 24 * time.Hour
+	// webhookCertRotation is how long before expiration certificates are regenerated
+	webhookCertRotation = 30 * 24 * time.Hour
+	// webhookCertCheckInterval is how often certificate expiration is checked
+	webhookCertCheckInterval = 24 * time.Hour
+
+	webhookRSAKeySize = 2048
+)
+
+// WebhookCertificateOptions contains the information needed to generate the webhook
+// serving certificate and to inject its CA in the ValidatingWebhookConfiguration
+type WebhookCertificateOptions struct {
+	// CertDir is the directory the webhook server loads tls.crt and tls.key from
+	CertDir string
+
+	// ServiceName and ServiceNamespace identify the Service in front of the webhook server
+	ServiceName      string
+	ServiceNamespace string
+
+	// WebhookConfigurationName is the name of the ValidatingWebhookConfiguration whose
+	// caBundle is managed
+	WebhookConfigurationName string
+}
+
+// SetupWebhookCertificates generates a self-signed CA and a serving certificate for the webhook
+// Service, writes the certificate in CertDir and injects the CA in the ValidatingWebhookConfiguration.
+// Certificates are then regenerated, till ctx is canceled, when close to expiration. The webhook
+// server reloads certificates when those change on disk.
+// With more than one replica, cert-manager should be used instead, as each replica would
+// generate its own CA.
+func SetupWebhookCertificates(ctx context.Context, c client.Client, o WebhookCertificateOptions,
+	logger logr.Logger) error {
+
+	if err := rotateWebhookCertificates(ctx, c, o, time.Now()); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(webhookCertCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !webhookCertificateNeedsRotation(o.CertDir, time.Now()) {
+					continue
+				}
+				logger.V(logs.LogInfo).Info("rotating webhook certificates")
+				if err := rotateWebhookCertificates(ctx, c, o, time.Now()); err != nil {
+					logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to rotate webhook certificates: %v", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func rotateWebhookCertificates(ctx context.Context, c client.Client, o WebhookCertificateOptions,
+	now time.Time) error {
+
+	caPEM, certPEM, keyPEM, err := generateWebhookCertificates(o.ServiceName, o.ServiceNamespace, now)
+	if err != nil {
+		return err
+	}
+
+	if err := injectWebhookCABundle(ctx, c, o.WebhookConfigurationName, caPEM); err != nil {
+		return err
+	}
+
+	return writeWebhookCertificates(o.CertDir, certPEM, keyPEM)
+}
+
+// generateWebhookCertificates returns, PEM encoded, a self-signed CA along with a certificate,
+// signed by that CA, and its key valid for the webhook Service DNS names
+func generateWebhookCertificates(serviceName, serviceNamespace string, now time.Time) (caPEM, certPEM,
+	keyPEM []byte, err error) {
+
+	caKey, err := rsa.GenerateKey(rand.Reader, webhookRSAKeySize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s-ca", serviceName)},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(webhookCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, webhookRSAKeySize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dnsNames := []string{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, serviceNamespace),
+		fmt.Sprintf("%s.%s.svc", serviceName, serviceNamespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, serviceNamespace),
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: dnsNames[2]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(webhookCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return caPEM, certPEM, keyPEM, nil
+}
+
+func writeWebhookCertificates(certDir string, certPEM, keyPEM []byte) error {
+	const permission0700 = 0700
+	if err := os.MkdirAll(certDir, permission0700); err != nil {
+		return err
+	}
+
+	const permission0600 = 0600
+	if err := os.WriteFile(filepath.Join(certDir, webhookKeyName), keyPEM, permission0600); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(certDir, webhookCertName), certPEM, permission0600)
+}
+
+// injectWebhookCABundle sets caBundle of all webhooks in the ValidatingWebhookConfiguration
+func injectWebhookCABundle(ctx context.Context, c client.Client, name string, caPEM []byte) error {
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, webhookConfiguration); err != nil {
+		return err
+	}
+
+	for i := range webhookConfiguration.Webhooks {
+		webhookConfiguration.Webhooks[i].ClientConfig.CABundle = caPEM
+	}
+
+	return c.Update(ctx, webhookConfiguration)
+}
+
+// webhookCertificateNeedsRotation returns true if the certificate in certDir is missing, cannot
+// be parsed or expires within webhookCertRotation
+func webhookCertificateNeedsRotation(certDir string, now time.Time) bool {
+	certPEM, err := os.ReadFile(filepath.Join(certDir, webhookCertName))
+	if err != nil {
+		return true
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return true
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+
+	return now.Add(webhookCertRotation).After(cert.NotAfter)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/addon-controller/controllers"
+)
+
+var _ = Describe("Webhook certificates", func() {
+	It("generateWebhookCertificates returns a certificate valid for the webhook service", func() {
+		serviceName := randomString()
+		serviceNamespace := randomString()
+
+		caPEM, certPEM, keyPEM, err := controllers.GenerateWebhookCertificates(serviceName, serviceNamespace,
+			time.Now())
+		Expect(err).To(BeNil())
+
+		_, err = tls.X509KeyPair(certPEM, keyPEM)
+		Expect(err).To(BeNil())
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(caPEM)).To(BeTrue())
+
+		block, _ := pem.Decode(certPEM)
+		Expect(block).ToNot(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).To(BeNil())
+
+		_, err = cert.Verify(x509.VerifyOptions{
+			DNSName: serviceName + "." + serviceNamespace + ".svc",
+			Roots:   roots,
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("webhookCertificateNeedsRotation returns true when certificate is missing or about to expire", func() {
+		certDir := GinkgoT().TempDir()
+		Expect(controllers.WebhookCertificateNeedsRotation(certDir, time.Now())).To(BeTrue())
+
+		_, certPEM, keyPEM, err := controllers.GenerateWebhookCertificates(randomString(), randomString(),
+			time.Now())
+		Expect(err).To(BeNil())
+		Expect(controllers.WriteWebhookCertificates(certDir, certPEM, keyPEM)).To(Succeed())
+
+		Expect(controllers.WebhookCertificateNeedsRotation(certDir, time.Now())).To(BeFalse())
+		const days = 360
+		Expect(controllers.WebhookCertificateNeedsRotation(certDir,
+			time.Now().Add(days*24*time.Hour))).To(BeTrue())
+	})
+
+	It("injectWebhookCABundle sets caBundle on all webhooks", func() {
+		webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{Name: randomString() + ".projectsveltos.io"},
+				{Name: randomString() + ".projectsveltos.io"},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhookConfiguration).Build()
+
+		caBundle := []byte(randomString())
+		Expect(controllers.InjectWebhookCABundle(context.TODO(), c, webhookConfiguration.Name,
+			caBundle)).To(Succeed())
+
+		currentConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: webhookConfiguration.Name},
+			currentConfiguration)).To(Succeed())
+		for i := range currentConfiguration.Webhooks {
+			Expect(currentConfiguration.Webhooks[i].ClientConfig.CABundle).To(Equal(caBundle))
+		}
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates of the addon-controller.
+// New capabilities can be shipped disabled by default and enabled per environment
+// with --feature-gates (e.g. --feature-gates=Jsonnet=true,CUE=false).
+package features
+
+import (
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// Jsonnet enables evaluating, as Jsonnet, ConfigMaps/Secrets/Flux Sources
+	// annotated with projectsveltos.io/jsonnet
+	Jsonnet featuregate.Feature = "Jsonnet"
+
+	// CUE enables evaluating, as CUE, ConfigMaps/Secrets/Flux Sources
+	// annotated with projectsveltos.io/cue
+	CUE featuregate.Feature = "CUE"
+)
+
+var (
+	// MutableGates is the mutable version of Gates, used to set gates from flags
+	MutableGates featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+	// Gates is the shared feature gate
+	Gates featuregate.FeatureGate = MutableGates
+)
+
+// defaultFeatureGates lists all known features and their default state.
+// To add a new feature, define a key for it above and add it here.
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	Jsonnet: {Default: true, PreRelease: featuregate.Beta},
+	CUE:     {Default: true, PreRelease: featuregate.Beta},
+}
+
+//nolint:gochecknoinits // forced pattern, can't workaround
+func init() {
+	utilruntime.Must(MutableGates.Add(defaultFeatureGates))
+}
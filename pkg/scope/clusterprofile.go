@@ -18,6 +18,7 @@ package scope
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/pkg/set"
 )
 
 // ProfileScopeParams defines the input parameters used to create a new Profile Scope.
@@ -117,6 +119,67 @@ func (s *ProfileScope) SetMatchingClusterRefs(matchingClusters []corev1.ObjectRe
 	status.MatchingClusterRefs = matchingClusters
 }
 
+// SetObservedGeneration records current Profile generation as fully processed.
+func (s *ProfileScope) SetObservedGeneration() {
+	s.GetStatus().ObservedGeneration = s.Profile.GetGeneration()
+}
+
+// StartRollout resets the list of updated clusters if those were updated to a Spec
+// whose hash is different than hash.
+func (s *ProfileScope) StartRollout(hash []byte) {
+	status := s.GetStatus()
+	if !reflect.DeepEqual(status.UpdatedClusters.Hash, hash) {
+		status.UpdatedClusters = configv1beta1.Clusters{
+			Hash:     hash,
+			Clusters: []corev1.ObjectReference{},
+		}
+	}
+}
+
+// AddUpdatingCluster records cluster as being updated to the Spec whose hash is hash.
+func (s *ProfileScope) AddUpdatingCluster(cluster *corev1.ObjectReference, hash []byte) {
+	status := s.GetStatus()
+	status.UpdatingClusters.Hash = hash
+	key := set.KeyFromObjectReference(cluster)
+	for i := range status.UpdatingClusters.Clusters {
+		if set.KeyFromObjectReference(&status.UpdatingClusters.Clusters[i]) == key {
+			return
+		}
+	}
+	status.UpdatingClusters.Clusters = append(status.UpdatingClusters.Clusters, *cluster)
+}
+
+// CompleteRollout resets updated and updating clusters once all matching clusters are updated.
+func (s *ProfileScope) CompleteRollout() {
+	status := s.GetStatus()
+	status.UpdatedClusters = configv1beta1.Clusters{}
+	status.UpdatingClusters = configv1beta1.Clusters{}
+}
+
+// RemoveNonMatchingClusters removes from updated and updating clusters those not
+// matching the Profile anymore.
+func (s *ProfileScope) RemoveNonMatchingClusters() {
+	status := s.GetStatus()
+
+	matchingClusters := &set.ObjectSet{}
+	for i := range status.MatchingClusterRefs {
+		matchingClusters.Insert(set.KeyFromObjectReference(&status.MatchingClusterRefs[i]))
+	}
+
+	status.UpdatedClusters.Clusters = filterClusters(status.UpdatedClusters.Clusters, matchingClusters)
+	status.UpdatingClusters.Clusters = filterClusters(status.UpdatingClusters.Clusters, matchingClusters)
+}
+
+func filterClusters(clusters []corev1.ObjectReference, keep *set.ObjectSet) []corev1.ObjectReference {
+	result := make([]corev1.ObjectReference, 0, len(clusters))
+	for i := range clusters {
+		if keep.Has(set.KeyFromObjectReference(&clusters[i])) {
+			result = append(result, clusters[i])
+		}
+	}
+	return result
+}
+
 // IsContinuousSync returns true if Profile is set to keep updating workload cluster
 func (s *ProfileScope) IsContinuousSync() bool {
 	spec := s.GetSpec()
@@ -225,6 +225,60 @@ var _ = Describe("ProfileScope/ClusterProfileScope", func() {
 		Expect(reflect.DeepEqual(profile.Status.MatchingClusterRefs, matchingClusters)).To(BeTrue())
 	})
 
+	It("Rollout helpers track updating and updated clusters", func() {
+		cpParams := scope.ProfileScopeParams{
+			Client:  c,
+			Profile: clusterProfile,
+			Logger:  textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		cpScope, err := scope.NewProfileScope(cpParams)
+		Expect(err).To(BeNil())
+
+		matching := corev1.ObjectReference{Namespace: randomString(), Name: randomString(),
+			Kind: libsveltosv1beta1.SveltosClusterKind, APIVersion: libsveltosv1beta1.GroupVersion.String()}
+		nonMatching := corev1.ObjectReference{Namespace: randomString(), Name: randomString(),
+			Kind: libsveltosv1beta1.SveltosClusterKind, APIVersion: libsveltosv1beta1.GroupVersion.String()}
+		cpScope.SetMatchingClusterRefs([]corev1.ObjectReference{matching})
+
+		hash := []byte(randomString())
+		clusterProfile.Status.UpdatedClusters = configv1beta1.Clusters{
+			Hash:     []byte(randomString()),
+			Clusters: []corev1.ObjectReference{matching},
+		}
+		cpScope.StartRollout(hash)
+		Expect(clusterProfile.Status.UpdatedClusters.Hash).To(Equal(hash))
+		Expect(clusterProfile.Status.UpdatedClusters.Clusters).To(BeEmpty())
+
+		cpScope.AddUpdatingCluster(&matching, hash)
+		cpScope.AddUpdatingCluster(&matching, hash)
+		cpScope.AddUpdatingCluster(&nonMatching, hash)
+		Expect(clusterProfile.Status.UpdatingClusters.Hash).To(Equal(hash))
+		Expect(len(clusterProfile.Status.UpdatingClusters.Clusters)).To(Equal(2))
+
+		cpScope.RemoveNonMatchingClusters()
+		Expect(clusterProfile.Status.UpdatingClusters.Clusters).To(Equal([]corev1.ObjectReference{matching}))
+
+		cpScope.CompleteRollout()
+		Expect(clusterProfile.Status.UpdatingClusters.Clusters).To(BeEmpty())
+		Expect(clusterProfile.Status.UpdatedClusters.Clusters).To(BeEmpty())
+	})
+
+	It("SetObservedGeneration records Profile generation", func() {
+		profile.Generation = 5
+		params := scope.ProfileScopeParams{
+			Client:  c,
+			Profile: profile,
+			Logger:  textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		profileScope, err := scope.NewProfileScope(params)
+		Expect(err).To(BeNil())
+
+		profileScope.SetObservedGeneration()
+		Expect(profile.Status.ObservedGeneration).To(Equal(int64(5)))
+	})
+
 	It("Close updates ClusterProfile", func() {
 		objects := []client.Object{clusterProfile, profile}
 		for i := range objects {
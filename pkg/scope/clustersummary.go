@@ -18,11 +18,16 @@ package scope
 
 import (
 	"context"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/cluster-api/util/patch"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
@@ -47,16 +52,12 @@ func NewClusterSummaryScope(params *ClusterSummaryScopeParams) (*ClusterSummaryS
 		return nil, errors.New("failed to generate new scope from nil ClusterSummary")
 	}
 
-	helper, err := patch.NewHelper(params.ClusterSummary, params.Client)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to init patch helper")
-	}
 	return &ClusterSummaryScope{
 		Logger:         params.Logger,
 		client:         params.Client,
 		Profile:        params.Profile,
 		ClusterSummary: params.ClusterSummary,
-		patchHelper:    helper,
+		original:       params.ClusterSummary.DeepCopy(),
 		controllerName: params.ControllerName,
 	}, nil
 }
@@ -65,18 +66,229 @@ func NewClusterSummaryScope(params *ClusterSummaryScopeParams) (*ClusterSummaryS
 type ClusterSummaryScope struct {
 	logr.Logger
 	client         client.Client
-	patchHelper    *patch.Helper
 	Profile        client.Object
 	ClusterSummary *configv1beta1.ClusterSummary
 	controllerName string
+
+	// original is the ClusterSummary as it was when the scope was created (or last persisted).
+	// Used to compute patches and, on conflict, to identify the changes made by this reconciliation.
+	original *configv1beta1.ClusterSummary
+
+	// statusMutex serializes access to ClusterSummary Status when
+	// features are processed concurrently.
+	statusMutex sync.Mutex
+}
+
+// LockStatus acquires exclusive access to ClusterSummary Status.
+func (s *ClusterSummaryScope) LockStatus() {
+	s.statusMutex.Lock()
+}
+
+// UnlockStatus releases access to ClusterSummary Status acquired with LockStatus.
+func (s *ClusterSummaryScope) UnlockStatus() {
+	s.statusMutex.Unlock()
 }
 
 // PatchObject persists the cluster configuration and status.
+// Status is patched first, using the resourceVersion the scope was created with as optimistic
+// lock. On conflict, ClusterSummary is read again and only the Status changes made by this
+// reconciliation are applied on top of the current Status, which is then patched again.
+// Metadata and Spec changes are then patched, as merge patch, on top of the current object.
 func (s *ClusterSummaryScope) PatchObject(ctx context.Context) error {
-	return s.patchHelper.Patch(
-		ctx,
-		s.ClusterSummary,
-	)
+	desired := s.ClusterSummary.DeepCopy()
+
+	latest, err := s.patchStatus(ctx, desired)
+	if err != nil {
+		return err
+	}
+
+	latest, err = s.patchMetadataAndSpec(ctx, desired, latest)
+	if err != nil {
+		return err
+	}
+
+	if latest != nil {
+		*s.ClusterSummary = *latest
+		s.original = latest.DeepCopy()
+	}
+	return nil
+}
+
+// patchStatus patches the Status changes made by this reconciliation. Returns the ClusterSummary
+// as persisted or nil if Status has not changed.
+func (s *ClusterSummaryScope) patchStatus(ctx context.Context, desired *configv1beta1.ClusterSummary,
+) (*configv1beta1.ClusterSummary, error) {
+
+	if reflect.DeepEqual(s.original.Status, desired.Status) {
+		return nil, nil
+	}
+
+	obj := s.original.DeepCopy()
+	desired.Status.DeepCopyInto(&obj.Status)
+	err := s.client.Status().Patch(ctx, obj, getStatusPatch(s.original))
+	if err == nil {
+		return obj, nil
+	}
+	if !apierrors.IsConflict(err) {
+		return nil, err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &configv1beta1.ClusterSummary{}
+		if err := s.client.Get(ctx, client.ObjectKeyFromObject(s.ClusterSummary), current); err != nil {
+			return err
+		}
+
+		obj = current.DeepCopy()
+		obj.Status = applyStatusChanges(&current.Status, &s.original.Status, &desired.Status)
+		return s.client.Status().Patch(ctx, obj, getStatusPatch(current))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// patchMetadataAndSpec patches the metadata and Spec changes made by this reconciliation.
+// Returns the ClusterSummary as persisted, or latest if nothing has changed.
+func (s *ClusterSummaryScope) patchMetadataAndSpec(ctx context.Context, desired, latest *configv1beta1.ClusterSummary,
+) (*configv1beta1.ClusterSummary, error) {
+
+	base := s.original.DeepCopy()
+	base.Status = configv1beta1.ClusterSummaryStatus{}
+	obj := desired.DeepCopy()
+	obj.Status = configv1beta1.ClusterSummaryStatus{}
+	if reflect.DeepEqual(base, obj) {
+		return latest, nil
+	}
+
+	// No optimistic lock: same as for the Status, only changes made by this reconciliation are sent
+	base.ResourceVersion = ""
+	obj.ResourceVersion = ""
+	if err := s.client.Patch(ctx, obj, client.MergeFrom(base)); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// getStatusPatch returns a merge patch using from resourceVersion, when set, as optimistic lock
+func getStatusPatch(from *configv1beta1.ClusterSummary) client.Patch {
+	if from.ResourceVersion == "" {
+		return client.MergeFrom(from)
+	}
+	return client.MergeFromWithOptions(from, client.MergeFromWithOptimisticLock{})
+}
+
+// applyStatusChanges returns current Status with the changes from original to desired applied.
+// FeatureSummaries and Conditions are merged per entry, so entries this reconciliation did not
+// touch are kept as currently stored. Any other field changed from original is replaced.
+func applyStatusChanges(current, original, desired *configv1beta1.ClusterSummaryStatus,
+) configv1beta1.ClusterSummaryStatus {
+
+	result := current.DeepCopy()
+
+	result.FeatureSummaries = applyFeatureSummaryChanges(current.FeatureSummaries,
+		original.FeatureSummaries, desired.FeatureSummaries)
+	result.Conditions = applyConditionChanges(current.Conditions, original.Conditions, desired.Conditions)
+
+	if !reflect.DeepEqual(original.Dependencies, desired.Dependencies) {
+		result.Dependencies = desired.DeepCopy().Dependencies
+	}
+	if !reflect.DeepEqual(original.DeployedGVKs, desired.DeployedGVKs) {
+		result.DeployedGVKs = desired.DeepCopy().DeployedGVKs
+	}
+	if !reflect.DeepEqual(original.HelmReleaseSummaries, desired.HelmReleaseSummaries) {
+		result.HelmReleaseSummaries = desired.DeepCopy().HelmReleaseSummaries
+	}
+	if !reflect.DeepEqual(original.InvalidReferences, desired.InvalidReferences) {
+		result.InvalidReferences = desired.DeepCopy().InvalidReferences
+	}
+
+	return *result
+}
+
+// applyConditionChanges returns current Conditions with the changes from original to desired applied.
+func applyConditionChanges(current, original, desired []metav1.Condition) []metav1.Condition {
+	result := make([]metav1.Condition, len(current))
+	for i := range current {
+		current[i].DeepCopyInto(&result[i])
+	}
+
+	for i := range desired {
+		originalCondition := meta.FindStatusCondition(original, desired[i].Type)
+		if originalCondition != nil && reflect.DeepEqual(*originalCondition, desired[i]) {
+			continue
+		}
+		meta.RemoveStatusCondition(&result, desired[i].Type)
+		result = append(result, *desired[i].DeepCopy())
+	}
+
+	for i := range original {
+		if meta.FindStatusCondition(desired, original[i].Type) == nil {
+			meta.RemoveStatusCondition(&result, original[i].Type)
+		}
+	}
+
+	return result
+}
+
+// applyFeatureSummaryChanges returns current FeatureSummaries with the changes from original
+// to desired applied: entries added or modified in desired replace the current ones, entries
+// removed from desired are removed. All other current entries are kept.
+func applyFeatureSummaryChanges(current, original, desired []configv1beta1.FeatureSummary,
+) []configv1beta1.FeatureSummary {
+
+	result := copyFeatureSummaries(current)
+
+	for i := range desired {
+		originalFS := getFeatureSummary(original, desired[i].FeatureID)
+		if originalFS != nil && reflect.DeepEqual(*originalFS, desired[i]) {
+			continue
+		}
+
+		if currentFS := getFeatureSummary(result, desired[i].FeatureID); currentFS != nil {
+			*currentFS = *desired[i].DeepCopy()
+		} else {
+			result = append(result, *desired[i].DeepCopy())
+		}
+	}
+
+	for i := range original {
+		if getFeatureSummary(desired, original[i].FeatureID) != nil {
+			continue
+		}
+		for j := range result {
+			if result[j].FeatureID == original[i].FeatureID {
+				result = append(result[:j], result[j+1:]...)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func getFeatureSummary(featureSummaries []configv1beta1.FeatureSummary, featureID configv1beta1.FeatureID,
+) *configv1beta1.FeatureSummary {
+
+	for i := range featureSummaries {
+		if featureSummaries[i].FeatureID == featureID {
+			return &featureSummaries[i]
+		}
+	}
+	return nil
+}
+
+func copyFeatureSummaries(featureSummaries []configv1beta1.FeatureSummary) []configv1beta1.FeatureSummary {
+	if featureSummaries == nil {
+		return nil
+	}
+
+	result := make([]configv1beta1.FeatureSummary, len(featureSummaries))
+	for i := range featureSummaries {
+		featureSummaries[i].DeepCopyInto(&result[i])
+	}
+	return result
 }
 
 // Close closes the current scope persisting the clusterprofile configuration and status.
@@ -171,6 +383,28 @@ func (s *ClusterSummaryScope) SetFailureReason(featureID configv1beta1.FeatureID
 	)
 }
 
+// SetFailedResources sets the resources which failed to be deployed.
+func (s *ClusterSummaryScope) SetFailedResources(featureID configv1beta1.FeatureID,
+	failedResources []configv1beta1.ResourceFailure) {
+
+	for i := range s.ClusterSummary.Status.FeatureSummaries {
+		if s.ClusterSummary.Status.FeatureSummaries[i].FeatureID == featureID {
+			s.ClusterSummary.Status.FeatureSummaries[i].FailedResources = failedResources
+			return
+		}
+	}
+
+	s.initializeFeatureStatusSummary()
+
+	s.ClusterSummary.Status.FeatureSummaries = append(
+		s.ClusterSummary.Status.FeatureSummaries,
+		configv1beta1.FeatureSummary{
+			FeatureID:       featureID,
+			FailedResources: failedResources,
+		},
+	)
+}
+
 func (s *ClusterSummaryScope) SetLastAppliedTime(featureID configv1beta1.FeatureID,
 	lastAppliedTime *metav1.Time) {
 
@@ -192,6 +426,74 @@ func (s *ClusterSummaryScope) SetLastAppliedTime(featureID configv1beta1.Feature
 	)
 }
 
+// MarkFeatureProvisioned sets the feature as provisioned with hash, clearing any failure, and
+// records now as last applied time.
+func (s *ClusterSummaryScope) MarkFeatureProvisioned(featureID configv1beta1.FeatureID, hash []byte) {
+	s.markFeature(featureID, configv1beta1.FeatureStatusProvisioned, hash)
+	s.clearFeatureFailure(featureID)
+}
+
+// MarkFeatureRemoved sets the feature as removed with hash, clearing any failure, and
+// records now as last applied time.
+func (s *ClusterSummaryScope) MarkFeatureRemoved(featureID configv1beta1.FeatureID, hash []byte) {
+	s.markFeature(featureID, configv1beta1.FeatureStatusRemoved, hash)
+	s.clearFeatureFailure(featureID)
+}
+
+// MarkFeatureInProgress sets the feature status to status (either provisioning or removing)
+// with hash and records now as last applied time. Failure information is preserved.
+func (s *ClusterSummaryScope) MarkFeatureInProgress(featureID configv1beta1.FeatureID,
+	status configv1beta1.FeatureStatus, hash []byte) {
+
+	s.markFeature(featureID, status, hash)
+}
+
+// MarkFeatureFailed sets the feature status to status (either failed or failed non retriable)
+// with hash, records failure message, reason and failed resources and records now as last
+// applied time.
+func (s *ClusterSummaryScope) MarkFeatureFailed(featureID configv1beta1.FeatureID,
+	status configv1beta1.FeatureStatus, hash []byte, failureMessage string, failureReason *string,
+	failedResources []configv1beta1.ResourceFailure) {
+
+	s.markFeature(featureID, status, hash)
+	s.SetFailureMessage(featureID, &failureMessage)
+	s.SetFailureReason(featureID, failureReason)
+	s.SetFailedResources(featureID, failedResources)
+}
+
+func (s *ClusterSummaryScope) markFeature(featureID configv1beta1.FeatureID,
+	status configv1beta1.FeatureStatus, hash []byte) {
+
+	now := metav1.NewTime(time.Now())
+	s.SetFeatureStatus(featureID, status, hash)
+	s.SetLastAppliedTime(featureID, &now)
+}
+
+func (s *ClusterSummaryScope) clearFeatureFailure(featureID configv1beta1.FeatureID) {
+	s.SetFailureMessage(featureID, nil)
+	s.SetFailureReason(featureID, nil)
+	s.SetFailedResources(featureID, nil)
+}
+
+// SetCondition sets a ClusterSummary condition. ObservedGeneration is set to the ClusterSummary
+// generation and LastTransitionTime is updated only when condition status changes.
+func (s *ClusterSummaryScope) SetCondition(conditionType string, status metav1.ConditionStatus,
+	reason, message string) {
+
+	meta.SetStatusCondition(&s.ClusterSummary.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: s.ClusterSummary.Generation,
+	})
+}
+
+// RemoveCondition removes a ClusterSummary condition, if present.
+func (s *ClusterSummaryScope) RemoveCondition(conditionType string) {
+	meta.RemoveStatusCondition(&s.ClusterSummary.Status.Conditions, conditionType)
+}
+
 // IsContinuousWithDriftDetection returns true if ClusterProfile is set to SyncModeContinuousWithDriftDetection
 func (s *ClusterSummaryScope) IsContinuousWithDriftDetection() bool {
 	return s.ClusterSummary.Spec.ClusterProfileSpec.SyncMode == configv1beta1.SyncModeContinuousWithDriftDetection
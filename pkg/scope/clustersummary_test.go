@@ -22,11 +22,13 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/textlogger"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/addon-controller/pkg/scope"
@@ -303,6 +305,31 @@ var _ = Describe("ClusterSummaryScope", func() {
 		Expect(*clusterSummary.Status.FeatureSummaries[0].FailureReason).To(Equal(failureReason))
 	})
 
+	It("SetFailedResources updates ClusterSummary Status FeatureSummary", func() {
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: clusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scope).ToNot(BeNil())
+
+		failedResources := []configv1beta1.ResourceFailure{
+			{APIVersion: "v1", Kind: "Service", Namespace: randomString(), Name: randomString(), Message: randomString()},
+		}
+		scope.SetFailedResources(configv1beta1.FeatureResources, failedResources)
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FeatureID).To(Equal(configv1beta1.FeatureResources))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailedResources).To(Equal(failedResources))
+
+		scope.SetFailedResources(configv1beta1.FeatureResources, nil)
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		Expect(clusterSummary.Status.FeatureSummaries[0].FailedResources).To(BeNil())
+	})
+
 	It("Close updates ClusterSummary", func() {
 		params := &scope.ClusterSummaryScopeParams{
 			Client:         c,
@@ -333,6 +360,266 @@ var _ = Describe("ClusterSummaryScope", func() {
 		Expect(len(currentClusterSummary.Status.FeatureSummaries)).To(Equal(1))
 	})
 
+	It("Close retries on real conflict merging FeatureSummaries", func() {
+		otherHash := []byte(randomString())
+		conflictReturned := false
+
+		scheme := setupScheme()
+		initObjects := []client.Object{clusterSummary, clusterProfile}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string,
+				obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+
+				if !conflictReturned {
+					conflictReturned = true
+					// Simulate another writer updating the Resources feature meanwhile
+					current := &configv1beta1.ClusterSummary{}
+					Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+					current.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+						{
+							FeatureID: configv1beta1.FeatureResources,
+							Status:    configv1beta1.FeatureStatusProvisioned,
+							Hash:      otherHash,
+						},
+					}
+					Expect(cl.Status().Update(ctx, current)).To(Succeed())
+					// Patch uses the resourceVersion read before as optimistic lock, so it fails
+					err := cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+					Expect(apierrors.IsConflict(err)).To(BeTrue())
+					return err
+				}
+				return cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: clusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+
+		scope.SetFeatureStatus(configv1beta1.FeatureHelm, configv1beta1.FeatureStatusProvisioned,
+			[]byte(randomString()))
+
+		Expect(scope.Close(context.TODO())).To(Succeed())
+		Expect(conflictReturned).To(BeTrue())
+
+		currentClusterSummary := &configv1beta1.ClusterSummary{}
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Namespace: clusterSummary.Namespace, Name: clusterSummary.Name},
+			currentClusterSummary)).To(Succeed())
+		Expect(len(currentClusterSummary.Status.FeatureSummaries)).To(Equal(2))
+		for i := range currentClusterSummary.Status.FeatureSummaries {
+			fs := &currentClusterSummary.Status.FeatureSummaries[i]
+			if fs.FeatureID == configv1beta1.FeatureResources {
+				Expect(fs.Hash).To(Equal(otherHash))
+			}
+		}
+	})
+
+	It("Close on conflict only applies FeatureSummaries changed by this reconciliation", func() {
+		originalHash := []byte(randomString())
+		otherHash := []byte(randomString())
+		helmHash := []byte(randomString())
+		const tier = 50
+		conflictReturned := false
+
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{FeatureID: configv1beta1.FeatureHelm, Status: configv1beta1.FeatureStatusProvisioning},
+			{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusProvisioned, Hash: originalHash},
+		}
+
+		scheme := setupScheme()
+		initObjects := []client.Object{clusterSummary, clusterProfile}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string,
+				obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+
+				if !conflictReturned {
+					conflictReturned = true
+					// Simulate another writer updating Spec and the Resources feature meanwhile
+					current := &configv1beta1.ClusterSummary{}
+					Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+					current.Spec.ClusterProfileSpec.Tier = tier
+					Expect(cl.Update(ctx, current)).To(Succeed())
+					current.Status.FeatureSummaries[1].Hash = otherHash
+					Expect(cl.Status().Update(ctx, current)).To(Succeed())
+					// Patch uses the resourceVersion read before as optimistic lock, so it fails
+					err := cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+					Expect(apierrors.IsConflict(err)).To(BeTrue())
+					return err
+				}
+				return cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		currentClusterSummary := &configv1beta1.ClusterSummary{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterSummary), currentClusterSummary)).To(Succeed())
+
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: currentClusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+
+		scope.SetFeatureStatus(configv1beta1.FeatureHelm, configv1beta1.FeatureStatusProvisioned, helmHash)
+
+		Expect(scope.Close(context.TODO())).To(Succeed())
+		Expect(conflictReturned).To(BeTrue())
+
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterSummary), currentClusterSummary)).To(Succeed())
+		Expect(currentClusterSummary.Spec.ClusterProfileSpec.Tier).To(Equal(int32(tier)))
+		Expect(len(currentClusterSummary.Status.FeatureSummaries)).To(Equal(2))
+		for i := range currentClusterSummary.Status.FeatureSummaries {
+			fs := &currentClusterSummary.Status.FeatureSummaries[i]
+			switch fs.FeatureID {
+			case configv1beta1.FeatureResources:
+				// Not changed by this reconciliation, so the concurrent update must be kept
+				Expect(fs.Hash).To(Equal(otherHash))
+			case configv1beta1.FeatureHelm:
+				Expect(fs.Status).To(Equal(configv1beta1.FeatureStatusProvisioned))
+				Expect(fs.Hash).To(Equal(helmHash))
+			}
+		}
+	})
+
+	It("Close on conflict keeps metadata and all other Status changes made by this reconciliation", func() {
+		conflictReturned := false
+
+		scheme := setupScheme()
+		initObjects := []client.Object{clusterSummary, clusterProfile}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string,
+				obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+
+				if !conflictReturned {
+					conflictReturned = true
+					// Simulate another writer updating the Resources feature meanwhile
+					current := &configv1beta1.ClusterSummary{}
+					Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+					current.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+						{FeatureID: configv1beta1.FeatureResources, Status: configv1beta1.FeatureStatusProvisioned},
+					}
+					Expect(cl.Status().Update(ctx, current)).To(Succeed())
+				}
+				return cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		currentClusterSummary := &configv1beta1.ClusterSummary{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterSummary), currentClusterSummary)).To(Succeed())
+
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: currentClusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+
+		annotation := randomString()
+		currentClusterSummary.Finalizers = append(currentClusterSummary.Finalizers, configv1beta1.ClusterSummaryFinalizer)
+		currentClusterSummary.Annotations = map[string]string{annotation: annotation}
+		scope.SetFeatureStatus(configv1beta1.FeatureHelm, configv1beta1.FeatureStatusProvisioned, []byte(randomString()))
+		scope.SetCondition(configv1beta1.DegradedCondition, metav1.ConditionFalse,
+			configv1beta1.FeaturesConvergedReason, "")
+		currentClusterSummary.Status.InvalidReferences = []configv1beta1.InvalidReference{
+			{Kind: "ConfigMap", Namespace: randomString(), Name: randomString(), Reason: configv1beta1.ReferenceNotFound},
+		}
+
+		Expect(scope.Close(context.TODO())).To(Succeed())
+		Expect(conflictReturned).To(BeTrue())
+
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterSummary), currentClusterSummary)).To(Succeed())
+		Expect(currentClusterSummary.Finalizers).To(ContainElement(configv1beta1.ClusterSummaryFinalizer))
+		Expect(currentClusterSummary.Annotations).To(HaveKeyWithValue(annotation, annotation))
+		Expect(currentClusterSummary.Status.FeatureSummaries).To(HaveLen(2))
+		Expect(currentClusterSummary.Status.Conditions).To(HaveLen(1))
+		Expect(currentClusterSummary.Status.InvalidReferences).To(HaveLen(1))
+	})
+
+	It("MarkFeatureFailed and MarkFeatureProvisioned manage failure information", func() {
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: clusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+
+		reason := randomString()
+		failedResources := []configv1beta1.ResourceFailure{
+			{Kind: "ConfigMap", Namespace: randomString(), Name: randomString(), Message: failedToDeploy},
+		}
+		hash := []byte(randomString())
+		scope.MarkFeatureFailed(configv1beta1.FeatureResources, configv1beta1.FeatureStatusFailed, hash,
+			failedToDeploy, &reason, failedResources)
+
+		Expect(len(clusterSummary.Status.FeatureSummaries)).To(Equal(1))
+		fs := &clusterSummary.Status.FeatureSummaries[0]
+		Expect(fs.Status).To(Equal(configv1beta1.FeatureStatusFailed))
+		Expect(fs.Hash).To(Equal(hash))
+		Expect(fs.FailureMessage).ToNot(BeNil())
+		Expect(*fs.FailureMessage).To(Equal(failedToDeploy))
+		Expect(fs.FailureReason).ToNot(BeNil())
+		Expect(*fs.FailureReason).To(Equal(reason))
+		Expect(fs.FailedResources).To(Equal(failedResources))
+		Expect(fs.LastAppliedTime).ToNot(BeNil())
+
+		scope.MarkFeatureInProgress(configv1beta1.FeatureResources, configv1beta1.FeatureStatusProvisioning, hash)
+		fs = &clusterSummary.Status.FeatureSummaries[0]
+		Expect(fs.Status).To(Equal(configv1beta1.FeatureStatusProvisioning))
+		Expect(fs.FailureMessage).ToNot(BeNil())
+
+		scope.MarkFeatureProvisioned(configv1beta1.FeatureResources, hash)
+		fs = &clusterSummary.Status.FeatureSummaries[0]
+		Expect(fs.Status).To(Equal(configv1beta1.FeatureStatusProvisioned))
+		Expect(fs.FailureMessage).To(BeNil())
+		Expect(fs.FailureReason).To(BeNil())
+		Expect(fs.FailedResources).To(BeNil())
+	})
+
+	It("SetCondition and RemoveCondition manage ClusterSummary conditions", func() {
+		clusterSummary.Generation = 3
+		params := &scope.ClusterSummaryScopeParams{
+			Client:         c,
+			Profile:        clusterProfile,
+			ClusterSummary: clusterSummary,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+		}
+
+		scope, err := scope.NewClusterSummaryScope(params)
+		Expect(err).ToNot(HaveOccurred())
+
+		scope.SetCondition(configv1beta1.PausedCondition, metav1.ConditionTrue,
+			configv1beta1.ClusterPausedReason, randomString())
+		Expect(len(clusterSummary.Status.Conditions)).To(Equal(1))
+		condition := clusterSummary.Status.Conditions[0]
+		Expect(condition.Type).To(Equal(configv1beta1.PausedCondition))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(configv1beta1.ClusterPausedReason))
+		Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+		Expect(condition.LastTransitionTime.IsZero()).To(BeFalse())
+
+		scope.RemoveCondition(configv1beta1.PausedCondition)
+		Expect(clusterSummary.Status.Conditions).To(BeEmpty())
+	})
+
 	It("SetLastAppliedTime updates featureSummary with time (entry not existing yet)", func() {
 		params := &scope.ClusterSummaryScopeParams{
 			Client:         c,
@@ -0,0 +1,151 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Set is a set of comparable entries. It is safe for concurrent use.
+// The zero value is an empty set ready to use.
+type Set[T comparable] struct {
+	mux  sync.RWMutex
+	data map[T]struct{}
+}
+
+// New returns a Set containing entries
+func New[T comparable](entries ...T) *Set[T] {
+	s := &Set[T]{}
+	for i := range entries {
+		s.Insert(entries[i])
+	}
+	return s
+}
+
+// Insert adds entry to set
+func (s *Set[T]) Insert(entry T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.data == nil {
+		s.data = make(map[T]struct{})
+	}
+	s.data[entry] = struct{}{}
+}
+
+// Append adds all entries in other to set
+func (s *Set[T]) Append(other *Set[T]) {
+	if other == nil || other == s {
+		return
+	}
+
+	items := other.Items()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.data == nil {
+		s.data = make(map[T]struct{}, len(items))
+	}
+	for i := range items {
+		s.data[items[i]] = struct{}{}
+	}
+}
+
+// Erase removes entry from set
+func (s *Set[T]) Erase(entry T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.data, entry)
+}
+
+// Has returns true if entry is currently part of set
+func (s *Set[T]) Has(entry T) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	_, ok := s.data[entry]
+	return ok
+}
+
+// Len returns length of set
+func (s *Set[T]) Len() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return len(s.data)
+}
+
+// Items returns a slice with all entries currently in set
+func (s *Set[T]) Items() []T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	items := make([]T, 0, len(s.data))
+	for k := range s.data {
+		items = append(items, k)
+	}
+	return items
+}
+
+// Difference returns all entries which are in s but not in other
+func (s *Set[T]) Difference(other *Set[T]) []T {
+	items := s.Items()
+
+	results := make([]T, 0, len(items))
+	for i := range items {
+		if other == nil || !other.Has(items[i]) {
+			results = append(results, items[i])
+		}
+	}
+	return results
+}
+
+// ObjectKey identifies a Kubernetes resource
+type ObjectKey struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// ObjectSet is a set of Kubernetes resources
+type ObjectSet = Set[ObjectKey]
+
+// KeyFromObjectReference returns the ObjectKey for ref. Fields other than apiVersion,
+// kind, namespace and name are ignored.
+func KeyFromObjectReference(ref *corev1.ObjectReference) ObjectKey {
+	return ObjectKey{
+		APIVersion: ref.APIVersion,
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+	}
+}
+
+// ObjectReference returns the corev1.ObjectReference for k
+func (k ObjectKey) ObjectReference() corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: k.APIVersion,
+		Kind:       k.Kind,
+		Namespace:  k.Namespace,
+		Name:       k.Name,
+	}
+}
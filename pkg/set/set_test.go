@@ -0,0 +1,92 @@
+/*
+Copyright 2024. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectsveltos/addon-controller/pkg/set"
+)
+
+var _ = Describe("Set", func() {
+	It("Insert, Has, Erase and Len manage entries", func() {
+		s := &set.ObjectSet{}
+		key := set.ObjectKey{Kind: randomString(), Namespace: randomString(), Name: randomString()}
+
+		Expect(s.Has(key)).To(BeFalse())
+		s.Insert(key)
+		s.Insert(key)
+		Expect(s.Has(key)).To(BeTrue())
+		Expect(s.Len()).To(Equal(1))
+
+		s.Erase(key)
+		Expect(s.Has(key)).To(BeFalse())
+		Expect(s.Len()).To(BeZero())
+	})
+
+	It("Append and Difference operate on sets", func() {
+		s1 := set.New("a", "b")
+		s2 := set.New("b", "c")
+
+		Expect(s1.Difference(s2)).To(ConsistOf("a"))
+		Expect(s2.Difference(s1)).To(ConsistOf("c"))
+
+		s1.Append(s2)
+		Expect(s1.Items()).To(ConsistOf("a", "b", "c"))
+		Expect(s1.Difference(s2)).To(ConsistOf("a"))
+	})
+
+	It("KeyFromObjectReference ignores fields other than apiVersion, kind, namespace and name", func() {
+		ref := corev1.ObjectReference{
+			APIVersion:      randomString(),
+			Kind:            randomString(),
+			Namespace:       randomString(),
+			Name:            randomString(),
+			ResourceVersion: randomString(),
+		}
+
+		key := set.KeyFromObjectReference(&ref)
+		ref.ResourceVersion = randomString()
+		Expect(set.KeyFromObjectReference(&ref)).To(Equal(key))
+
+		ref.ResourceVersion = ""
+		Expect(key.ObjectReference()).To(Equal(ref))
+	})
+
+	It("Set is safe for concurrent use", func() {
+		s := &set.Set[int]{}
+		const entries = 100
+
+		var wg sync.WaitGroup
+		for i := 0; i < entries; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Insert(i)
+				_ = s.Has(i)
+				_ = s.Items()
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(s.Len()).To(Equal(entries))
+	})
+})